@@ -0,0 +1,305 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/kniren/gota/data-frame"
+)
+
+// list is a flag that can be given several times
+type list []string
+
+func (l *list) String() string {
+	return strings.Join(*l, " ")
+}
+
+func (l *list) Set(s string) error {
+	*l = append(*l, s)
+	return nil
+}
+
+// splitList splits a comma separated list of column names
+func splitList(s string) []string {
+	var ret []string
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			ret = append(ret, v)
+		}
+	}
+	return ret
+}
+
+// orEmpty returns a function that takes the result of an operation that
+// selects rows of d, and replaces it with d without rows if the operation
+// failed because it selected none:
+//
+//	d, err = orEmpty(d)(d.Unique())
+func orEmpty(d *df.DataFrame) func(*df.DataFrame, error) (*df.DataFrame, error) {
+	return func(ret *df.DataFrame, err error) (*df.DataFrame, error) {
+		if err == df.ErrEmptySubset {
+			return d.Empty(), nil
+		}
+		return ret, err
+	}
+}
+
+func runHead(e *env, args []string) error {
+	fs, f := e.flags("head")
+	n := fs.Int("n", 10, "number of rows")
+	files, err := e.parse(fs, f, args)
+	if err != nil {
+		return err
+	}
+	d, format, err := e.input(files, f)
+	if err != nil {
+		return err
+	}
+	if d, err = head(d, *n); err != nil {
+		return err
+	}
+	return e.write(d, f.output(format))
+}
+
+// head returns the first n rows of d
+func head(d *df.DataFrame, n int) (*df.DataFrame, error) {
+	if n < 0 {
+		return nil, errors.New("Negative number of rows")
+	}
+	if n >= d.NRows() {
+		return d, nil
+	}
+	return orEmpty(d)(d.SubsetRows(df.R{0, n}))
+}
+
+func runSchema(e *env, args []string) error {
+	fs, f := e.flags("schema")
+	files, err := e.parse(fs, f, args)
+	if err != nil {
+		return err
+	}
+	d, format, err := e.input(files, f)
+	if err != nil {
+		return err
+	}
+	if d, err = schema(d); err != nil {
+		return err
+	}
+	return e.write(d, f.output(format))
+}
+
+// schema returns a DataFrame with the name and the type of each column of d
+func schema(d *df.DataFrame) (*df.DataFrame, error) {
+	names := d.Names()
+	types := d.InferTypes()
+	cells := make([]string, 0, len(names))
+	for _, k := range names {
+		cells = append(cells, types[k])
+	}
+	return df.New(
+		df.C{"Column", df.Strings(names)},
+		df.C{"Type", df.Strings(cells)},
+	)
+}
+
+func runDescribe(e *env, args []string) error {
+	fs, f := e.flags("describe")
+	files, err := e.parse(fs, f, args)
+	if err != nil {
+		return err
+	}
+	d, format, err := e.input(files, f)
+	if err != nil {
+		return err
+	}
+	if d, err = d.Describe(); err != nil {
+		return err
+	}
+	return e.write(d, f.output(format))
+}
+
+func runFilter(e *env, args []string) error {
+	fs, f := e.flags("filter")
+	var where list
+	fs.Var(&where, "where", "condition `column op value`, with op one of <, >, <=, >= or ==; can be repeated")
+	files, err := e.parse(fs, f, args)
+	if err != nil {
+		return err
+	}
+	if len(where) == 0 {
+		return errors.New("No conditions given")
+	}
+	conds, err := df.ParseConditions(where)
+	if err != nil {
+		return err
+	}
+	d, format, err := e.input(files, f)
+	if err != nil {
+		return err
+	}
+	if d, err = orEmpty(d)(d.ConditionRows(conds)); err != nil {
+		return err
+	}
+	return e.write(d, f.output(format))
+}
+
+func runSelect(e *env, args []string) error {
+	fs, f := e.flags("select")
+	cols := fs.String("c", "", "comma separated list of columns")
+	files, err := e.parse(fs, f, args)
+	if err != nil {
+		return err
+	}
+	names := splitList(*cols)
+	if len(names) == 0 {
+		return errors.New("No columns given")
+	}
+	d, format, err := e.input(files, f)
+	if err != nil {
+		return err
+	}
+	if d, err = d.SelectColumns(names...); err != nil {
+		return err
+	}
+	return e.write(d, f.output(format))
+}
+
+func runSort(e *env, args []string) error {
+	fs, f := e.flags("sort")
+	by := fs.String("by", "", "comma separated list of columns, prefixed with - to sort them in descending order")
+	naFirst := fs.Bool("nafirst", false, "place NA elements first")
+	files, err := e.parse(fs, f, args)
+	if err != nil {
+		return err
+	}
+	cols := splitList(*by)
+	if len(cols) == 0 {
+		return errors.New("No columns given")
+	}
+	d, format, err := e.input(files, f)
+	if err != nil {
+		return err
+	}
+	if d, err = d.Arrange(sortKeys(cols, *naFirst)...); err != nil {
+		return err
+	}
+	return e.write(d, f.output(format))
+}
+
+// sortKeys returns the keys to sort by the given columns, in descending
+// order for the ones prefixed with -
+func sortKeys(cols []string, naFirst bool) []df.SortKey {
+	keys := make([]df.SortKey, 0, len(cols))
+	for _, k := range cols {
+		key := df.SortKey{Column: k, NAFirst: naFirst}
+		if strings.HasPrefix(k, "-") {
+			key.Column, key.Desc = k[1:], true
+		}
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+func runUniq(e *env, args []string) error {
+	fs, f := e.flags("uniq")
+	unique := fs.Bool("u", false, "only keep the rows that appear once")
+	duplicated := fs.Bool("d", false, "only keep the repetitions of rows that appear more than once")
+	files, err := e.parse(fs, f, args)
+	if err != nil {
+		return err
+	}
+	if *unique && *duplicated {
+		return errors.New("Flags -u and -d can't be used together")
+	}
+	d, format, err := e.input(files, f)
+	if err != nil {
+		return err
+	}
+	opts := df.DuplicatesOptions{KeepOrder: true}
+	switch {
+	case *unique:
+		d, err = orEmpty(d)(d.Unique(opts))
+	case *duplicated:
+		d, err = orEmpty(d)(d.Duplicated(opts))
+	default:
+		d, err = orEmpty(d)(d.RemoveDuplicated(opts))
+	}
+	if err != nil {
+		return err
+	}
+	return e.write(d, f.output(format))
+}
+
+func runJoin(e *env, args []string) error {
+	fs, f := e.flags("join")
+	on := fs.String("on", "", "comma separated list of key columns")
+	how := fs.String("how", "inner", "type of join: inner, left, right or cross")
+	files, err := e.parse(fs, f, args)
+	if err != nil {
+		return err
+	}
+	if len(files) != 2 {
+		return errors.New("Two inputs are needed")
+	}
+	if files[0] == "-" && files[1] == "-" {
+		return errors.New("Only one input can be read from the standard input")
+	}
+	a, format, err := e.load(files[0], f.in)
+	if err != nil {
+		return err
+	}
+	b, _, err := e.load(files[1], f.in)
+	if err != nil {
+		return err
+	}
+	d, err := join(a, b, *how, splitList(*on))
+	if err != nil {
+		return err
+	}
+	return e.write(d, f.output(format))
+}
+
+// join joins a and b by the key columns. The type of join is one of inner,
+// left, right or cross, which doesn't take keys.
+func join(a, b *df.DataFrame, how string, keys []string) (*df.DataFrame, error) {
+	if how == "cross" {
+		if len(keys) > 0 {
+			return nil, errors.New("Cross joins don't take key columns")
+		}
+		return df.CrossJoin(*a, *b)
+	}
+	if len(keys) == 0 {
+		return nil, errors.New("No key columns given")
+	}
+	switch how {
+	case "inner":
+		return df.InnerJoin(*a, *b, keys...)
+	case "left":
+		return df.LeftJoin(*a, *b, keys...)
+	case "right":
+		return df.RightJoin(*a, *b, keys...)
+	}
+	return nil, fmt.Errorf("Unknown join type %q", how)
+}
+
+func runConvert(e *env, args []string) error {
+	fs, f := e.flags("convert")
+	files, err := e.parse(fs, f, args)
+	if err != nil {
+		return err
+	}
+	d, format, err := e.input(files, f)
+	if err != nil {
+		return err
+	}
+	// Without an output format the data is converted to the other one
+	out := f.out
+	if out == "" {
+		out = "json"
+		if format == "json" {
+			out = "csv"
+		}
+	}
+	return e.write(d, out)
+}
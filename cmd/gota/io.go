@@ -0,0 +1,336 @@
+package main
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/kniren/gota/data-frame"
+)
+
+// ioFlags are the input and output formats, shared by all the commands
+type ioFlags struct {
+	in, out string
+}
+
+// output returns the output format, which is the input one if none was given
+func (f ioFlags) output(input string) string {
+	if f.out != "" {
+		return f.out
+	}
+	return input
+}
+
+// flags returns the flag set of a command with the -in and -out flags
+func (e *env) flags(name string) (*flag.FlagSet, *ioFlags) {
+	f := &ioFlags{}
+	fs := flag.NewFlagSet("gota "+name, flag.ContinueOnError)
+	fs.SetOutput(e.stderr)
+	fs.StringVar(&f.in, "in", "", "input format: csv or json (default: guessed)")
+	fs.StringVar(&f.out, "out", "", "output format: csv, json, table, markdown, html or latex (default: the input one)")
+	fs.Usage = func() {
+		c := commands[name]
+		fmt.Fprintf(e.stderr, "Usage: gota %s %s\n\n%s\n\nFlags:\n", name, c.args, c.help)
+		fs.PrintDefaults()
+	}
+	return fs, f
+}
+
+// parse parses the flags of a command, which can be given before or after
+// its arguments, and checks the formats. It returns the arguments.
+func (e *env) parse(fs *flag.FlagSet, f *ioFlags, args []string) ([]string, error) {
+	var files []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			if err == flag.ErrHelp {
+				return nil, err
+			}
+			return nil, usageError{}
+		}
+		if fs.NArg() == 0 {
+			break
+		}
+		// Everything after "--" is an argument
+		if n := len(args) - fs.NArg(); n > 0 && args[n-1] == "--" {
+			files = append(files, fs.Args()...)
+			break
+		}
+		files = append(files, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+	switch f.in {
+	case "", "csv", "json":
+	default:
+		return nil, fmt.Errorf("Unknown input format %q", f.in)
+	}
+	switch f.out {
+	case "", "csv", "json", "table", "markdown", "html", "latex":
+	default:
+		return nil, fmt.Errorf("Unknown output format %q", f.out)
+	}
+	return files, nil
+}
+
+// input loads the only input file of a command, or the standard input if
+// there are no arguments
+func (e *env) input(files []string, f *ioFlags) (*df.DataFrame, string, error) {
+	switch len(files) {
+	case 0:
+		return e.load("-", f.in)
+	case 1:
+		return e.load(files[0], f.in)
+	}
+	return nil, "", errors.New("Too many arguments")
+}
+
+// load reads a DataFrame from the given file, or from the standard input if
+// path is "-". It returns the format of the data, which is guessed if none
+// is given.
+func (e *env) load(path, format string) (*df.DataFrame, string, error) {
+	r := e.stdin
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, "", err
+		}
+		defer f.Close()
+		r = f
+		if format == "" {
+			switch strings.ToLower(filepath.Ext(path)) {
+			case ".csv":
+				format = "csv"
+			case ".json":
+				format = "json"
+			}
+		}
+	}
+	br := bufio.NewReader(r)
+	if format == "" {
+		format = sniff(br)
+	}
+
+	var names []string
+	var rows [][]interface{}
+	var err error
+	if format == "json" {
+		names, rows, err = readJson(br)
+	} else {
+		names, rows, err = readCsv(br)
+	}
+	if err != nil {
+		return nil, "", err
+	}
+	d, err := newFrame(names, rows)
+	return d, format, err
+}
+
+// sniff guesses the format of the data from its first non blank character,
+// which is '[' on JSON
+func sniff(br *bufio.Reader) string {
+	for i := 1; i <= br.Size(); i++ {
+		b, _ := br.Peek(i)
+		if len(b) < i {
+			break
+		}
+		switch b[i-1] {
+		case ' ', '\t', '\r', '\n':
+			continue
+		case '[':
+			return "json"
+		}
+		break
+	}
+	return "csv"
+}
+
+// readCsv reads CSV data with a header. "NA" fields are NA elements.
+func readCsv(r io.Reader) ([]string, [][]interface{}, error) {
+	records, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(records) == 0 {
+		return nil, nil, errors.New("Empty input")
+	}
+	rows := make([][]interface{}, 0, len(records)-1)
+	for _, record := range records[1:] {
+		row := make([]interface{}, len(record))
+		for j, v := range record {
+			if v != "NA" {
+				row[j] = v
+			}
+		}
+		rows = append(rows, row)
+	}
+	return records[0], rows, nil
+}
+
+// readJson reads a JSON array of objects, one per row. The columns are the
+// keys of the objects in order of appearance, null values and missing keys
+// are NA elements and nested objects and arrays are kept as JSON text.
+func readJson(r io.Reader) ([]string, [][]interface{}, error) {
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+	if err := expectDelim(dec, '['); err != nil {
+		return nil, nil, err
+	}
+	var names []string
+	var rows [][]interface{}
+	index := map[string]int{}
+	for dec.More() {
+		if err := expectDelim(dec, '{'); err != nil {
+			return nil, nil, err
+		}
+		row := make([]interface{}, len(names))
+		for dec.More() {
+			t, err := dec.Token()
+			if err != nil {
+				return nil, nil, err
+			}
+			key := t.(string)
+			var v interface{}
+			if err := dec.Decode(&v); err != nil {
+				return nil, nil, err
+			}
+			j, ok := index[key]
+			if !ok {
+				j = len(names)
+				index[key] = j
+				names = append(names, key)
+			}
+			for len(row) <= j {
+				row = append(row, nil)
+			}
+			if row[j], err = jsonString(v); err != nil {
+				return nil, nil, err
+			}
+		}
+		if err := expectDelim(dec, '}'); err != nil {
+			return nil, nil, err
+		}
+		rows = append(rows, row)
+	}
+	if err := expectDelim(dec, ']'); err != nil {
+		return nil, nil, err
+	}
+	if len(names) == 0 {
+		return nil, nil, errors.New("Empty input")
+	}
+	return names, rows, nil
+}
+
+// expectDelim reads the next token of the JSON data, which must be the given
+// delimiter
+func expectDelim(dec *json.Decoder, delim json.Delim) error {
+	t, err := dec.Token()
+	if err == io.EOF {
+		return errors.New("Unexpected end of JSON input")
+	}
+	if err != nil {
+		return err
+	}
+	if d, ok := t.(json.Delim); !ok || d != delim {
+		return fmt.Errorf("Invalid JSON input: expected %v, found %v", delim, t)
+	}
+	return nil
+}
+
+// jsonString returns a decoded JSON value as a string, or nil for null
+func jsonString(v interface{}) (interface{}, error) {
+	switch e := v.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		return e, nil
+	case json.Number:
+		return e.String(), nil
+	case bool:
+		return strconv.FormatBool(e), nil
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+// newFrame builds a DataFrame from rows of strings and nil values, which are
+// NA elements, and parses its columns with the inferred types. Rows can be
+// shorter than the header, and are completed with NA elements.
+func newFrame(names []string, rows [][]interface{}) (*df.DataFrame, error) {
+	seen := map[string]bool{}
+	for _, k := range names {
+		if seen[k] {
+			return nil, errors.New("Duplicated column names: " + k)
+		}
+		seen[k] = true
+	}
+	// A DataFrame without rows is built from a row of NA elements, which is
+	// then filtered out keeping the columns
+	empty := len(rows) == 0
+	if empty {
+		rows = [][]interface{}{nil}
+	}
+	cols := make([]df.C, 0, len(names))
+	for j, k := range names {
+		values := make([]interface{}, len(rows))
+		for i, row := range rows {
+			if j < len(row) {
+				values[i] = row[j]
+			}
+		}
+		cols = append(cols, df.C{k, df.Strings(values...)})
+	}
+	d, err := df.New(cols...)
+	if err != nil {
+		return nil, err
+	}
+	if err := d.Parse(d.InferTypes()); err != nil {
+		return nil, err
+	}
+	if empty {
+		return d.Empty(), nil
+	}
+	return d, nil
+}
+
+// encode returns the DataFrame in the given format
+func encode(d *df.DataFrame, format string) ([]byte, error) {
+	switch format {
+	case "csv":
+		return d.SaveCsv()
+	case "json":
+		b, err := d.SaveJson()
+		if err != nil {
+			return nil, err
+		}
+		return append(b, '\n'), nil
+	case "table":
+		return []byte(d.String()), nil
+	case "markdown":
+		return []byte(d.Markdown()), nil
+	case "html":
+		return []byte(d.HTML()), nil
+	case "latex":
+		return []byte(d.LaTeX(df.LaTeXOptions{Booktabs: true})), nil
+	}
+	return nil, fmt.Errorf("Unknown output format %q", format)
+}
+
+// write writes the DataFrame to the standard output with the given format
+func (e *env) write(d *df.DataFrame, format string) error {
+	b, err := encode(d, format)
+	if err != nil {
+		return err
+	}
+	_, err = e.stdout.Write(b)
+	return err
+}
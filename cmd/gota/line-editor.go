@@ -0,0 +1,260 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// completer returns the candidates to complete the word that ends at the end
+// of line, and the position where that word starts
+type completer func(line string) (start int, candidates []string)
+
+// lineEditor reads lines from a terminal in raw mode. Lines can be edited
+// with the arrow keys and the usual Emacs bindings, the up and down keys move
+// through the history and tab completes the current word.
+type lineEditor struct {
+	in       *bufio.Reader
+	out      io.Writer
+	prompt   string
+	history  []string
+	complete completer
+
+	line    []rune
+	pos     int
+	lastTab bool
+}
+
+func newLineEditor(in io.Reader, out io.Writer, prompt string, complete completer) *lineEditor {
+	return &lineEditor{
+		in:       bufio.NewReader(in),
+		out:      out,
+		prompt:   prompt,
+		complete: complete,
+	}
+}
+
+// readLine reads a line. It returns io.EOF on ctrl-d over an empty line, and
+// ctrl-c discards the current line.
+func (l *lineEditor) readLine() (string, error) {
+	l.line, l.pos, l.lastTab = nil, 0, false
+	// hist is the position on the history, and current the line that was
+	// being written before moving through it
+	hist, current := len(l.history), ""
+	l.refresh()
+	for {
+		r, _, err := l.in.ReadRune()
+		if err != nil {
+			return "", err
+		}
+		tab := false
+		switch r {
+		case '\r', '\n':
+			fmt.Fprint(l.out, "\r\n")
+			return string(l.line), nil
+		case 3: // ctrl-c
+			fmt.Fprint(l.out, "^C\r\n")
+			l.line, l.pos = nil, 0
+			hist = len(l.history)
+		case 4: // ctrl-d
+			if len(l.line) == 0 {
+				fmt.Fprint(l.out, "\r\n")
+				return "", io.EOF
+			}
+			l.delete(l.pos, l.pos+1)
+		case 127, 8: // backspace
+			l.delete(l.pos-1, l.pos)
+		case 1: // ctrl-a
+			l.pos = 0
+		case 5: // ctrl-e
+			l.pos = len(l.line)
+		case 2: // ctrl-b
+			l.move(-1)
+		case 6: // ctrl-f
+			l.move(1)
+		case 11: // ctrl-k
+			l.delete(l.pos, len(l.line))
+		case 21: // ctrl-u
+			l.delete(0, l.pos)
+		case 23: // ctrl-w
+			start := l.pos
+			for start > 0 && unicode.IsSpace(l.line[start-1]) {
+				start--
+			}
+			for start > 0 && !unicode.IsSpace(l.line[start-1]) {
+				start--
+			}
+			l.delete(start, l.pos)
+		case 12: // ctrl-l
+			fmt.Fprint(l.out, "\x1b[H\x1b[2J")
+		case 16, 14: // ctrl-p, ctrl-n
+			hist, current = l.browse(r == 16, hist, current)
+		case '\t':
+			tab = true
+			l.tab()
+		case 27: // escape sequences
+			key, err := l.escape()
+			if err != nil {
+				return "", err
+			}
+			switch key {
+			case 'A', 'B':
+				hist, current = l.browse(key == 'A', hist, current)
+			case 'C':
+				l.move(1)
+			case 'D':
+				l.move(-1)
+			case 'H':
+				l.pos = 0
+			case 'F':
+				l.pos = len(l.line)
+			case '~':
+				l.delete(l.pos, l.pos+1)
+			}
+		default:
+			if unicode.IsPrint(r) {
+				l.insert(string(r))
+			}
+		}
+		l.lastTab = tab
+		l.refresh()
+	}
+}
+
+// escape reads the rest of an escape sequence and returns its final key:
+// A, B, C and D for the arrows, H and F for home and end and ~ for delete
+func (l *lineEditor) escape() (rune, error) {
+	r, _, err := l.in.ReadRune()
+	if err != nil || (r != '[' && r != 'O') {
+		return 0, err
+	}
+	var args []rune
+	for {
+		r, _, err = l.in.ReadRune()
+		if err != nil {
+			return 0, err
+		}
+		if (r < '0' || r > '9') && r != ';' {
+			break
+		}
+		args = append(args, r)
+	}
+	if r != '~' {
+		return r, nil
+	}
+	// Home, end and delete on terminals that send "\x1b[n~"
+	switch string(args) {
+	case "1", "7":
+		return 'H', nil
+	case "4", "8":
+		return 'F', nil
+	case "3":
+		return '~', nil
+	}
+	return 0, nil
+}
+
+// refresh redraws the line and places the cursor
+func (l *lineEditor) refresh() {
+	fmt.Fprintf(l.out, "\r%s%s\x1b[K", l.prompt, string(l.line))
+	if n := len(l.line) - l.pos; n > 0 {
+		fmt.Fprintf(l.out, "\x1b[%dD", n)
+	}
+}
+
+func (l *lineEditor) move(n int) {
+	if l.pos += n; l.pos < 0 {
+		l.pos = 0
+	} else if l.pos > len(l.line) {
+		l.pos = len(l.line)
+	}
+}
+
+func (l *lineEditor) insert(s string) {
+	rs := []rune(s)
+	line := make([]rune, 0, len(l.line)+len(rs))
+	line = append(line, l.line[:l.pos]...)
+	line = append(line, rs...)
+	l.line = append(line, l.line[l.pos:]...)
+	l.pos += len(rs)
+}
+
+// delete removes the runes between from and to, moving the cursor to from
+func (l *lineEditor) delete(from, to int) {
+	if from < 0 {
+		from = 0
+	}
+	if to > len(l.line) {
+		to = len(l.line)
+	}
+	if from >= to {
+		return
+	}
+	l.line = append(l.line[:from], l.line[to:]...)
+	l.pos = from
+}
+
+// browse replaces the line with the previous or next entry of the history,
+// going back to the line being written after the last one
+func (l *lineEditor) browse(back bool, hist int, current string) (int, string) {
+	if hist == len(l.history) {
+		current = string(l.line)
+	}
+	switch {
+	case back && hist > 0:
+		hist--
+	case !back && hist < len(l.history):
+		hist++
+	default:
+		return hist, current
+	}
+	s := current
+	if hist < len(l.history) {
+		s = l.history[hist]
+	}
+	l.line, l.pos = []rune(s), len([]rune(s))
+	return hist, current
+}
+
+// tab completes the word before the cursor. A single candidate is inserted
+// followed by a space, several ones are completed up to their common prefix
+// and listed if tab is pressed twice.
+func (l *lineEditor) tab() {
+	if l.complete == nil {
+		return
+	}
+	before := string(l.line[:l.pos])
+	start, candidates := l.complete(before)
+	if len(candidates) == 0 {
+		fmt.Fprint(l.out, "\a")
+		return
+	}
+	word := before[start:]
+	if len(candidates) == 1 {
+		l.insert(strings.TrimPrefix(candidates[0], word) + " ")
+		return
+	}
+	prefix := commonPrefix(candidates)
+	if len(prefix) > len(word) {
+		l.insert(strings.TrimPrefix(prefix, word))
+		return
+	}
+	if l.lastTab {
+		fmt.Fprintf(l.out, "\r\n%s\r\n", strings.Join(candidates, "  "))
+	}
+}
+
+// commonPrefix returns the longest prefix shared by all the strings
+func commonPrefix(ss []string) string {
+	prefix := ss[0]
+	for _, s := range ss[1:] {
+		for !strings.HasPrefix(s, prefix) {
+			_, size := utf8.DecodeLastRuneInString(prefix)
+			prefix = prefix[:len(prefix)-size]
+		}
+	}
+	return prefix
+}
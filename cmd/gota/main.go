@@ -0,0 +1,120 @@
+// Command gota inspects and transforms CSV and JSON data files with the
+// operations of the df package.
+//
+// Usage:
+//
+//	gota <command> [flags] [file ...]
+//
+// Every command reads its input from the given file or from the standard
+// input when the file is missing or is "-", and writes its result to the
+// standard output, so commands can be piped:
+//
+//	gota filter -where 'Age > 30' people.csv | gota sort -by -Amount | gota head -n 5
+//
+// The format of the input is taken from the file extension or guessed from
+// its content, and can be set with -in. Results are written in the format of
+// the input unless -out is given, which also accepts "table" to print them
+// aligned and "markdown", "html" and "latex" to write them as tables of those
+// formats. The types of the columns are inferred from their values, and NA
+// elements are read from "NA" on CSV and from null on JSON.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+)
+
+// command is a subcommand of gota
+type command struct {
+	args string
+	help string
+	run  func(e *env, args []string) error
+}
+
+var commands map[string]command
+
+func init() {
+	// Commands are set on init because they refer to the list for their usage
+	commands = map[string]command{
+		"head":     {"[-n rows] [file]", "print the first rows", runHead},
+		"schema":   {"[file]", "print the inferred type of each column", runSchema},
+		"describe": {"[file]", "print summary statistics of each column", runDescribe},
+		"filter":   {"-where condition [-where condition ...] [file]", "keep the rows that match all the conditions", runFilter},
+		"select":   {"-c col1,col2,... [file]", "keep the given columns, in the given order", runSelect},
+		"sort":     {"-by col1,-col2,... [-nafirst] [file]", "sort the rows, descending on columns prefixed with -", runSort},
+		"uniq":     {"[-u | -d] [file]", "remove duplicated rows, or keep only unique (-u) or duplicated (-d) ones", runUniq},
+		"join":     {"[-how inner|left|right|cross] -on key1,key2,... left right", "join two files by the key columns", runJoin},
+		"convert":  {"[-out csv|json] [file]", "convert between CSV and JSON", runConvert},
+		"repl":     {"[-history file] [[name=]file ...]", "explore the files interactively, loaded as named frames", runRepl},
+	}
+}
+
+// env is where a command reads its input and writes its output
+type env struct {
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run executes the command given by args and returns the exit status
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		usage(stderr)
+		return 2
+	}
+	name := args[0]
+	if name == "help" || name == "-h" || name == "-help" || name == "--help" {
+		usage(stdout)
+		return 0
+	}
+	c, ok := commands[name]
+	if !ok {
+		fmt.Fprintf(stderr, "gota: unknown command %q\n", name)
+		usage(stderr)
+		return 2
+	}
+	e := &env{stdin: stdin, stdout: stdout, stderr: stderr}
+	if err := c.run(e, args[1:]); err != nil {
+		if err == flag.ErrHelp {
+			return 0
+		}
+		if _, ok := err.(usageError); ok {
+			return 2
+		}
+		fmt.Fprintf(stderr, "gota %s: %v\n", name, err)
+		return 1
+	}
+	return 0
+}
+
+// usage prints the list of commands
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "Usage: gota <command> [flags] [file ...]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+	names := make([]string, 0, len(commands))
+	for k := range commands {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	for _, k := range names {
+		fmt.Fprintf(w, "  %-9s %s\n", k, commands[k].help)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Run gota <command> -h for the flags of a command.")
+}
+
+// usageError is returned when the flags of a command are invalid. The flag
+// package has already reported the error.
+type usageError struct{}
+
+func (usageError) Error() string {
+	return "invalid usage"
+}
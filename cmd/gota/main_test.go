@@ -0,0 +1,160 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const people = `Name,Age,Amount
+Alice,32,10.5
+Bob,NA,3
+Carol,41,7.25
+Bob,NA,3
+`
+
+func TestRun(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gota")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	cities := filepath.Join(dir, "cities.json")
+	err = ioutil.WriteFile(cities, []byte(`[{"Name":"Alice","City":"Rome"},{"Name":"Bob","City":null}]`), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	table := []struct {
+		args     []string
+		stdin    string
+		expected string
+	}{
+		{
+			[]string{"head", "-n", "2"},
+			people,
+			"Name,Age,Amount\nAlice,32,10.5\nBob,NA,3\n",
+		},
+		{
+			[]string{"head", "-n", "0"},
+			people,
+			"Name,Age,Amount\n",
+		},
+		{
+			[]string{"schema"},
+			people,
+			"Column,Type\nName,string\nAge,int\nAmount,float\n",
+		},
+		{
+			[]string{"filter", "-where", "Amount > 5", "-where", "Amount < 10"},
+			people,
+			"Name,Age,Amount\nCarol,41,7.25\n",
+		},
+		{
+			[]string{"filter", "-where", "Age > 50"},
+			people,
+			"Name,Age,Amount\n",
+		},
+		{
+			[]string{"select", "-c", "Amount,Name"},
+			people,
+			"Amount,Name\n10.5,Alice\n3,Bob\n7.25,Carol\n3,Bob\n",
+		},
+		{
+			[]string{"sort", "-by", "-Age", "-nafirst"},
+			people,
+			"Name,Age,Amount\nBob,NA,3\nBob,NA,3\nCarol,41,7.25\nAlice,32,10.5\n",
+		},
+		{
+			[]string{"uniq"},
+			people,
+			"Name,Age,Amount\nAlice,32,10.5\nBob,NA,3\nCarol,41,7.25\n",
+		},
+		{
+			[]string{"uniq", "-u"},
+			people,
+			"Name,Age,Amount\nAlice,32,10.5\nCarol,41,7.25\n",
+		},
+		{
+			[]string{"join", "-on", "Name", "-how", "left", "-", cities},
+			people,
+			"Name,Age,Amount,City\nAlice,32,10.5,Rome\nBob,NA,3,NA\nCarol,41,7.25,NA\nBob,NA,3,NA\n",
+		},
+		{
+			[]string{"convert"},
+			people,
+			`[{"Name":"Alice","Age":32,"Amount":10.5},{"Name":"Bob","Age":null,"Amount":3},` +
+				`{"Name":"Carol","Age":41,"Amount":7.25},{"Name":"Bob","Age":null,"Amount":3}]` + "\n",
+		},
+		{
+			[]string{"convert", cities},
+			"",
+			"Name,City\nAlice,Rome\nBob,NA\n",
+		},
+		{
+			[]string{"head", cities, "-out", "json"},
+			"",
+			`[{"Name":"Alice","City":"Rome"},{"Name":"Bob","City":null}]` + "\n",
+		},
+		{
+			[]string{"head", cities, "-out", "markdown"},
+			"",
+			"| Name  | City |\n| ----- | ---- |\n| Alice | Rome |\n| Bob   | NA   |\n",
+		},
+		{
+			[]string{"head"},
+			"Name,Age\n",
+			"Name,Age\n",
+		},
+	}
+	for k, v := range table {
+		var stdout, stderr bytes.Buffer
+		status := run(v.args, strings.NewReader(v.stdin), &stdout, &stderr)
+		if status != 0 {
+			t.Error("Test", k, "failed:", stderr.String())
+			continue
+		}
+		received := stdout.String()
+		if v.expected != received {
+			t.Error(
+				"Test", k, "\n",
+				"Expected:\n",
+				v.expected, "\n",
+				"Received:\n",
+				received,
+			)
+		}
+	}
+}
+
+func TestRun_Errors(t *testing.T) {
+	table := []struct {
+		args     []string
+		status   int
+		expected string
+	}{
+		{[]string{"nope"}, 2, `gota: unknown command "nope"`},
+		{[]string{"head", "-x"}, 2, "flag provided but not defined: -x"},
+		{[]string{"head", "-out", "xml"}, 1, `gota head: Unknown output format "xml"`},
+		{[]string{"filter", "-where", "Age != 3"}, 1, `gota filter: Invalid operation "!=" in condition "Age != 3"`},
+		{[]string{"select", "-c", "Nope"}, 1, "gota select: Column not found: Nope"},
+		{[]string{"uniq", "-u", "-d"}, 1, "gota uniq: Flags -u and -d can't be used together"},
+		{[]string{"join", "-on", "Name", "-", "-"}, 1, "gota join: Only one input can be read from the standard input"},
+	}
+	for k, v := range table {
+		var stdout, stderr bytes.Buffer
+		status := run(v.args, strings.NewReader(people), &stdout, &stderr)
+		if status != v.status || !strings.Contains(stderr.String(), v.expected) {
+			t.Error(
+				"Test", k, "\n",
+				"Expected:\n",
+				v.status, v.expected, "\n",
+				"Received:\n",
+				status, stderr.String(),
+			)
+		}
+	}
+}
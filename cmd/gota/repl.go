@@ -0,0 +1,835 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/kniren/gota/data-frame"
+)
+
+// The REPL evaluates statements over named DataFrames. A statement is either
+// a command that manages the session, like load or frames, or a pipeline of
+// stages that transform a DataFrame, optionally assigned to a name:
+//
+//	load d people.csv
+//	adults = filter d Age >= 18 | sort -Amount
+//	groupby adults City | agg mean(Amount), count(*) as n
+//
+// The first stage of a pipeline takes the name of the DataFrame as its first
+// argument, or is the name itself, and the next ones transform the result of
+// the previous stage. The result of a pipeline is printed unless it is
+// assigned.
+
+// stage is a command that can be part of a pipeline
+type stage struct {
+	args string
+	help string
+	run  func(s *session, in value, args string) (value, error)
+}
+
+// value is the result of a stage: a DataFrame, which can be grouped by some
+// of its columns, or nothing after save
+type value struct {
+	d       *df.DataFrame
+	grouped bool
+	groupBy []string
+}
+
+// statement is a command that manages the session and can't be piped
+type statement struct {
+	args string
+	help string
+	run  func(s *session, args []string) error
+}
+
+var (
+	stages     map[string]stage
+	statements map[string]statement
+)
+
+func init() {
+	stages = map[string]stage{
+		"head":     {"[n]", "first n rows, 10 by default", stageHead},
+		"filter":   {"expr", "rows where the expression is true, as on Mutate", stageFilter},
+		"select":   {"col, ...", "the given columns", stageSelect},
+		"sort":     {"[-]col, ... [nafirst]", "rows sorted by the columns, descending with -", stageSort},
+		"mutate":   {"col = expr", "add or replace a column with an expression", stageMutate},
+		"groupby":  {"col, ...", "group the rows for agg", stageGroupBy},
+		"agg":      {"f(col) [as name], ...", "aggregate the groups with count, sum, mean, min, max, std, first or last", stageAgg},
+		"uniq":     {"[-u | -d]", "rows without duplicates, or only unique (-u) or duplicated (-d) ones", stageUniq},
+		"join":     {"frame [how] [on] col, ...", "join with another frame, how is inner, left, right or cross", stageJoin},
+		"describe": {"", "summary statistics of each column", stageDescribe},
+		"schema":   {"", "inferred type of each column", stageSchema},
+		"save":     {"path", "save to a file, as CSV unless its extension is .json, .md, .html or .tex", stageSave},
+	}
+	statements = map[string]statement{
+		"load":    {"name path", "load a CSV or JSON file as a frame", stmtLoad},
+		"frames":  {"", "list the frames", stmtFrames},
+		"drop":    {"name", "remove a frame", stmtDrop},
+		"history": {"", "print the history", stmtHistory},
+		"help":    {"", "print this help", stmtHelp},
+		"quit":    {"", "exit, also exit or ctrl-d", nil},
+	}
+}
+
+// errQuit is returned by exec when the session is over
+var errQuit = errors.New("quit")
+
+var (
+	// validName matches the names of the frames
+	validName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+	// assignment matches the name a pipeline is assigned to, followed by the
+	// pipeline
+	assignment = regexp.MustCompile(`^\s*([A-Za-z_][A-Za-z0-9_]*)\s*=($|[^=])`)
+)
+
+// session is the state of the REPL
+type session struct {
+	e       *env
+	in      string
+	out     string
+	frames  map[string]*df.DataFrame
+	history []string
+}
+
+func newSession(e *env, in, out string) *session {
+	if out == "" {
+		out = "table"
+	}
+	return &session{e: e, in: in, out: out, frames: map[string]*df.DataFrame{}}
+}
+
+func runRepl(e *env, args []string) error {
+	fs, f := e.flags("repl")
+	historyFile := ""
+	if home := os.Getenv("HOME"); home != "" {
+		historyFile = filepath.Join(home, ".gota_history")
+	}
+	fs.StringVar(&historyFile, "history", historyFile, "file that keeps the history of interactive sessions, empty to disable it")
+	files, err := e.parse(fs, f, args)
+	if err != nil {
+		return err
+	}
+	s := newSession(e, f.in, f.out)
+	for _, v := range files {
+		name, path := frameName(v)
+		if err := s.load(name, path); err != nil {
+			return err
+		}
+	}
+	if file, ok := e.stdin.(*os.File); ok && isTerminal(file.Fd()) {
+		return s.interactive(file, historyFile)
+	}
+	return s.script(e.stdin)
+}
+
+// frameName returns the name and the path of a file given as name=path, or
+// named after its base name otherwise
+func frameName(arg string) (string, string) {
+	if i := strings.Index(arg, "="); i > 0 {
+		return arg[:i], arg[i+1:]
+	}
+	base := filepath.Base(arg)
+	base = strings.TrimSuffix(base, filepath.Ext(base))
+	name := []rune(base)
+	for i, r := range name {
+		if !(r == '_' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9') {
+			name[i] = '_'
+		}
+	}
+	if len(name) == 0 || name[0] >= '0' && name[0] <= '9' {
+		name = append([]rune{'_'}, name...)
+	}
+	return string(name), arg
+}
+
+// script runs the statements read from r, one per line, reporting the
+// errors and going on with the next ones
+func (s *session) script(r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		s.addHistory(line)
+		if err := s.exec(line); err == errQuit {
+			return nil
+		} else if err != nil {
+			fmt.Fprintln(s.e.stderr, "error:", err)
+		}
+	}
+	return scanner.Err()
+}
+
+// interactive runs the statements typed on the terminal with line editing,
+// keeping the history on historyFile
+func (s *session) interactive(terminal *os.File, historyFile string) error {
+	if historyFile != "" {
+		s.history = readHistory(historyFile)
+	}
+	fmt.Fprintln(s.e.stdout, `Type "help" for the list of commands.`)
+	editor := newLineEditor(terminal, s.e.stdout, "gota> ", s.complete)
+	for {
+		editor.history = s.history
+		line, err := readRaw(terminal, editor)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if s.addHistory(line) && historyFile != "" {
+			appendHistory(historyFile, line)
+		}
+		if err := s.exec(line); err == errQuit {
+			return nil
+		} else if err != nil {
+			fmt.Fprintln(s.e.stderr, "error:", err)
+		}
+	}
+}
+
+// readRaw reads a line with the terminal in raw mode, restoring it afterwards
+func readRaw(terminal *os.File, editor *lineEditor) (string, error) {
+	restore, err := makeRaw(terminal.Fd())
+	if err != nil {
+		return "", err
+	}
+	defer restore()
+	return editor.readLine()
+}
+
+// maxHistory is the number of lines of history read from the file
+const maxHistory = 1000
+
+// readHistory returns the last lines of the history file
+func readHistory(path string) []string {
+	b, err := ioutil.ReadFile(path)
+	if err != nil || len(b) == 0 {
+		return nil
+	}
+	lines := strings.Split(strings.TrimRight(string(b), "\n"), "\n")
+	if len(lines) > maxHistory {
+		lines = lines[len(lines)-maxHistory:]
+	}
+	return lines
+}
+
+// appendHistory adds a line to the history file. Errors are ignored, as the
+// history is not essential for the session.
+func appendHistory(path, line string) {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		return
+	}
+	fmt.Fprintln(f, line)
+	f.Close()
+}
+
+// addHistory adds a line to the history unless it is blank or repeats the
+// last one, and returns whether it was added
+func (s *session) addHistory(line string) bool {
+	if strings.TrimSpace(line) == "" {
+		return false
+	}
+	if n := len(s.history); n > 0 && s.history[n-1] == line {
+		return false
+	}
+	s.history = append(s.history, line)
+	return true
+}
+
+// exec runs a statement
+func (s *session) exec(line string) error {
+	line = strings.TrimSpace(line)
+	if line == "" || strings.HasPrefix(line, "#") {
+		return nil
+	}
+	fields := strings.Fields(line)
+	switch fields[0] {
+	case "quit", "exit":
+		return errQuit
+	}
+	if st, ok := statements[fields[0]]; ok {
+		return st.run(s, fields[1:])
+	}
+
+	target := ""
+	if m := assignment.FindStringSubmatchIndex(line); m != nil {
+		target, line = line[m[2]:m[3]], line[m[4]:]
+		if err := checkName(target); err != nil {
+			return err
+		}
+	}
+	v, err := s.pipeline(line)
+	if err != nil {
+		return err
+	}
+	if v.d == nil {
+		if target != "" {
+			return errors.New("Nothing to assign")
+		}
+		return nil
+	}
+	if target != "" {
+		s.frames[target] = v.d
+		dim := v.d.Dim()
+		fmt.Fprintf(s.e.stdout, "%s: %d rows x %d columns\n", target, dim[0], dim[1])
+		return nil
+	}
+	return s.e.write(v.d, s.out)
+}
+
+// pipeline runs the stages of a pipeline and returns the result of the last
+// one
+func (s *session) pipeline(line string) (value, error) {
+	var v value
+	for i, text := range splitPipes(line) {
+		name, args := splitWord(text)
+		if name == "" {
+			return value{}, errors.New("Empty stage")
+		}
+		if i > 0 && v.d == nil {
+			return value{}, errors.New("save must be the last stage")
+		}
+		st, ok := stages[name]
+		if i == 0 {
+			if d, isFrame := s.frames[name]; isFrame && !ok {
+				if args != "" {
+					return value{}, fmt.Errorf("Unexpected %q after frame %s", args, name)
+				}
+				v = value{d: d}
+				continue
+			}
+		}
+		if !ok {
+			if _, isStatement := statements[name]; isStatement {
+				return value{}, fmt.Errorf("%s can't be part of a pipeline", name)
+			}
+			if i == 0 {
+				return value{}, fmt.Errorf("Unknown command or frame %q", name)
+			}
+			return value{}, fmt.Errorf("Unknown command %q", name)
+		}
+		if i == 0 {
+			var frame string
+			frame, args = splitWord(args)
+			d, isFrame := s.frames[frame]
+			if !isFrame {
+				if frame == "" {
+					return value{}, fmt.Errorf("%s needs a frame", name)
+				}
+				return value{}, fmt.Errorf("Unknown frame %q", frame)
+			}
+			v = value{d: d}
+		}
+		if v.grouped && name != "agg" {
+			return value{}, errors.New("groupby must be followed by agg")
+		}
+		var err error
+		if v, err = st.run(s, v, args); err != nil {
+			return value{}, fmt.Errorf("%s: %v", name, err)
+		}
+	}
+	if v.grouped {
+		return value{}, errors.New("groupby must be followed by agg")
+	}
+	return v, nil
+}
+
+// splitPipes splits a pipeline by the | that are not inside quotes or part
+// of the || operator
+func splitPipes(line string) []string {
+	var parts []string
+	var quote rune
+	start := 0
+	rs := []rune(line)
+	for i := 0; i < len(rs); i++ {
+		r := rs[i]
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '"' || r == '\'' || r == '`':
+			quote = r
+		case r == '|' && i+1 < len(rs) && rs[i+1] == '|':
+			i++
+		case r == '|':
+			parts = append(parts, string(rs[start:i]))
+			start = i + 1
+		}
+	}
+	return append(parts, string(rs[start:]))
+}
+
+// splitWord returns the first word of s and the rest of it, trimmed
+func splitWord(s string) (string, string) {
+	s = strings.TrimSpace(s)
+	i := strings.IndexFunc(s, func(r rune) bool { return r == ' ' || r == '\t' })
+	if i < 0 {
+		return s, ""
+	}
+	return s[:i], strings.TrimSpace(s[i:])
+}
+
+// columnList splits a list of column names separated by commas or spaces.
+// Names with spaces can be written between backquotes.
+func columnList(s string) ([]string, error) {
+	var names []string
+	var current []rune
+	quoted, inName := false, false
+	flush := func() {
+		if inName {
+			names = append(names, string(current))
+		}
+		current, inName = nil, false
+	}
+	for _, r := range s {
+		switch {
+		case r == '`':
+			if quoted {
+				flush()
+			}
+			quoted, inName = !quoted, true
+		case quoted:
+			current = append(current, r)
+		case r == ',' || r == ' ' || r == '\t':
+			flush()
+		default:
+			current, inName = append(current, r), true
+		}
+	}
+	if quoted {
+		return nil, errors.New("Unterminated `")
+	}
+	flush()
+	return names, nil
+}
+
+func stageHead(s *session, in value, args string) (value, error) {
+	n := 10
+	if args != "" {
+		var err error
+		if n, err = strconv.Atoi(args); err != nil {
+			return value{}, fmt.Errorf("Invalid number of rows %q", args)
+		}
+	}
+	d, err := head(in.d, n)
+	return value{d: d}, err
+}
+
+func stageFilter(s *session, in value, args string) (value, error) {
+	if args == "" {
+		return value{}, errors.New("No expression given")
+	}
+	d, err := in.d.Lazy().Filter(args).Collect()
+	return value{d: d}, err
+}
+
+func stageSelect(s *session, in value, args string) (value, error) {
+	names, err := columnList(args)
+	if err != nil {
+		return value{}, err
+	}
+	if len(names) == 0 {
+		return value{}, errors.New("No columns given")
+	}
+	d, err := in.d.SelectColumns(names...)
+	return value{d: d}, err
+}
+
+func stageSort(s *session, in value, args string) (value, error) {
+	cols, err := columnList(args)
+	if err != nil {
+		return value{}, err
+	}
+	naFirst := false
+	if n := len(cols); n > 0 && cols[n-1] == "nafirst" {
+		cols, naFirst = cols[:n-1], true
+	}
+	if len(cols) == 0 {
+		return value{}, errors.New("No columns given")
+	}
+	d, err := in.d.Arrange(sortKeys(cols, naFirst)...)
+	return value{d: d}, err
+}
+
+// mutation matches the column and the expression of mutate
+var mutation = regexp.MustCompile("^(`[^`]+`|[^\\s=`]+)\\s*=([^=].*)$")
+
+func stageMutate(s *session, in value, args string) (value, error) {
+	m := mutation.FindStringSubmatch(args)
+	if m == nil {
+		return value{}, errors.New("Expected col = expr")
+	}
+	d, err := in.d.Mutate(strings.Trim(m[1], "`"), strings.TrimSpace(m[2]))
+	return value{d: d}, err
+}
+
+func stageGroupBy(s *session, in value, args string) (value, error) {
+	cols, err := columnList(args)
+	if err != nil {
+		return value{}, err
+	}
+	if len(cols) == 0 {
+		return value{}, errors.New("No columns given")
+	}
+	for _, k := range cols {
+		if _, err := in.d.Column(k); err != nil {
+			return value{}, errors.New("Unknown column: " + k)
+		}
+	}
+	return value{d: in.d, grouped: true, groupBy: cols}, nil
+}
+
+// aggregation matches an aggregation of agg: a function over a column, *
+// for count, and an optional name
+var aggregation = regexp.MustCompile("^(\\w+)\\s*\\(\\s*(`[^`]+`|[^()\\s]*)\\s*\\)(?:\\s+(?i:as)\\s+(`[^`]+`|\\S+))?$")
+
+// aggFuncs are the aggregations by their name on agg
+var aggFuncs = map[string]df.Aggregation{
+	"count": df.AggCount, "sum": df.AggSum, "mean": df.AggMean,
+	"min": df.AggMin, "max": df.AggMax, "std": df.AggStd,
+	"first": df.AggFirst, "last": df.AggLast,
+}
+
+// countAll is the column added to count the rows with count(*)
+const countAll = "#rows"
+
+func stageAgg(s *session, in value, args string) (value, error) {
+	d := in.d
+	var aggs []df.Aggregate
+	for _, part := range splitTopLevel(args) {
+		m := aggregation.FindStringSubmatch(strings.TrimSpace(part))
+		if m == nil {
+			return value{}, fmt.Errorf("Invalid aggregation %q", strings.TrimSpace(part))
+		}
+		f, ok := aggFuncs[strings.ToLower(m[1])]
+		if !ok {
+			return value{}, fmt.Errorf("Unknown aggregation %q", m[1])
+		}
+		agg := df.Aggregate{Column: strings.Trim(m[2], "`"), Agg: f, Name: strings.Trim(m[3], "`")}
+		if agg.Column == "" || agg.Column == "*" {
+			if f != df.AggCount {
+				return value{}, fmt.Errorf("%s needs a column", m[1])
+			}
+			if _, err := d.Column(countAll); err != nil {
+				var err error
+				if d, err = d.Mutate(countAll, "1"); err != nil {
+					return value{}, err
+				}
+			}
+			agg.Column = countAll
+			if agg.Name == "" {
+				agg.Name = "count"
+			}
+		}
+		aggs = append(aggs, agg)
+	}
+	if len(aggs) == 0 {
+		return value{}, errors.New("No aggregations given")
+	}
+	ret, err := d.GroupBy(in.groupBy, aggs...)
+	return value{d: ret}, err
+}
+
+// splitTopLevel splits s by the commas outside parentheses and backquotes
+func splitTopLevel(s string) []string {
+	var parts []string
+	depth, quoted, start := 0, false, 0
+	for i, r := range s {
+		switch {
+		case r == '`':
+			quoted = !quoted
+		case quoted:
+		case r == '(':
+			depth++
+		case r == ')':
+			depth--
+		case r == ',' && depth == 0:
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+	if strings.TrimSpace(s[start:]) != "" || len(parts) > 0 {
+		parts = append(parts, s[start:])
+	}
+	return parts
+}
+
+func stageUniq(s *session, in value, args string) (value, error) {
+	opts := df.DuplicatesOptions{KeepOrder: true}
+	var d *df.DataFrame
+	var err error
+	switch args {
+	case "":
+		d, err = orEmpty(in.d)(in.d.RemoveDuplicated(opts))
+	case "-u":
+		d, err = orEmpty(in.d)(in.d.Unique(opts))
+	case "-d":
+		d, err = orEmpty(in.d)(in.d.Duplicated(opts))
+	default:
+		return value{}, fmt.Errorf("Unexpected %q", args)
+	}
+	return value{d: d}, err
+}
+
+func stageJoin(s *session, in value, args string) (value, error) {
+	name, args := splitWord(args)
+	other, ok := s.frames[name]
+	if !ok {
+		if name == "" {
+			return value{}, errors.New("No frame given")
+		}
+		return value{}, fmt.Errorf("Unknown frame %q", name)
+	}
+	how := "inner"
+	if w, rest := splitWord(args); w == "inner" || w == "left" || w == "right" || w == "cross" {
+		how, args = w, rest
+	}
+	if w, rest := splitWord(args); w == "on" {
+		args = rest
+	}
+	keys, err := columnList(args)
+	if err != nil {
+		return value{}, err
+	}
+	d, err := join(in.d, other, how, keys)
+	return value{d: d}, err
+}
+
+func stageDescribe(s *session, in value, args string) (value, error) {
+	d, err := in.d.Describe()
+	return value{d: d}, err
+}
+
+func stageSchema(s *session, in value, args string) (value, error) {
+	d, err := schema(in.d)
+	return value{d: d}, err
+}
+
+func stageSave(s *session, in value, args string) (value, error) {
+	if args == "" {
+		return value{}, errors.New("No path given")
+	}
+	format := "csv"
+	switch strings.ToLower(filepath.Ext(args)) {
+	case ".json":
+		format = "json"
+	case ".md":
+		format = "markdown"
+	case ".html", ".htm":
+		format = "html"
+	case ".tex":
+		format = "latex"
+	}
+	b, err := encode(in.d, format)
+	if err != nil {
+		return value{}, err
+	}
+	return value{}, ioutil.WriteFile(args, b, 0644)
+}
+
+func stmtLoad(s *session, args []string) error {
+	if len(args) != 2 {
+		return errors.New("Usage: load name path")
+	}
+	return s.load(args[0], args[1])
+}
+
+// load reads a file as a frame with the given name
+func (s *session) load(name, path string) error {
+	if err := checkName(name); err != nil {
+		return err
+	}
+	d, _, err := s.e.load(path, s.in)
+	if err != nil {
+		return err
+	}
+	s.frames[name] = d
+	dim := d.Dim()
+	fmt.Fprintf(s.e.stdout, "%s: %d rows x %d columns\n", name, dim[0], dim[1])
+	return nil
+}
+
+// checkName returns an error if name can't be the name of a frame
+func checkName(name string) error {
+	if !validName.MatchString(name) {
+		return fmt.Errorf("Invalid frame name %q", name)
+	}
+	_, isStage := stages[name]
+	_, isStatement := statements[name]
+	if isStage || isStatement || name == "exit" {
+		return fmt.Errorf("Invalid frame name %q, it is a command", name)
+	}
+	return nil
+}
+
+func stmtFrames(s *session, args []string) error {
+	for _, k := range s.frameNames() {
+		dim := s.frames[k].Dim()
+		fmt.Fprintf(s.e.stdout, "%s: %d rows x %d columns\n", k, dim[0], dim[1])
+	}
+	return nil
+}
+
+func stmtDrop(s *session, args []string) error {
+	if len(args) != 1 {
+		return errors.New("Usage: drop name")
+	}
+	if _, ok := s.frames[args[0]]; !ok {
+		return fmt.Errorf("Unknown frame %q", args[0])
+	}
+	delete(s.frames, args[0])
+	return nil
+}
+
+func stmtHistory(s *session, args []string) error {
+	for i, line := range s.history {
+		fmt.Fprintf(s.e.stdout, "%5d  %s\n", i+1, line)
+	}
+	return nil
+}
+
+func stmtHelp(s *session, args []string) error {
+	w := s.e.stdout
+	fmt.Fprintln(w, "Statements:")
+	for _, k := range sortedKeys(statements) {
+		st := statements[k]
+		fmt.Fprintf(w, "  %-28s %s\n", strings.TrimSpace(k+" "+st.args), st.help)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Pipelines, printed or assigned with name = pipeline:")
+	fmt.Fprintln(w, "  frame | stage | ...  or  stage frame args | stage | ...")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Stages:")
+	for _, k := range sortedKeys(stages) {
+		st := stages[k]
+		fmt.Fprintf(w, "  %-28s %s\n", strings.TrimSpace(k+" "+st.args), st.help)
+	}
+	return nil
+}
+
+// sortedKeys returns the keys of a map of stages or statements, sorted
+func sortedKeys(m interface{}) []string {
+	var keys []string
+	switch m := m.(type) {
+	case map[string]stage:
+		for k := range m {
+			keys = append(keys, k)
+		}
+	case map[string]statement:
+		for k := range m {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func (s *session) frameNames() []string {
+	names := make([]string, 0, len(s.frames))
+	for k := range s.frames {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// complete returns the candidates to complete the last word of line:
+// commands and frames at the start of a stage, frames after the commands
+// that take them, paths after load and save, and column names otherwise
+func (s *session) complete(line string) (int, []string) {
+	start := strings.LastIndexAny(line, " \t|,(=") + 1
+	word := line[start:]
+	stmt := line[:start]
+	if m := assignment.FindStringSubmatchIndex(stmt); m != nil {
+		stmt = stmt[m[4]:]
+	}
+	parts := splitPipes(stmt)
+	first := strings.Fields(parts[0])
+	current := strings.Fields(parts[len(parts)-1])
+
+	// The arguments of the first stage start after the frame
+	args := len(current) - 1
+	if len(parts) == 1 {
+		args--
+	}
+	isStage := false
+	if len(first) > 0 {
+		_, isStage = stages[first[0]]
+	}
+
+	var candidates []string
+	switch {
+	case len(current) == 0:
+		candidates = sortedKeys(stages)
+		if len(parts) == 1 {
+			candidates = append(candidates, sortedKeys(statements)...)
+			candidates = append(candidates, s.frameNames()...)
+		}
+	case len(parts) == 1 && len(current) == 1 && (isStage || first[0] == "drop"):
+		candidates = s.frameNames()
+	case current[0] == "join" && args == 0:
+		candidates = s.frameNames()
+	case (current[0] == "save" && args == 0) || (current[0] == "load" && len(current) == 2):
+		matches, _ := filepath.Glob(word + "*")
+		for _, m := range matches {
+			if info, err := os.Stat(m); err == nil && info.IsDir() {
+				m += string(filepath.Separator)
+			}
+			candidates = append(candidates, m)
+		}
+	default:
+		candidates = s.columns(first)
+		if current[0] == "agg" {
+			for k := range aggFuncs {
+				candidates = append(candidates, k)
+			}
+		}
+	}
+
+	var ret []string
+	seen := map[string]bool{}
+	for _, c := range candidates {
+		if strings.HasPrefix(c, word) && !seen[c] {
+			seen[c] = true
+			ret = append(ret, c)
+		}
+	}
+	sort.Strings(ret)
+	return start, ret
+}
+
+// columns returns the column names of the frame that the first stage of a
+// pipeline reads, or of all the frames if it is not known yet
+func (s *session) columns(first []string) []string {
+	frames := s.frames
+	if len(first) > 0 {
+		name := first[0]
+		if _, ok := stages[name]; ok && len(first) > 1 {
+			name = first[1]
+		}
+		if d, ok := s.frames[name]; ok {
+			frames = map[string]*df.DataFrame{name: d}
+		}
+	}
+	var names []string
+	for _, d := range frames {
+		for _, k := range d.Names() {
+			if strings.ContainsAny(k, " \t,|()=`") {
+				k = "`" + k + "`"
+			}
+			names = append(names, k)
+		}
+	}
+	return names
+}
@@ -0,0 +1,229 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+)
+
+// newTestSession returns a session with people loaded as the frame p
+func newTestSession(t *testing.T) (*session, *bytes.Buffer, *bytes.Buffer) {
+	var stdout, stderr bytes.Buffer
+	e := &env{stdin: strings.NewReader(people), stdout: &stdout, stderr: &stderr}
+	s := newSession(e, "", "csv")
+	if err := s.load("p", "-"); err != nil {
+		t.Fatal(err)
+	}
+	stdout.Reset()
+	return s, &stdout, &stderr
+}
+
+func TestSession_Exec(t *testing.T) {
+	table := []struct {
+		lines    []string
+		expected string
+	}{
+		{
+			[]string{"p | head 1"},
+			"Name,Age,Amount\nAlice,32,10.5\n",
+		},
+		{
+			[]string{"filter p Age > 30 && Amount < 10"},
+			"Name,Age,Amount\nCarol,41,7.25\n",
+		},
+		{
+			[]string{"filter p Age > 50"},
+			"Name,Age,Amount\n",
+		},
+		{
+			[]string{"groupby p Name | agg sum(Amount) as total, count(*)"},
+			"Name,total,count\nAlice,10.5,1\nBob,6,2\nCarol,7.25,1\n",
+		},
+		{
+			[]string{"agg p max(Age), count(Age)"},
+			"Age_max,Age_count\n41,2\n",
+		},
+		{
+			[]string{"x = sort p -Amount | select Amount, Name | uniq", "x | head 2", "frames"},
+			"x: 3 rows x 2 columns\nAmount,Name\n10.5,Alice\n7.25,Carol\np: 4 rows x 3 columns\nx: 3 rows x 2 columns\n",
+		},
+		{
+			[]string{"mutate p Double = Amount * 2 | select Double | head 2"},
+			"Double\n21\n6\n",
+		},
+		{
+			[]string{"n = select p Name | head 2", "join n p left on Name | select Name, Age"},
+			"n: 2 rows x 1 columns\nName,Age\nAlice,32\nBob,NA\nBob,NA\n",
+		},
+		{
+			[]string{"schema p"},
+			"Column,Type\nName,string\nAge,int\nAmount,float\n",
+		},
+		{
+			[]string{"# comment", "", "drop p", "frames"},
+			"",
+		},
+	}
+	for k, v := range table {
+		s, stdout, stderr := newTestSession(t)
+		for _, line := range v.lines {
+			if err := s.exec(line); err != nil {
+				t.Error("Test", k, line, err, stderr.String())
+			}
+		}
+		received := stdout.String()
+		if v.expected != received {
+			t.Error(
+				"Test", k, "\n",
+				"Expected:\n",
+				v.expected, "\n",
+				"Received:\n",
+				received,
+			)
+		}
+	}
+}
+
+func TestSession_ExecErrors(t *testing.T) {
+	table := []struct {
+		line     string
+		expected string
+	}{
+		{"q | head", `Unknown command or frame "q"`},
+		{"filter q Age > 1", `Unknown frame "q"`},
+		{"p | nope", `Unknown command "nope"`},
+		{"p | frames", "frames can't be part of a pipeline"},
+		{"groupby p Name", "groupby must be followed by agg"},
+		{"groupby p Name | head", "groupby must be followed by agg"},
+		{"groupby p Nope | agg count(*)", "groupby: Unknown column: Nope"},
+		{"agg p median(Age)", `agg: Unknown aggregation "median"`},
+		{"agg p sum(*)", "agg: sum needs a column"},
+		{"head = p", `Invalid frame name "head", it is a command`},
+		{"p | head x", `head: Invalid number of rows "x"`},
+		{"mutate p x", "mutate: Expected col = expr"},
+		{"join p q on Name", `join: Unknown frame "q"`},
+		{"load x", "Usage: load name path"},
+	}
+	for k, v := range table {
+		s, _, _ := newTestSession(t)
+		err := s.exec(v.line)
+		if err == nil || err.Error() != v.expected {
+			t.Error(
+				"Test", k, "\n",
+				"Expected:\n",
+				v.expected, "\n",
+				"Received:\n",
+				err,
+			)
+		}
+	}
+	s, _, _ := newTestSession(t)
+	if err := s.exec("quit"); err != errQuit {
+		t.Error("quit should end the session:", err)
+	}
+}
+
+func TestSession_Script(t *testing.T) {
+	s, stdout, stderr := newTestSession(t)
+	err := s.script(strings.NewReader("p | head 1\nbad\nhistory\nexit\np\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "Name,Age,Amount\nAlice,32,10.5\n    1  p | head 1\n    2  bad\n    3  history\n"
+	if stdout.String() != expected {
+		t.Error("Expected:\n", expected, "\nReceived:\n", stdout.String())
+	}
+	expected = "error: Unknown command or frame \"bad\"\n"
+	if stderr.String() != expected {
+		t.Error("Expected:\n", expected, "\nReceived:\n", stderr.String())
+	}
+}
+
+func TestSession_Complete(t *testing.T) {
+	s, _, _ := newTestSession(t)
+	s.exec("q = select p Name")
+	table := []struct {
+		line     string
+		expected string
+	}{
+		{"fi", "0 [filter]"},
+		{"", "0 [agg describe drop filter frames groupby head help history join load mutate p q quit save schema select sort uniq]"},
+		{"p | s", "4 [save schema select sort]"},
+		{"filter ", "7 [p q]"},
+		{"filter p A", "9 [Age Amount]"},
+		{"x = sort p -Amount, N", "20 [Name]"},
+		{"groupby p Name | agg m", "21 [max mean min]"},
+		{"p | join ", "9 [p q]"},
+		{"join p q on ", "12 [Age Amount Name]"},
+		{"drop ", "5 [p q]"},
+	}
+	for k, v := range table {
+		start, candidates := s.complete(v.line)
+		received := fmt.Sprint(start, " ", candidates)
+		if v.expected != received {
+			t.Error(
+				"Test", k, "\n",
+				"Expected:\n",
+				v.expected, "\n",
+				"Received:\n",
+				received,
+			)
+		}
+	}
+}
+
+func TestLineEditor(t *testing.T) {
+	complete := func(line string) (int, []string) {
+		start := strings.LastIndex(line, " ") + 1
+		var ret []string
+		for _, c := range []string{"Amount", "Age", "Name"} {
+			if strings.HasPrefix(c, line[start:]) {
+				ret = append(ret, c)
+			}
+		}
+		return start, ret
+	}
+	table := []struct {
+		input    string
+		history  []string
+		expected []string
+	}{
+		{"abc\r", nil, []string{"abc"}},
+		// Backspace, moving the cursor and inserting
+		{"abd\x7fc\x1b[D\x1b[DX\x05Y\r", nil, []string{"aXbcY"}},
+		// ctrl-a, ctrl-k, ctrl-u and ctrl-w
+		{"abc\x01\x0bxy z\x17\r", nil, []string{"xy "}},
+		{"abc\x15d\r", nil, []string{"d"}},
+		// ctrl-c discards the line and ctrl-d ends on an empty line
+		{"abc\x03d\r\x04", nil, []string{"d"}},
+		// Browsing the history returns to the line being written
+		{"x\x1b[A\x1b[A\x1b[B\r", []string{"one", "two"}, []string{"two"}},
+		{"x\x1b[A\x1b[B\r", []string{"one"}, []string{"x"}},
+		{"\x10\x10\x10\r", []string{"one", "two"}, []string{"one"}},
+		// Tab completes a single candidate and the common prefix
+		{"N\t\r", nil, []string{"Name "}},
+		{"sort A\tg\t\r", nil, []string{"sort Age "}},
+		{"sort Z\t\r", nil, []string{"sort Z"}},
+	}
+	for k, v := range table {
+		var out bytes.Buffer
+		l := newLineEditor(strings.NewReader(v.input), &out, "> ", complete)
+		l.history = v.history
+		var received []string
+		for {
+			line, err := l.readLine()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			received = append(received, line)
+		}
+		if fmt.Sprintf("%q", received) != fmt.Sprintf("%q", v.expected) {
+			t.Errorf("Test %d\nExpected:\n%q\nReceived:\n%q", k, v.expected, received)
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package df
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Row gives access to the elements of a row of a DataFrame by column name
+type Row struct {
+	df    DataFrame
+	index int
+}
+
+// Index returns the position of the row on the DataFrame
+func (r Row) Index() int {
+	return r.index
+}
+
+// Names returns the names of the columns of the row
+func (r Row) Names() []string {
+	return r.df.Names()
+}
+
+// Get returns the element of the row on the given column
+func (r Row) Get(colname string) (Cell, error) {
+	col, err := r.df.col(colname)
+	if err != nil {
+		return nil, err
+	}
+	return col.cells[r.index], nil
+}
+
+// Cells returns the elements of the row in the order of the columns
+func (r Row) Cells() Cells {
+	names := r.df.Names()
+	cells := make(Cells, 0, len(names))
+	for _, k := range names {
+		cells = append(cells, r.df.Columns[k].cells[r.index])
+	}
+	return cells
+}
+
+// checkCells verifies that the cells returned by a user function can be
+// stored on a column
+func checkCells(cells Cells) error {
+	for i, c := range cells {
+		if c == nil {
+			return fmt.Errorf("nil element at position %d", i)
+		}
+	}
+	return nil
+}
+
+// ApplyColumns returns a new DataFrame where each column has been replaced by
+// the result of calling f with its name and its elements. The returned Cells
+// must have the same length as the column and elements of the same type, but
+// the type may differ from the one of the original column. Columns without
+// elements keep their type, as there are no elements to take it from.
+func (df DataFrame) ApplyColumns(f func(colname string, col Cells) (Cells, error)) (*DataFrame, error) {
+	newDf := df.copy()
+	for _, k := range df.Names() {
+		cells, err := f(k, df.Columns[k].copy().cells)
+		if err != nil {
+			return nil, fmt.Errorf("column %s: %v", k, err)
+		}
+		if len(cells) != df.nRows {
+			return nil, fmt.Errorf("column %s: expected %d elements but received %d", k, df.nRows, len(cells))
+		}
+		if err := checkCells(cells); err != nil {
+			return nil, fmt.Errorf("column %s: %v", k, err)
+		}
+		if df.nRows == 0 {
+			continue
+		}
+		col, err := newCol(k, cells)
+		if err != nil {
+			return nil, fmt.Errorf("column %s: %v", k, err)
+		}
+		newDf.Columns[k] = *col
+	}
+	return &newDf, nil
+}
+
+// ApplyRows returns a new DataFrame where each row has been replaced by the
+// result of calling f with it. The returned Cells must contain an element for
+// each column, in the order of the columns, with the type of the column.
+func (df DataFrame) ApplyRows(f func(row Row) (Cells, error)) (*DataFrame, error) {
+	names := df.Names()
+	columns := make([]Cells, len(names))
+	for i := 0; i < df.nRows; i++ {
+		cells, err := f(Row{df, i})
+		if err != nil {
+			return nil, fmt.Errorf("row %d: %v", i, err)
+		}
+		if len(cells) != len(names) {
+			return nil, fmt.Errorf("row %d: expected %d elements but received %d", i, len(names), len(cells))
+		}
+		if err := checkCells(cells); err != nil {
+			return nil, fmt.Errorf("row %d: %v", i, err)
+		}
+		for j, k := range names {
+			colType := df.Columns[k].colType
+			if t := reflect.TypeOf(cells[j]).String(); t != colType {
+				return nil, fmt.Errorf("row %d: expected an element of type %s for column %s but received %s", i, colType, k, t)
+			}
+			columns[j] = append(columns[j], cells[j])
+		}
+	}
+
+	newDf := df.copy()
+	if df.nRows == 0 {
+		return &newDf, nil
+	}
+	for j, k := range names {
+		col, err := newCol(k, columns[j])
+		if err != nil {
+			return nil, err
+		}
+		newDf.Columns[k] = *col
+	}
+	return &newDf, nil
+}
+
+// MapColumn returns a new DataFrame where each element of the column colname
+// has been replaced by the result of calling f with it. All the returned
+// elements must be of the same type.
+func (df DataFrame) MapColumn(colname string, f func(Cell) Cell) (*DataFrame, error) {
+	col, err := df.col(colname)
+	if err != nil {
+		return nil, err
+	}
+	if df.nRows == 0 {
+		newDf := df.copy()
+		return &newDf, nil
+	}
+	cells := make(Cells, 0, df.nRows)
+	for i, c := range col.cells {
+		r := f(c.Copy())
+		if r == nil {
+			return nil, fmt.Errorf("nil element at position %d", i)
+		}
+		cells = append(cells, r)
+	}
+	return df.withColumn(colname, cells)
+}
@@ -0,0 +1,156 @@
+package df
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestDataFrame_ApplyColumns(t *testing.T) {
+	d, _ := New(
+		C{"A", Strings("a", "b", nil)},
+		C{"B", Ints(1, 2, 3)},
+	)
+	dd, err := d.ApplyColumns(func(name string, col Cells) (Cells, error) {
+		if name == "A" {
+			ret := Cells{}
+			for _, c := range col {
+				if c.IsNA() {
+					ret = append(ret, c)
+					continue
+				}
+				ret = append(ret, Strings(strings.ToUpper(c.String()))...)
+			}
+			return ret, nil
+		}
+		return Floats(col), nil
+	})
+	if err != nil {
+		t.Error(err)
+	}
+	expected := "[[A B] [A 1] [B 2] [NA 3]]"
+	received := fmt.Sprint(dd.SaveRecords())
+	if expected != received || dd.Columns["B"].colType != "df.Float" {
+		t.Error(
+			"Expected:\n",
+			expected, "df.Float\n",
+			"Received:\n",
+			received, dd.Columns["B"].colType,
+		)
+	}
+	if fmt.Sprint(d.Columns["A"].cells) != "[a b NA]" {
+		t.Error("Original DataFrame was modified:", d.Columns["A"].cells)
+	}
+
+	// Without rows the columns keep their types
+	de, err := d.Empty().ApplyColumns(func(string, Cells) (Cells, error) { return Cells{}, nil })
+	if err != nil {
+		t.Error(err)
+	} else if fmt.Sprint(de.Types()) != "[df.String df.Int]" {
+		t.Error("Expected the types [df.String df.Int], received", de.Types())
+	} else if _, err := Rbind(*de, *d); err != nil {
+		t.Error(err)
+	}
+
+	var fails = []func(string, Cells) (Cells, error){
+		func(string, Cells) (Cells, error) { return Ints(1), nil },
+		func(string, Cells) (Cells, error) { return Cells{Ints(1)[0], Floats(1)[0], nil}, nil },
+		func(string, Cells) (Cells, error) { return Cells{Ints(1)[0], Floats(1)[0], Ints(1)[0]}, nil },
+		func(string, Cells) (Cells, error) { return nil, errors.New("fail") },
+	}
+	for k, f := range fails {
+		if _, err := d.ApplyColumns(f); err == nil {
+			t.Error("Test", k, "should throw an error")
+		}
+	}
+}
+
+func TestDataFrame_ApplyRows(t *testing.T) {
+	d, _ := New(
+		C{"Name", Strings("a", "b", "c")},
+		C{"Qty", Ints(1, 2, nil)},
+	)
+	dd, err := d.ApplyRows(func(r Row) (Cells, error) {
+		qty, err := r.Get("Qty")
+		if err != nil {
+			return nil, err
+		}
+		name, _ := r.Get("Name")
+		if qty.IsNA() {
+			return Cells{name, Ints(0)[0]}, nil
+		}
+		i, _ := qty.Int()
+		return Cells{name, Ints(*i * 10)[0]}, nil
+	})
+	if err != nil {
+		t.Error(err)
+	}
+	expected := "[[Name Qty] [a 10] [b 20] [c 0]]"
+	received := fmt.Sprint(dd.SaveRecords())
+	if expected != received {
+		t.Error(
+			"Expected:\n",
+			expected, "\n",
+			"Received:\n",
+			received,
+		)
+	}
+
+	// Rows can be accessed by index and as a whole
+	d.ApplyRows(func(r Row) (Cells, error) {
+		if r.Index() == 1 && fmt.Sprint(r.Cells()) != "[b 2]" {
+			t.Error("Expected row: [b 2] Received:", r.Cells())
+		}
+		return r.Cells(), nil
+	})
+
+	var fails = []func(Row) (Cells, error){
+		func(r Row) (Cells, error) { return Cells{r.Cells()[0]}, nil },
+		func(r Row) (Cells, error) { return Cells{r.Cells()[0], Floats(1)[0]}, nil },
+		func(r Row) (Cells, error) { return Cells{nil, Ints(1)[0]}, nil },
+		func(r Row) (Cells, error) { _, err := r.Get("Unknown"); return nil, err },
+	}
+	for k, f := range fails {
+		if _, err := d.ApplyRows(f); err == nil {
+			t.Error("Test", k, "should throw an error")
+		}
+	}
+}
+
+func TestDataFrame_MapColumn(t *testing.T) {
+	d, _ := New(
+		C{"A", Ints(1, 2, nil)},
+		C{"B", Strings("a", "b", "c")},
+	)
+	dd, err := d.MapColumn("A", func(c Cell) Cell {
+		if c.IsNA() {
+			return Bool{nil}
+		}
+		i, _ := c.Int()
+		return Bools(*i%2 == 0)[0]
+	})
+	if err != nil {
+		t.Error(err)
+	}
+	expected := "[false true NA]"
+	received := fmt.Sprint(dd.Columns["A"].cells)
+	if expected != received || dd.Columns["A"].colType != "df.Bool" {
+		t.Error("Expected:", expected, "Received:", received, dd.Columns["A"].colType)
+	}
+
+	if _, err := d.MapColumn("A", func(c Cell) Cell {
+		if c.IsNA() {
+			return Strings("NA")[0]
+		}
+		return c
+	}); err == nil {
+		t.Error("Elements of different types should throw an error")
+	}
+	if _, err := d.MapColumn("A", func(c Cell) Cell { return nil }); err == nil {
+		t.Error("Nil elements should throw an error")
+	}
+	if _, err := d.MapColumn("C", func(c Cell) Cell { return c }); err == nil {
+		t.Error("Unknown columns should throw an error")
+	}
+}
@@ -2,79 +2,451 @@ package df
 
 import (
 	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
 )
 
-func (d *DataFrame) DivColumn(a, b string) error {
-	ca, oka := d.Columns[a]
-	cb, okb := d.Columns[b]
-	if !oka || !okb {
-		return errors.New("column not find:" + a + " " + b)
+// Operator represents an arithmetic operation between two elements
+type Operator int
+
+// Arithmetic operators supported between columns and values
+const (
+	Add Operator = iota
+	Sub
+	Mul
+	Div
+	Mod
+	Pow
+)
+
+func (op Operator) String() string {
+	switch op {
+	case Add:
+		return "+"
+	case Sub:
+		return "-"
+	case Mul:
+		return "*"
+	case Div:
+		return "/"
+	case Mod:
+		return "%"
+	case Pow:
+		return "^"
 	}
+	return "Operator(" + strconv.Itoa(int(op)) + ")"
+}
+
+// ArithOptions modifies the behaviour of the arithmetic operations
+type ArithOptions struct {
+	// FloatDivision promotes the result of the division between integer
+	// elements to Float instead of performing an integer division.
+	FloatDivision bool
+}
+
+// numKind identifies the numeric types that can be used on arithmetic
+// operations. The order of the constants is used for the type promotion.
+type numKind int
+
+const (
+	kindNone numKind = iota
+	kindInt
+	kindUint64
+	kindInt64
+	kindDecimal
+	kindFloat
+)
 
+// numericKind returns the numeric kind of a Cell
+func numericKind(c Cell) numKind {
+	switch c.(type) {
+	case Int:
+		return kindInt
+	case Int64:
+		return kindInt64
+	case Uint64:
+		return kindUint64
+	case Decimal:
+		return kindDecimal
+	case Float:
+		return kindFloat
+	}
+	return kindNone
+}
+
+// promoteKinds returns the kind of the result of operating two elements of
+// the given kinds:
+//	- Pow always returns a Float.
+//	- If any of the elements is a Float the result will be a Float.
+//	- If any of the elements is a Decimal the result will be a Decimal.
+//	- The division of integers returns a Float if FloatDivision is set.
+//	- Int64 and Uint64 operated with any other integer returns an Int64.
+//	- Int operated with Int returns an Int.
+func promoteKinds(a, b numKind, op Operator, opts ArithOptions) numKind {
+	k := a
+	if b > k {
+		k = b
+	}
 	switch {
-	case ca.colType == "df.Int" && cb.colType == "df.Int":
-		for i := 0; i < d.nRows; i++ {
-			acell := ca.cells[i].(Int)
-			bcell := cb.cells[i].(Int)
+	case op == Pow || k == kindFloat:
+		return kindFloat
+	case k == kindDecimal:
+		return kindDecimal
+	case op == Div && opts.FloatDivision:
+		return kindFloat
+	case a != b:
+		return kindInt64
+	}
+	return k
+}
 
-			av := *acell.i / *bcell.i
-			acell.i = &av
-		}
-	case ca.colType == "df.Float" && cb.colType == "df.Int":
-		for i := 0; i < d.nRows; i++ {
-			acell := ca.cells[i].(Float)
-			bcell := cb.cells[i].(Int)
+// emptyKind returns the NA element of the given numeric kind
+func emptyKind(k numKind, scale int) Cell {
+	switch k {
+	case kindInt:
+		return Int{nil}
+	case kindInt64:
+		return Int64{nil}
+	case kindUint64:
+		return Uint64{nil}
+	case kindDecimal:
+		return Decimal{nil, scale}
+	}
+	return Float{nil}
+}
 
-			av := *acell.f / float64(*bcell.i)
-			acell.f = &av
-		}
-	case ca.colType == "df.Int" && cb.colType == "df.Float":
-		for i := 0; i < d.nRows; i++ {
-			acell := ca.cells[i].(Int)
-			bcell := cb.cells[i].(Float)
+// decimalScale returns the scale of the cell if it is a Decimal
+func decimalScale(c Cell) int {
+	if d, ok := c.(Decimal); ok {
+		return d.scale
+	}
+	return 0
+}
+
+// toDecimal converts a non NA numeric Cell into a Decimal
+func toDecimal(c Cell) (Decimal, error) {
+	switch e := c.(type) {
+	case Decimal:
+		return e, nil
+	case Uint64:
+		return Decimal{new(big.Int).SetUint64(*e.u), 0}, nil
+	}
+	i, err := cellInt64(c)
+	if err != nil {
+		return Decimal{}, err
+	}
+	return Decimal{big.NewInt(i), 0}, nil
+}
+
+// arith performs the given operation between two numeric cells, returning
+// a cell of the promoted kind. If any of the elements is NA or a division by
+// zero is performed the result will be NA.
+func arith(a, b Cell, op Operator, opts ArithOptions) (Cell, error) {
+	ka, kb := numericKind(a), numericKind(b)
+	if ka == kindNone || kb == kindNone {
+		return nil, fmt.Errorf("Can't operate non numeric elements: %T %s %T", a, op, b)
+	}
+	k := promoteKinds(ka, kb, op, opts)
+	empty := emptyKind(k, maxInt(decimalScale(a), decimalScale(b)))
+	if a.IsNA() || b.IsNA() {
+		return empty, nil
+	}
 
-			av := int(float64(*acell.i) / *bcell.f)
-			acell.i = &av
+	var ret Cell
+	var err error
+	switch k {
+	case kindFloat:
+		fa, _ := a.Float()
+		fb, _ := b.Float()
+		ret, err = arithFloat(*fa, *fb, op)
+	case kindDecimal:
+		var da, db Decimal
+		if da, err = toDecimal(a); err != nil {
+			return nil, err
 		}
-	case ca.colType == "df.Float" && cb.colType == "df.Float":
-		for i := 0; i < d.nRows; i++ {
-			acell := ca.cells[i].(Float)
-			bcell := cb.cells[i].(Float)
+		if db, err = toDecimal(b); err != nil {
+			return nil, err
+		}
+		ret, err = arithDecimal(da, db, op)
+	case kindUint64:
+		ret, err = arithUint64(a.(Uint64), b.(Uint64), op)
+	case kindInt64, kindInt:
+		var ia, ib int64
+		if ia, err = cellInt64(a); err != nil {
+			return nil, err
+		}
+		if ib, err = cellInt64(b); err != nil {
+			return nil, err
+		}
+		var r Int64
+		r, err = arithInt64(Int64{&ia}, Int64{&ib}, op)
+		if err == nil && k == kindInt && !r.IsNA() {
+			i := int(*r.i)
+			if int64(i) != *r.i {
+				return nil, errors.New("Int overflow")
+			}
+			ret = Int{&i}
+		} else {
+			ret = r
+		}
+	}
+	if err == errDivisionByZero {
+		return empty, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
 
-			av := *acell.f / *bcell.f
-			acell.f = &av
+func arithFloat(a, b float64, op Operator) (Cell, error) {
+	var r float64
+	switch op {
+	case Add:
+		r = a + b
+	case Sub:
+		r = a - b
+	case Mul:
+		r = a * b
+	case Div:
+		if b == 0 {
+			return nil, errDivisionByZero
+		}
+		r = a / b
+	case Mod:
+		if b == 0 {
+			return nil, errDivisionByZero
 		}
+		r = math.Mod(a, b)
+	case Pow:
+		r = math.Pow(a, b)
 	default:
-		return errors.New("types check fail")
+		return nil, errors.New("Unknown operator: " + op.String())
 	}
+	if math.IsNaN(r) {
+		return Float{nil}, nil
+	}
+	return Float{&r}, nil
+}
 
-	return nil
+func arithDecimal(a, b Decimal, op Operator) (Cell, error) {
+	switch op {
+	case Add:
+		return a.Add(b)
+	case Sub:
+		return a.Sub(b)
+	case Mul:
+		return a.Mul(b)
+	case Div:
+		return a.Div(b)
+	case Mod:
+		return a.Mod(b)
+	}
+	return nil, errors.New("Unknown operator: " + op.String())
 }
 
-func (d *DataFrame) DivValue(a string, v float64) error {
-	ca, oka := d.Columns[a]
-	if !oka {
-		return errors.New("column not find:" + a)
+func arithInt64(a, b Int64, op Operator) (Int64, error) {
+	switch op {
+	case Add:
+		return a.Add(b)
+	case Sub:
+		return a.Sub(b)
+	case Mul:
+		return a.Mul(b)
+	case Div:
+		return a.Div(b)
+	case Mod:
+		return a.Mod(b)
 	}
+	return Int64{nil}, errors.New("Unknown operator: " + op.String())
+}
 
-	switch {
-	case ca.colType == "df.Int":
-		for i := 0; i < d.nRows; i++ {
-			acell := ca.cells[i].(Int)
+func arithUint64(a, b Uint64, op Operator) (Cell, error) {
+	switch op {
+	case Add:
+		return a.Add(b)
+	case Sub:
+		return a.Sub(b)
+	case Mul:
+		return a.Mul(b)
+	case Div:
+		return a.Div(b)
+	case Mod:
+		return a.Mod(b)
+	}
+	return nil, errors.New("Unknown operator: " + op.String())
+}
+
+// arithCells performs the given operation element by element. If one of the
+// slices contains a single element it will be operated with all the elements
+// of the other one.
+func arithCells(a, b Cells, op Operator, opts ArithOptions) (Cells, error) {
+	n := len(a)
+	if len(b) > n {
+		n = len(b)
+	}
+	if (len(a) != n && len(a) != 1) || (len(b) != n && len(b) != 1) {
+		return nil, errors.New("Can't operate elements of different dimensions")
+	}
+	ret := make(Cells, 0, n)
+	for i := 0; i < n; i++ {
+		ca, cb := a[0], b[0]
+		if len(a) > 1 {
+			ca = a[i]
+		}
+		if len(b) > 1 {
+			cb = b[i]
+		}
+		c, err := arith(ca, cb, op, opts)
+		if err != nil {
+			return nil, fmt.Errorf("row %d: %v", i, err)
+		}
+		ret = append(ret, c)
+	}
+	return ret, nil
+}
 
-			av := int(float64(*acell.i) / v)
-			acell.i = &av
+// valueCell converts the given value into a Cell that can be operated with the
+// elements of a column of type colType
+func valueCell(v interface{}, colType string) (Cell, error) {
+	switch e := v.(type) {
+	case Cell:
+		return e, nil
+	case int:
+		return Int{&e}, nil
+	case int64:
+		return Int64{&e}, nil
+	case uint64:
+		return Uint64{&e}, nil
+	case float64:
+		return Float{&e}, nil
+	case string:
+		t, ok := typeOf(colType)
+		if !ok {
+			return nil, errors.New("Can't parse a value for the type: " + colType)
 		}
-	case ca.colType == "df.Float":
-		for i := 0; i < d.nRows; i++ {
-			acell := ca.cells[i].(Float)
+		return t.Parse(e)
+	}
+	return nil, fmt.Errorf("Unsupported value type: %T", v)
+}
+
+func arithOptions(opts []ArithOptions) ArithOptions {
+	if len(opts) == 0 {
+		return ArithOptions{}
+	}
+	return opts[0]
+}
 
-			av := *acell.f / v
-			acell.f = &av
+// ArithColumns returns a new DataFrame where the column dest contains the result
+// of operating the columns a and b element by element. If dest is an existing
+// column it will be replaced, otherwise it will be added to the DataFrame.
+func (df DataFrame) ArithColumns(dest, a string, op Operator, b string, opts ...ArithOptions) (*DataFrame, error) {
+	ca, oka := df.Columns[a]
+	cb, okb := df.Columns[b]
+	if !oka || !okb {
+		return nil, errors.New("column not find:" + a + " " + b)
+	}
+	if df.nRows == 0 {
+		// The NA elements give the type of the result
+		empty, err := arith(ca.empty, cb.empty, op, arithOptions(opts))
+		if err != nil {
+			return nil, err
 		}
-	default:
-		return errors.New("types check fail")
+		return df.withEmptyColumn(dest, empty)
+	}
+	cells, err := arithCells(ca.cells, cb.cells, op, arithOptions(opts))
+	if err != nil {
+		return nil, err
+	}
+	return df.withColumn(dest, cells)
+}
+
+// ArithValue returns a new DataFrame where the column dest contains the result
+// of operating each element of the column a with the value v. Values can be
+// given as int, int64, uint64, float64, Cell or as a string that will be
+// parsed with the type of the column.
+func (df DataFrame) ArithValue(dest, a string, op Operator, v interface{}, opts ...ArithOptions) (*DataFrame, error) {
+	ca, ok := df.Columns[a]
+	if !ok {
+		return nil, errors.New("column not find:" + a)
+	}
+	c, err := valueCell(v, ca.colType)
+	if err != nil {
+		return nil, err
+	}
+	if df.nRows == 0 {
+		empty, err := arith(ca.empty, c, op, arithOptions(opts))
+		if err != nil {
+			return nil, err
+		}
+		return df.withEmptyColumn(dest, empty)
+	}
+	cells, err := arithCells(ca.cells, Cells{c}, op, arithOptions(opts))
+	if err != nil {
+		return nil, err
 	}
+	return df.withColumn(dest, cells)
+}
+
+// AddColumn stores on dest the sum of the columns a and b
+func (df DataFrame) AddColumn(dest, a, b string, opts ...ArithOptions) (*DataFrame, error) {
+	return df.ArithColumns(dest, a, Add, b, opts...)
+}
+
+// SubColumn stores on dest the difference of the columns a and b
+func (df DataFrame) SubColumn(dest, a, b string, opts ...ArithOptions) (*DataFrame, error) {
+	return df.ArithColumns(dest, a, Sub, b, opts...)
+}
+
+// MulColumn stores on dest the product of the columns a and b
+func (df DataFrame) MulColumn(dest, a, b string, opts ...ArithOptions) (*DataFrame, error) {
+	return df.ArithColumns(dest, a, Mul, b, opts...)
+}
+
+// DivColumn stores on dest the quotient of the columns a and b
+func (df DataFrame) DivColumn(dest, a, b string, opts ...ArithOptions) (*DataFrame, error) {
+	return df.ArithColumns(dest, a, Div, b, opts...)
+}
+
+// ModColumn stores on dest the remainder of the division of the columns a and b
+func (df DataFrame) ModColumn(dest, a, b string, opts ...ArithOptions) (*DataFrame, error) {
+	return df.ArithColumns(dest, a, Mod, b, opts...)
+}
+
+// PowColumn stores on dest the column a raised to the power of the column b
+func (df DataFrame) PowColumn(dest, a, b string, opts ...ArithOptions) (*DataFrame, error) {
+	return df.ArithColumns(dest, a, Pow, b, opts...)
+}
+
+// AddValue stores on dest the sum of the column a and the value v
+func (df DataFrame) AddValue(dest, a string, v interface{}, opts ...ArithOptions) (*DataFrame, error) {
+	return df.ArithValue(dest, a, Add, v, opts...)
+}
+
+// SubValue stores on dest the difference of the column a and the value v
+func (df DataFrame) SubValue(dest, a string, v interface{}, opts ...ArithOptions) (*DataFrame, error) {
+	return df.ArithValue(dest, a, Sub, v, opts...)
+}
+
+// MulValue stores on dest the product of the column a and the value v
+func (df DataFrame) MulValue(dest, a string, v interface{}, opts ...ArithOptions) (*DataFrame, error) {
+	return df.ArithValue(dest, a, Mul, v, opts...)
+}
+
+// DivValue stores on dest the quotient of the column a and the value v
+func (df DataFrame) DivValue(dest, a string, v interface{}, opts ...ArithOptions) (*DataFrame, error) {
+	return df.ArithValue(dest, a, Div, v, opts...)
+}
+
+// ModValue stores on dest the remainder of the division of the column a and
+// the value v
+func (df DataFrame) ModValue(dest, a string, v interface{}, opts ...ArithOptions) (*DataFrame, error) {
+	return df.ArithValue(dest, a, Mod, v, opts...)
+}
 
-	return nil
+// PowValue stores on dest the column a raised to the power of the value v
+func (df DataFrame) PowValue(dest, a string, v interface{}, opts ...ArithOptions) (*DataFrame, error) {
+	return df.ArithValue(dest, a, Pow, v, opts...)
 }
@@ -0,0 +1,149 @@
+package df
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestDataFrame_ArithColumns(t *testing.T) {
+	d, _ := New(
+		C{"A", Ints(7, 9, nil, 4)},
+		C{"B", Ints(2, 0, 1, 3)},
+		C{"C", Floats(0.5, 1.5, 2.5, nil)},
+		C{"D", Decimals(2, "1.10", "2.25", "3.00", "0.10")},
+		C{"S", Strings("a", "b", "c", "d")},
+	)
+	var tests = []struct {
+		a, b     string
+		op       Operator
+		opts     []ArithOptions
+		expected string
+		colType  string
+	}{
+		{"A", "B", Add, nil, "[9 9 NA 7]", "df.Int"},
+		{"A", "B", Sub, nil, "[5 9 NA 1]", "df.Int"},
+		{"A", "B", Mul, nil, "[14 0 NA 12]", "df.Int"},
+		{"A", "B", Div, nil, "[3 NA NA 1]", "df.Int"},
+		{"A", "B", Div, []ArithOptions{{FloatDivision: true}}, "[3.5 NA NA 1.3333333333333333]", "df.Float"},
+		{"A", "B", Mod, nil, "[1 NA NA 1]", "df.Int"},
+		{"A", "B", Pow, nil, "[49 1 NA 64]", "df.Float"},
+		{"A", "C", Mul, nil, "[3.5 13.5 NA NA]", "df.Float"},
+		{"A", "D", Mul, nil, "[7.70 20.25 NA 0.40]", "df.Decimal"},
+		{"D", "B", Div, nil, "[0.55 NA 3.00 0.03]", "df.Decimal"},
+	}
+	for k, v := range tests {
+		dd, err := d.ArithColumns("R", v.a, v.op, v.b, v.opts...)
+		if err != nil {
+			t.Error("Test", k, ":", err)
+			continue
+		}
+		col := dd.Columns["R"]
+		received := fmt.Sprint(col.cells)
+		if v.expected != received || v.colType != col.colType {
+			t.Error(
+				"Test", k, "\n",
+				"Expected:\n",
+				v.expected, v.colType, "\n",
+				"Received:\n",
+				received, col.colType,
+			)
+		}
+	}
+
+	// Without rows the result column gets the type it would have with them
+	for k, v := range tests {
+		dd, err := d.Empty().ArithColumns("R", v.a, v.op, v.b, v.opts...)
+		if err != nil {
+			t.Error("Test", k, "without rows:", err)
+			continue
+		}
+		if dd.NRows() != 0 || dd.Columns["R"].colType != v.colType {
+			t.Error("Test", k, "without rows: expected an empty", v.colType, "column, received", dd.NRows(), "rows of type", dd.Columns["R"].colType)
+		}
+	}
+	if _, err := d.Empty().AddColumn("R", "A", "S"); err == nil {
+		t.Error("Operating non numeric columns should throw an error without rows")
+	}
+
+	if _, err := d.AddColumn("R", "A", "S"); err == nil {
+		t.Error("Operating non numeric columns should throw an error")
+	}
+	if _, err := d.AddColumn("R", "A", "X"); err == nil {
+		t.Error("Operating unknown columns should throw an error")
+	}
+
+	// The result is added as a new column and the input is not modified
+	dd, err := d.AddColumn("A", "A", "B")
+	if err != nil {
+		t.Error(err)
+	}
+	if fmt.Sprint(d.Columns["A"].cells) != "[7 9 NA 4]" {
+		t.Error("Original DataFrame was modified:", d.Columns["A"].cells)
+	}
+	if fmt.Sprint(dd.Names()) != "[A B C D S]" {
+		t.Error("Replacing a column should keep the column order:", dd.Names())
+	}
+	dd, _ = d.SubColumn("E", "A", "B")
+	if fmt.Sprint(dd.Names()) != "[A B C D S E]" {
+		t.Error("New columns should be added at the end:", dd.Names())
+	}
+	if d.NCols() != 5 {
+		t.Error("Original DataFrame was modified:", d.Names())
+	}
+}
+
+func TestDataFrame_ArithValue(t *testing.T) {
+	d, _ := New(
+		C{"A", Ints(7, 9, nil, 4)},
+		C{"D", Decimals(2, "1.10", "2.25", "3.00", "0.10")},
+		C{"I", Int64s(1, 2, 3, 4)},
+	)
+	var tests = []struct {
+		a        string
+		op       Operator
+		v        interface{}
+		expected string
+	}{
+		{"A", Add, 1, "[8 10 NA 5]"},
+		{"A", Div, 0, "[NA NA NA NA]"},
+		{"A", Mul, 0.5, "[3.5 4.5 NA 2]"},
+		{"D", Mul, "1.5", "[1.65 3.38 4.50 0.15]"},
+		{"I", Sub, int64(1), "[0 1 2 3]"},
+		{"A", Mod, 4, "[3 1 NA 0]"},
+		{"A", Pow, 2, "[49 81 NA 16]"},
+	}
+	for k, v := range tests {
+		dd, err := d.ArithValue("R", v.a, v.op, v.v)
+		if err != nil {
+			t.Error("Test", k, ":", err)
+			continue
+		}
+		received := fmt.Sprint(dd.Columns["R"].cells)
+		if v.expected != received {
+			t.Error(
+				"Test", k, "\n",
+				"Expected:\n",
+				v.expected, "\n",
+				"Received:\n",
+				received,
+			)
+		}
+
+		// Without rows the result column keeps the same type
+		de, err := d.Empty().ArithValue("R", v.a, v.op, v.v)
+		if err != nil {
+			t.Error("Test", k, "without rows:", err)
+		} else if _, err := Rbind(*de, *dd); err != nil {
+			t.Error("Test", k, "without rows:", err)
+		}
+	}
+	if _, err := d.Empty().AddValue("R", "A", "abc"); err == nil {
+		t.Error("Values of the wrong type should throw an error without rows")
+	}
+
+	max := Int64s("9223372036854775807")
+	d, _ = New(C{"A", max})
+	if _, err := d.AddValue("R", "A", int64(1)); err == nil {
+		t.Error("Overflow should throw an error")
+	}
+}
@@ -0,0 +1,95 @@
+package df
+
+import (
+	"errors"
+	"sort"
+)
+
+// SortKey defines the column used to sort the rows of a DataFrame, the
+// direction of the sort and where NA elements should be placed. By default
+// rows are sorted in ascending order with the NA elements at the end.
+type SortKey struct {
+	Column  string
+	Desc    bool
+	NAFirst bool
+}
+
+// sortKeyColumn is a SortKey with its column and comparator resolved
+type sortKeyColumn struct {
+	SortKey
+	cells   Cells
+	compare func(a, b Cell) int
+}
+
+// rowSorter implements sort.Interface over the row indexes of a DataFrame
+type rowSorter struct {
+	rows []int
+	keys []sortKeyColumn
+}
+
+func (s rowSorter) Len() int {
+	return len(s.rows)
+}
+
+func (s rowSorter) Swap(i, j int) {
+	s.rows[i], s.rows[j] = s.rows[j], s.rows[i]
+}
+
+func (s rowSorter) Less(i, j int) bool {
+	for _, k := range s.keys {
+		a, b := k.cells[s.rows[i]], k.cells[s.rows[j]]
+		naA, naB := a.IsNA(), b.IsNA()
+		switch {
+		case naA && naB:
+			continue
+		case naA:
+			return k.NAFirst
+		case naB:
+			return !k.NAFirst
+		}
+		cmp := k.compare(a, b)
+		if cmp == 0 {
+			continue
+		}
+		if k.Desc {
+			return cmp > 0
+		}
+		return cmp < 0
+	}
+	return false
+}
+
+// Arrange returns a new DataFrame with its rows sorted by the given keys.
+// Rows are compared by the first key and ties are broken by the following
+// ones. Elements are compared with the comparator of the column type and the
+// sort is stable, so rows that compare equal keep their original order.
+func (df DataFrame) Arrange(keys ...SortKey) (*DataFrame, error) {
+	if len(keys) == 0 {
+		return nil, errors.New("No sorting keys given")
+	}
+	s := rowSorter{
+		rows: make([]int, df.nRows),
+		keys: make([]sortKeyColumn, 0, len(keys)),
+	}
+	for _, k := range keys {
+		col, err := df.col(k.Column)
+		if err != nil {
+			return nil, err
+		}
+		t, ok := typeOf(col.colType)
+		if !ok {
+			return nil, errors.New("Can't sort elements of unregistered type: " + col.colType)
+		}
+		s.keys = append(s.keys, sortKeyColumn{k, col.cells, t.Compare})
+	}
+	if df.nRows == 0 {
+		newDf := df.copy()
+		return &newDf, nil
+	}
+	for i := range s.rows {
+		s.rows[i] = i
+	}
+	sort.Stable(s)
+
+	return df.SubsetRows(s.rows)
+}
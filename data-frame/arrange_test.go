@@ -0,0 +1,101 @@
+package df
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestDataFrame_Arrange(t *testing.T) {
+	d, _ := New(
+		C{"Country", Strings("ES", "FR", "ES", nil, "FR", "ES")},
+		C{"Age", Ints(30, nil, 18, 40, 30, nil)},
+		C{"Amount", Decimals(2, "10.5", "2", "2", "1", "9.99", "3")},
+		C{"Id", Ints(0, 1, 2, 3, 4, 5)},
+	)
+	var tests = []struct {
+		keys     []SortKey
+		expected string
+	}{
+		{[]SortKey{{Column: "Age"}}, "[2 0 4 3 1 5]"},
+		{[]SortKey{{Column: "Age", NAFirst: true}}, "[1 5 2 0 4 3]"},
+		{[]SortKey{{Column: "Age", Desc: true}}, "[3 0 4 2 1 5]"},
+		{[]SortKey{{Column: "Country"}, {Column: "Amount", Desc: true}}, "[0 5 2 4 1 3]"},
+		{[]SortKey{{Column: "Amount"}}, "[3 1 2 5 4 0]"},
+		{[]SortKey{{Column: "Country", Desc: true, NAFirst: true}, {Column: "Age"}}, "[3 4 1 2 0 5]"},
+	}
+	for k, v := range tests {
+		dd, err := d.Arrange(v.keys...)
+		if err != nil {
+			t.Error("Test", k, ":", err)
+			continue
+		}
+		received := fmt.Sprint(dd.Columns["Id"].cells)
+		if v.expected != received {
+			t.Error(
+				"Test", k, "\n",
+				"Expected:\n",
+				v.expected, "\n",
+				"Received:\n",
+				received,
+			)
+		}
+	}
+	if fmt.Sprint(d.Columns["Id"].cells) != "[0 1 2 3 4 5]" {
+		t.Error("Original DataFrame was modified:", d.Columns["Id"].cells)
+	}
+
+	// Ordered categoricals are sorted by their levels
+	cats, _ := CategoricalsWithLevels([]string{"low", "mid", "high"}, true, "mid", "high", "low", nil)
+	d, _ = New(C{"Level", cats})
+	dd, err := d.Arrange(SortKey{Column: "Level"})
+	if err != nil {
+		t.Error(err)
+	}
+	if received := fmt.Sprint(dd.Columns["Level"].cells); received != "[low mid high NA]" {
+		t.Error("Expected: [low mid high NA] Received:", received)
+	}
+
+	if _, err := d.Arrange(SortKey{Column: "Unknown"}); err == nil {
+		t.Error("Sorting by an unknown column should throw an error")
+	}
+	if _, err := d.Arrange(); err == nil {
+		t.Error("Sorting without keys should throw an error")
+	}
+}
+
+func TestDataFrame_KeepOrder(t *testing.T) {
+	d, _ := New(
+		C{"A", Strings("c", "a", "c", "b", "a", "d")},
+		C{"B", Ints(1, 2, 1, 3, 2, 4)},
+	)
+	var tests = []struct {
+		f        func(...DuplicatesOptions) (*DataFrame, error)
+		expected string
+	}{
+		{d.Unique, "[b d]"},
+		{d.RemoveUnique, "[c a c a]"},
+		{d.RemoveDuplicated, "[c a b d]"},
+		{d.Duplicated, "[c a]"},
+	}
+	for k, v := range tests {
+		// Repeat to make sure the order doesn't depend on map iteration
+		for i := 0; i < 10; i++ {
+			dd, err := v.f(DuplicatesOptions{KeepOrder: true})
+			if err != nil {
+				t.Error("Test", k, ":", err)
+				break
+			}
+			received := fmt.Sprint(dd.Columns["A"].cells)
+			if v.expected != received {
+				t.Error(
+					"Test", k, "\n",
+					"Expected:\n",
+					v.expected, "\n",
+					"Received:\n",
+					received,
+				)
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,371 @@
+package df
+
+import (
+	"crypto/md5"
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// levels is the dictionary shared by all the cells of a Categorical column.
+// Once created a levels object is never modified, relabelling or reordering
+// the levels of a column creates a new one, which makes it safe to share it
+// between copies of the same cell.
+type levels struct {
+	names   []string
+	codes   map[string]int
+	sums    [][16]byte
+	ordered bool
+}
+
+// newLevels builds the dictionary for the given level names. The checksum of
+// every level is precomputed so that grouping, joining and Unique operations
+// over Categorical columns don't need to hash the labels on every row.
+func newLevels(names []string, ordered bool) (*levels, error) {
+	lvl := &levels{
+		names:   make([]string, 0, len(names)),
+		codes:   make(map[string]int, len(names)),
+		sums:    make([][16]byte, 0, len(names)),
+		ordered: ordered,
+	}
+	for _, v := range names {
+		if _, ok := lvl.codes[v]; ok {
+			return nil, errors.New("Duplicated level: " + v)
+		}
+		lvl.codes[v] = len(lvl.names)
+		lvl.names = append(lvl.names, v)
+		lvl.sums = append(lvl.sums, md5.Sum([]byte(v+"Categorical")))
+	}
+	return lvl, nil
+}
+
+// Categorical is a dictionary encoded string. Each cell stores an integer code
+// that points to one of the levels of the column, which are shared by all the
+// cells of said column.
+type Categorical struct {
+	c   *int
+	lvl *levels
+}
+
+// Copy returns a copy of a given Cell
+func (c Categorical) Copy() Cell {
+	if c.c == nil {
+		return Categorical{nil, c.lvl}
+	}
+	j := *c.c
+	return Categorical{&j, c.lvl}
+}
+
+// Int returns the integer value of the level of the Categorical
+func (c Categorical) Int() (*int, error) {
+	if c.c == nil {
+		return nil, errors.New("Could't convert to int")
+	}
+	s := c.String()
+	return String{&s}.Int()
+}
+
+// Float returns the float value of the level of the Categorical
+func (c Categorical) Float() (*float64, error) {
+	if c.c == nil {
+		return nil, errors.New("Could't convert to float64")
+	}
+	s := c.String()
+	return String{&s}.Float()
+}
+
+// Bool returns the bool value of the level of the Categorical
+func (c Categorical) Bool() (*bool, error) {
+	if c.c == nil {
+		return nil, errors.New("Could't convert to bool")
+	}
+	s := c.String()
+	return String{&s}.Bool()
+}
+
+func (c Categorical) String() string {
+	if c.c == nil {
+		return "NA"
+	}
+	return c.lvl.names[*c.c]
+}
+
+// Checksum generates a pseudo-unique 16 byte array. The checksums of the
+// levels are computed only once when the dictionary is built.
+func (c Categorical) Checksum() [16]byte {
+	if c.c == nil {
+		return md5.Sum([]byte("NACategorical"))
+	}
+	return c.lvl.sums[*c.c]
+}
+
+// NA returns the empty element for this type
+func (c Categorical) NA() Cell {
+	return Categorical{nil, c.lvl}
+}
+
+// IsNA returns true if the element is empty and viceversa
+func (c Categorical) IsNA() bool {
+	if c.c == nil {
+		return true
+	}
+	return false
+}
+
+// Code returns the integer code of the level stored in the Categorical
+func (c Categorical) Code() (*int, error) {
+	if c.c == nil {
+		return nil, errors.New("Empty value")
+	}
+	j := *c.c
+	return &j, nil
+}
+
+// Levels returns the list of levels of the Categorical in order
+func (c Categorical) Levels() []string {
+	if c.lvl == nil {
+		return nil
+	}
+	return append([]string(nil), c.lvl.names...)
+}
+
+// Ordered returns true if the levels of the Categorical have a meaningful
+// order that can be used on comparisons
+func (c Categorical) Ordered() bool {
+	return c.lvl != nil && c.lvl.ordered
+}
+
+// compareLiteral compares the level of the Categorical with the given label,
+// which is only possible if the levels are ordered and the label is one of
+// them. Equality is checked by equalLiteral instead.
+func (c Categorical) compareLiteral(label string) (int, bool) {
+	if c.c == nil || !c.Ordered() {
+		return 0, false
+	}
+	code, ok := c.lvl.codes[label]
+	if !ok {
+		return 0, false
+	}
+	return compareInts(*c.c, code), true
+}
+
+// equalLiteral reports whether the level of the Categorical is the given
+// label, which doesn't need to be one of the levels
+func (c Categorical) equalLiteral(label string) bool {
+	return c.c != nil && c.lvl.names[*c.c] == label
+}
+
+// categoricalLabels returns the labels of the given elements as pointers to
+// strings, being nil the NA elements.
+func categoricalLabels(args ...interface{}) []*string {
+	labels := []*string{}
+	for _, v := range args {
+		switch v.(type) {
+		case Cells:
+			for _, c := range v.(Cells) {
+				if c.IsNA() {
+					labels = append(labels, nil)
+				} else {
+					s := c.String()
+					labels = append(labels, &s)
+				}
+			}
+		default:
+			for _, c := range Strings(v) {
+				s := c.(String)
+				labels = append(labels, s.s)
+			}
+		}
+	}
+	return labels
+}
+
+// encodeCategoricals transforms the given labels into Categorical cells using
+// the given dictionary. Labels that are not levels of the dictionary will be
+// considered NA.
+func encodeCategoricals(labels []*string, lvl *levels) Cells {
+	ret := make(Cells, 0, len(labels))
+	for _, s := range labels {
+		if s == nil {
+			ret = append(ret, Categorical{nil, lvl})
+			continue
+		}
+		if code, ok := lvl.codes[*s]; ok {
+			ret = append(ret, Categorical{&code, lvl})
+		} else {
+			ret = append(ret, Categorical{nil, lvl})
+		}
+	}
+	return ret
+}
+
+// unifyLevels makes all the cells of a Categorical column share the same
+// dictionary. Cells with different levels meet when the rows of several
+// DataFrames are combined, and are encoded again with the levels of the first
+// dictionary followed by the ones missing from it. The new levels are only
+// ordered if all the dictionaries were ordered and had the same levels.
+func (col *column) unifyLevels() {
+	if col.colType != "df.Categorical" {
+		return
+	}
+	var dicts []*levels
+	seen := make(map[*levels]bool)
+	mixed := false
+	for _, v := range col.cells {
+		lvl := v.(Categorical).lvl
+		if lvl == nil {
+			mixed = true
+			continue
+		}
+		if !seen[lvl] {
+			seen[lvl] = true
+			dicts = append(dicts, lvl)
+		}
+	}
+	if len(dicts) == 0 || (len(dicts) == 1 && !mixed) {
+		return
+	}
+
+	first := dicts[0]
+	names := append([]string(nil), first.names...)
+	ordered := first.ordered
+	for _, lvl := range dicts[1:] {
+		same := lvl.ordered && len(lvl.names) == len(first.names)
+		for i, v := range lvl.names {
+			if _, ok := first.codes[v]; !ok {
+				names = append(names, v)
+				same = false
+			} else if same && first.names[i] != v {
+				same = false
+			}
+		}
+		ordered = ordered && same
+	}
+	lvl := first
+	if len(names) != len(first.names) || ordered != first.ordered {
+		lvl, _ = newLevels(names, ordered)
+	}
+
+	cells := make(Cells, 0, len(col.cells))
+	for _, v := range col.cells {
+		c := v.(Categorical)
+		if c.c == nil {
+			cells = append(cells, Categorical{nil, lvl})
+			continue
+		}
+		code := lvl.codes[c.lvl.names[*c.c]]
+		cells = append(cells, Categorical{&code, lvl})
+	}
+	col.cells = cells
+	col.empty = Categorical{nil, lvl}
+}
+
+// Categoricals is a constructor for a Categorical array. The levels will be
+// the unique values of the given elements sorted alphabetically and will not
+// be considered ordered.
+func Categoricals(args ...interface{}) Cells {
+	labels := categoricalLabels(args...)
+	seen := make(map[string]bool)
+	names := []string{}
+	for _, s := range labels {
+		if s != nil && !seen[*s] {
+			seen[*s] = true
+			names = append(names, *s)
+		}
+	}
+	sort.Strings(names)
+	lvl, _ := newLevels(names, false)
+	return encodeCategoricals(labels, lvl)
+}
+
+// CategoricalsWithLevels is a constructor for a Categorical array with the
+// given levels. If ordered is true the order of the levels will be used when
+// comparing elements. Elements that are not in levels will be NA.
+func CategoricalsWithLevels(levelNames []string, ordered bool, args ...interface{}) (Cells, error) {
+	lvl, err := newLevels(levelNames, ordered)
+	if err != nil {
+		return nil, err
+	}
+	return encodeCategoricals(categoricalLabels(args...), lvl), nil
+}
+
+// categoricalColumn returns the given column if it is of Categorical type
+func (df DataFrame) categoricalColumn(colname string) (column, error) {
+	col, ok := df.Columns[colname]
+	if !ok {
+		return column{}, errors.New("Can't find the given column: " + colname)
+	}
+	if col.colType != "df.Categorical" {
+		return column{}, fmt.Errorf("Column %s is not of type df.Categorical but %s", colname, col.colType)
+	}
+	return col, nil
+}
+
+// SetLevels returns a new DataFrame where the levels of the given Categorical
+// column have been replaced by levelNames. The order of levelNames will be
+// used on comparisons if ordered is true. Values that are not present on
+// levelNames will be NA.
+func (df DataFrame) SetLevels(colname string, levelNames []string, ordered bool) (*DataFrame, error) {
+	col, err := df.categoricalColumn(colname)
+	if err != nil {
+		return nil, err
+	}
+	cells, err := CategoricalsWithLevels(levelNames, ordered, col.cells)
+	if err != nil {
+		return nil, err
+	}
+	return df.withColumn(colname, cells)
+}
+
+// RenameLevels returns a new DataFrame where the levels of the given
+// Categorical column have been relabelled as indicated by the mapping. If
+// several levels are renamed to the same label they will be merged into
+// a single level, which keeps the position of the first of them.
+func (df DataFrame) RenameLevels(colname string, mapping map[string]string) (*DataFrame, error) {
+	col, err := df.categoricalColumn(colname)
+	if err != nil {
+		return nil, err
+	}
+	if len(col.cells) == 0 {
+		newDf := df.copy()
+		return &newDf, nil
+	}
+	old := col.cells[0].(Categorical).lvl
+	for k := range mapping {
+		if _, ok := old.codes[k]; !ok {
+			return nil, errors.New("Unknown level: " + k)
+		}
+	}
+
+	names := []string{}
+	seen := make(map[string]bool)
+	for _, v := range old.names {
+		if n, ok := mapping[v]; ok {
+			v = n
+		}
+		if !seen[v] {
+			seen[v] = true
+			names = append(names, v)
+		}
+	}
+	lvl, err := newLevels(names, old.ordered)
+	if err != nil {
+		return nil, err
+	}
+
+	cells := make(Cells, 0, len(col.cells))
+	for _, v := range col.cells {
+		c := v.(Categorical)
+		if c.c == nil {
+			cells = append(cells, Categorical{nil, lvl})
+			continue
+		}
+		label := c.lvl.names[*c.c]
+		if n, ok := mapping[label]; ok {
+			label = n
+		}
+		code := lvl.codes[label]
+		cells = append(cells, Categorical{&code, lvl})
+	}
+	return df.withColumn(colname, cells)
+}
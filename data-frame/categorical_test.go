@@ -0,0 +1,227 @@
+package df
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestCategoricals(t *testing.T) {
+	a := Categoricals("b", "a", nil, []string{"b", "c"})
+	expected := "[b a NA b c]"
+	received := fmt.Sprint(a)
+	if expected != received {
+		t.Error(
+			"Categorical elements not being propery inserted\n",
+			"Expected:\n",
+			expected, "\n",
+			"Received:\n",
+			received,
+		)
+	}
+
+	expected = "[a b c]"
+	received = fmt.Sprint(a[0].(Categorical).Levels())
+	if expected != received {
+		t.Error(
+			"Levels not being generated properly\n",
+			"Expected:\n",
+			expected, "\n",
+			"Received:\n",
+			received,
+		)
+	}
+
+	if a[0].Checksum() != a[3].Checksum() {
+		t.Error("Equal levels should have the same checksum")
+	}
+	if a[0].Checksum() == a[1].Checksum() {
+		t.Error("Different levels should have different checksums")
+	}
+	if !a[2].IsNA() {
+		t.Error("nil element should be NA")
+	}
+
+	b, err := CategoricalsWithLevels([]string{"low", "high"}, true, "high", "mid", Strings("low"))
+	if err != nil {
+		t.Error(err)
+	}
+	expected = "[high NA low]"
+	received = fmt.Sprint(b)
+	if expected != received {
+		t.Error(
+			"Elements not in levels should be NA\n",
+			"Expected:\n",
+			expected, "\n",
+			"Received:\n",
+			received,
+		)
+	}
+	_, err = CategoricalsWithLevels([]string{"low", "low"}, true)
+	if err == nil {
+		t.Error("Duplicated levels should throw an error")
+	}
+}
+
+func TestColumn_ParseColumnCategory(t *testing.T) {
+	col, _ := newCol("TestCol", Strings("UK", "US", "UK"))
+	err := col.ParseColumn("category")
+	if err != nil {
+		t.Error("Error parsing a df.String column into df.Categorical:", err)
+	}
+	if col.colType != "df.Categorical" || fmt.Sprint(col.cells) != "[UK US UK]" {
+		t.Error("Error parsing a df.String column into df.Categorical",
+			"\ncol.colType:", col.colType,
+			"\ncol.cells:", col.cells,
+		)
+	}
+}
+
+func TestDataFrame_Levels(t *testing.T) {
+	d, _ := New(
+		C{"Size", Categoricals("M", "S", "L", "M", "XL")},
+	)
+
+	// Ordered levels can be compared
+	do, err := d.SetLevels("Size", []string{"S", "M", "L"}, true)
+	if err != nil {
+		t.Error(err)
+	}
+	expected := "[M S L M NA]"
+	received := fmt.Sprint(do.Columns["Size"].cells)
+	if expected != received {
+		t.Error(
+			"Levels not being set properly\n",
+			"Expected:\n",
+			expected, "\n",
+			"Received:\n",
+			received,
+		)
+	}
+	gt := GtCondition("S")
+	lt := LtCondition("L")
+	cond := ArrayCondition{&gt, &lt}
+	dc, err := do.ConditionRows(map[string]Condition{"Size": &cond})
+	if err != nil {
+		t.Error(err)
+	}
+	if dc.NRows() != 2 {
+		t.Error("Expected 2 rows between S and L, received:", dc.NRows())
+	}
+
+	// Unordered levels can't be compared
+	for _, v := range d.Columns["Size"].cells {
+		if gt.Compare(v, "df.Categorical") || lt.Compare(v, "df.Categorical") {
+			t.Error("Unordered levels shouldn't be comparable:", v)
+		}
+	}
+
+	// Merging levels
+	dm, err := d.RenameLevels("Size", map[string]string{"XL": "L", "S": "Small"})
+	if err != nil {
+		t.Error(err)
+	}
+	expected = "[M Small L M L]"
+	received = fmt.Sprint(dm.Columns["Size"].cells)
+	if expected != received {
+		t.Error(
+			"Levels not being renamed properly\n",
+			"Expected:\n",
+			expected, "\n",
+			"Received:\n",
+			received,
+		)
+	}
+	expected = "[L M Small]"
+	received = fmt.Sprint(dm.Columns["Size"].cells[0].(Categorical).Levels())
+	if expected != received {
+		t.Error(
+			"Levels not being merged properly\n",
+			"Expected:\n",
+			expected, "\n",
+			"Received:\n",
+			received,
+		)
+	}
+	if _, err := d.RenameLevels("Size", map[string]string{"XXL": "L"}); err == nil {
+		t.Error("Unknown levels should throw an error")
+	}
+
+	// The original DataFrame is not modified
+	expected = "[M S L M XL]"
+	received = fmt.Sprint(d.Columns["Size"].cells)
+	if expected != received {
+		t.Error("Original DataFrame was modified:", received)
+	}
+}
+
+func TestDataFrame_CombineLevels(t *testing.T) {
+	a, _ := New(C{"A", Categoricals("x", nil)})
+	b, _ := New(C{"A", Categoricals("a", "b", "c")})
+
+	// The rows of both DataFrames share a single dictionary
+	r, err := Rbind(*a, *b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	u, err := UnionAll(*b, *a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	table := []struct {
+		df       *DataFrame
+		expected string
+	}{
+		{r, "[x NA a b c] [x a b c]"},
+		{u, "[a b c x NA] [a b c x]"},
+	}
+	for k, v := range table {
+		cells := v.df.Columns["A"].cells
+		received := fmt.Sprint(cells, cells[0].(Categorical).Levels())
+		for _, c := range cells {
+			if c.(Categorical).lvl != cells[0].(Categorical).lvl {
+				received += " (mixed levels)"
+				break
+			}
+		}
+		if v.expected != received {
+			t.Error(
+				"Test", k, "\n",
+				"Expected:\n",
+				v.expected, "\n",
+				"Received:\n",
+				received,
+			)
+		}
+	}
+
+	// Relabelling a combined column
+	dr, err := r.RenameLevels("A", map[string]string{"x": "y"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "[y NA a b c]"
+	received := fmt.Sprint(dr.Columns["A"].cells)
+	if expected != received {
+		t.Error(
+			"Levels not being renamed properly\n",
+			"Expected:\n",
+			expected, "\n",
+			"Received:\n",
+			received,
+		)
+	}
+
+	// Ordered levels stay ordered only if both dictionaries agree
+	s, _ := CategoricalsWithLevels([]string{"S", "M", "L"}, true, "M")
+	m, _ := CategoricalsWithLevels([]string{"S", "M", "L"}, true, "L")
+	l, _ := CategoricalsWithLevels([]string{"S", "L"}, true, "S")
+	ds, _ := New(C{"Size", s})
+	dm, _ := New(C{"Size", m})
+	dl, _ := New(C{"Size", l})
+	if d, _ := Rbind(*ds, *dm); !d.Columns["Size"].cells[0].(Categorical).Ordered() {
+		t.Error("Equal ordered levels should stay ordered")
+	}
+	if d, _ := Rbind(*ds, *dl); d.Columns["Size"].cells[0].(Categorical).Ordered() {
+		t.Error("Different ordered levels shouldn't stay ordered")
+	}
+}
@@ -0,0 +1,433 @@
+package df
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+)
+
+// defaultChunkSize is the number of rows of the chunks when none is given
+const defaultChunkSize = 10000
+
+// Chunks is a sequence of DataFrames with the same columns, like the chunks
+// of a file too big to be loaded at once. Next returns io.EOF after the last
+// chunk.
+type Chunks interface {
+	Next() (*DataFrame, error)
+}
+
+// ChunkOptions configures a ChunkReader. ChunkSize is the maximum number of
+// rows of each chunk, 10000 by default. Types are used to parse the columns of
+// every chunk as on Parse; without them all the columns are strings.
+type ChunkOptions struct {
+	ChunkSize int
+	Types     interface{}
+	Comma     rune
+}
+
+// ChunkReader reads a CSV file with a header in DataFrames of a fixed number
+// of rows, so the whole file never has to be in memory
+type ChunkReader struct {
+	r      *csv.Reader
+	header []string
+	opts   ChunkOptions
+	done   bool
+}
+
+// NewChunkReader returns a ChunkReader for the CSV data of r, reading its
+// header
+func NewChunkReader(r io.Reader, opts ...ChunkOptions) (*ChunkReader, error) {
+	var o ChunkOptions
+	if len(opts) > 0 {
+		o = opts[0]
+	}
+	if o.ChunkSize <= 0 {
+		o.ChunkSize = defaultChunkSize
+	}
+	cr := csv.NewReader(r)
+	if o.Comma != 0 {
+		cr.Comma = o.Comma
+	}
+	header, err := cr.Read()
+	if err == io.EOF {
+		return nil, errors.New("Empty CSV file")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &ChunkReader{r: cr, header: header, opts: o}, nil
+}
+
+// Names returns the column names of the header
+func (c *ChunkReader) Names() []string {
+	return append([]string(nil), c.header...)
+}
+
+// Next returns the next chunk of rows, or io.EOF if there are no more
+func (c *ChunkReader) Next() (*DataFrame, error) {
+	if c.done {
+		return nil, io.EOF
+	}
+	records := [][]string{append([]string(nil), c.header...)}
+	for len(records) <= c.opts.ChunkSize {
+		record, err := c.r.Read()
+		if err == io.EOF {
+			c.done = true
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, record)
+	}
+	if len(records) == 1 {
+		return nil, io.EOF
+	}
+
+	d := &DataFrame{}
+	if err := d.LoadData(records); err != nil {
+		return nil, err
+	}
+	if c.opts.Types != nil {
+		if err := d.Parse(c.opts.Types); err != nil {
+			return nil, err
+		}
+	}
+	return d, nil
+}
+
+// chunkFunc applies a function to each chunk of a sequence
+type chunkFunc struct {
+	chunks Chunks
+	f      func(d DataFrame) (*DataFrame, error)
+	// last is the last empty chunk, kept until a non empty one is returned
+	last *DataFrame
+	sent bool
+}
+
+// Next returns the next non empty chunk after applying the function. If all
+// the chunks end up empty the last of them is returned, so that the columns
+// of the sequence are still known.
+func (c *chunkFunc) Next() (*DataFrame, error) {
+	for {
+		d, err := c.chunks.Next()
+		if err == io.EOF && !c.sent && c.last != nil {
+			d, c.last, c.sent = c.last, nil, true
+			return d, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if d, err = c.f(*d); err != nil {
+			return nil, err
+		}
+		if d.nRows > 0 {
+			c.last, c.sent = nil, true
+			return d, nil
+		}
+		c.last = d
+	}
+}
+
+// FilterChunks returns the rows of each chunk where the boolean expression
+// expr is true. See Mutate for the syntax of the expressions. Chunks without
+// matching rows are skipped, unless no chunk has any, in which case a single
+// empty chunk is returned.
+func FilterChunks(chunks Chunks, expr string) Chunks {
+	return &chunkFunc{chunks: chunks, f: func(d DataFrame) (*DataFrame, error) {
+		if _, err := predicateRefs(d, expr); err != nil {
+			return nil, err
+		}
+		d, err := d.filterRows([]string{expr})
+		return &d, err
+	}}
+}
+
+// MutateChunks adds to each chunk the column colname with the result of the
+// expression expr, as Mutate does
+func MutateChunks(chunks Chunks, colname, expr string) Chunks {
+	return &chunkFunc{chunks: chunks, f: func(d DataFrame) (*DataFrame, error) {
+		return d.Mutate(colname, expr)
+	}}
+}
+
+// CollectChunks reads all the chunks and binds them by rows
+func CollectChunks(chunks Chunks) (*DataFrame, error) {
+	var ret *DataFrame
+	for {
+		d, err := chunks.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if ret == nil {
+			ret = d
+		} else if ret, err = Rbind(*ret, *d); err != nil {
+			return nil, err
+		}
+	}
+	if ret == nil {
+		return nil, errors.New("No chunks")
+	}
+	return ret, nil
+}
+
+// aggState is the partial result of an aggregation over the chunks read so
+// far. Means and standard deviations are updated with Welford's algorithm
+// from the count of values, their mean and the sum of squared deviations.
+type aggState struct {
+	count       int
+	sum         float64
+	mean, m2    float64
+	min, max    float64
+	first, last Cell
+}
+
+func (s *aggState) add(c Cell, numeric bool) error {
+	if c.IsNA() {
+		return nil
+	}
+	if s.count == 0 {
+		s.first = c
+	}
+	s.last = c
+	s.count++
+	if !numeric {
+		return nil
+	}
+	f, err := c.Float()
+	if err != nil {
+		return err
+	}
+	v := *f
+	if s.count == 1 || v < s.min {
+		s.min = v
+	}
+	if s.count == 1 || v > s.max {
+		s.max = v
+	}
+	s.sum += v
+	delta := v - s.mean
+	s.mean += delta / float64(s.count)
+	s.m2 += delta * (v - s.mean)
+	return nil
+}
+
+// result returns the value of the aggregation, with the same types as on
+// Resample
+func (s *aggState) result(agg Aggregation, empty Cell) Cell {
+	switch agg {
+	case AggCount:
+		n := s.count
+		return Int{&n}
+	case AggFirst, AggLast:
+		if s.count == 0 {
+			return empty.NA()
+		}
+		if agg == AggFirst {
+			return s.first
+		}
+		return s.last
+	}
+	f := math.NaN()
+	switch {
+	case s.count == 0:
+	case agg == AggSum:
+		f = s.sum
+	case agg == AggMean:
+		f = s.mean
+	case agg == AggMin:
+		f = s.min
+	case agg == AggMax:
+		f = s.max
+	case agg == AggStd && s.count > 1:
+		f = math.Sqrt(s.m2 / float64(s.count-1))
+	}
+	if math.IsNaN(f) {
+		return Float{nil}
+	}
+	return Float{&f}
+}
+
+// chunkGroup holds the keys of a group and the state of its aggregations
+type chunkGroup struct {
+	keys   Cells
+	states []aggState
+}
+
+// aggregator computes grouped aggregations over a sequence of DataFrames. The
+// columns of the first one define the types of the result.
+type aggregator struct {
+	groupBy []string
+	aggs    []Aggregate
+	groups  []*chunkGroup
+	index   map[string]*chunkGroup
+	keyCols []column
+	aggCols []column
+}
+
+func newAggregator(groupBy []string, aggs []Aggregate) (*aggregator, error) {
+	if len(aggs) == 0 {
+		return nil, errors.New("No aggregations given")
+	}
+	return &aggregator{
+		groupBy: groupBy,
+		aggs:    aggs,
+		index:   map[string]*chunkGroup{},
+	}, nil
+}
+
+// add updates the groups with the rows of d
+func (a *aggregator) add(d DataFrame) error {
+	var err error
+	keyCols := make([]column, len(a.groupBy))
+	for j, k := range a.groupBy {
+		if keyCols[j], err = d.col(k); err != nil {
+			return err
+		}
+	}
+	cols := make([]column, len(a.aggs))
+	numeric := make([]bool, len(a.aggs))
+	for j, agg := range a.aggs {
+		if cols[j], err = d.col(agg.Column); err != nil {
+			return err
+		}
+		switch agg.Agg {
+		case AggCount, AggFirst, AggLast:
+		default:
+			if numericKind(cols[j].empty) == kindNone {
+				return fmt.Errorf("column %s: can't aggregate non numeric elements", agg.Column)
+			}
+			numeric[j] = true
+		}
+	}
+	if a.keyCols == nil {
+		a.keyCols, a.aggCols = keyCols, cols
+	}
+
+	for i := 0; i < d.nRows; i++ {
+		key := rowChecksum(d, a.groupBy, i)
+		g, ok := a.index[key]
+		if !ok {
+			g = &chunkGroup{states: make([]aggState, len(a.aggs))}
+			for _, c := range keyCols {
+				g.keys = append(g.keys, c.cells[i])
+			}
+			a.index[key] = g
+			a.groups = append(a.groups, g)
+		}
+		for j, c := range cols {
+			if err := g.states[j].add(c.cells[i], numeric[j]); err != nil {
+				return fmt.Errorf("column %s: %v", a.aggs[j].Column, err)
+			}
+		}
+	}
+	return nil
+}
+
+// result returns a DataFrame with a row per group. Without groupBy columns
+// there is always a single row, even if no rows were added.
+func (a *aggregator) result() (*DataFrame, error) {
+	groups := a.groups
+	if len(groups) == 0 && len(a.groupBy) == 0 {
+		groups = []*chunkGroup{{states: make([]aggState, len(a.aggs))}}
+	}
+
+	newDf := DataFrame{
+		Columns:   map[string]column{},
+		colIndexs: map[string]int{},
+		nRows:     len(groups),
+	}
+	add := func(name string, cells Cells, empty Cell) error {
+		if _, ok := newDf.Columns[name]; ok {
+			return errors.New("duplicate column name: " + name)
+		}
+		col := emptyCol(name, empty)
+		if len(cells) > 0 {
+			c, err := newCol(name, cells)
+			if err != nil {
+				return err
+			}
+			col = *c
+		}
+		newDf.Columns[name] = col
+		newDf.colIndexs[name] = len(newDf.colIndexs)
+		return nil
+	}
+	for j, k := range a.groupBy {
+		cells := make(Cells, 0, len(groups))
+		for _, g := range groups {
+			cells = append(cells, g.keys[j])
+		}
+		if err := add(k, cells, a.keyCols[j].empty); err != nil {
+			return nil, err
+		}
+	}
+	for j, agg := range a.aggs {
+		cells := make(Cells, 0, len(groups))
+		for _, g := range groups {
+			cells = append(cells, g.states[j].result(agg.Agg, a.aggCols[j].empty))
+		}
+		// The result over no values has the type of the aggregation
+		empty := (&aggState{}).result(agg.Agg, a.aggCols[j].empty)
+		if err := add(agg.name(), cells, empty); err != nil {
+			return nil, err
+		}
+	}
+	return &newDf, nil
+}
+
+// AggregateChunks groups the rows of all the chunks by the values of the
+// columns groupBy and computes the given aggregations for each group, reading
+// one chunk at a time. The result is the same as calling GroupBy over all
+// the rows, also when they were all filtered out. If there are no chunks at
+// all, like on a CSV file with only a header, the columns are unknown and an
+// error is returned instead.
+func AggregateChunks(chunks Chunks, groupBy []string, aggs ...Aggregate) (*DataFrame, error) {
+	a, err := newAggregator(groupBy, aggs)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		d, err := chunks.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if err := a.add(*d); err != nil {
+			return nil, err
+		}
+	}
+	if a.keyCols == nil {
+		return nil, errors.New("No rows to aggregate")
+	}
+	return a.result()
+}
+
+// GroupBy groups the rows by the values of the columns groupBy and computes
+// the given aggregations for each group. The result has a row per group, in
+// order of appearance, with the group columns followed by the aggregations.
+// Without groupBy columns all the rows form a single group, so the result
+// has one row even if the DataFrame is empty. Aggregations and their result
+// types are the same as on Resample.
+//
+//	d.GroupBy([]string{"City"},
+//		df.Aggregate{Column: "Amount", Agg: df.AggSum},
+//		df.Aggregate{Column: "Amount", Agg: df.AggCount, Name: "n"},
+//	)
+func (df DataFrame) GroupBy(groupBy []string, aggs ...Aggregate) (*DataFrame, error) {
+	a, err := newAggregator(groupBy, aggs)
+	if err != nil {
+		return nil, err
+	}
+	if err := a.add(df); err != nil {
+		return nil, err
+	}
+	return a.result()
+}
@@ -0,0 +1,212 @@
+package df
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+)
+
+const chunksCsv = `City,Amount,Qty
+Oslo,10.5,1
+Rome,3,2
+Oslo,,3
+Lima,7.25,4
+Rome,1,5
+Oslo,2,6
+Rome,,7
+`
+
+func TestChunkReader(t *testing.T) {
+	r, err := NewChunkReader(strings.NewReader(chunksCsv), ChunkOptions{
+		ChunkSize: 3,
+		Types:     T{"Amount": "float", "Qty": "int"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(r.Names()) != "[City Amount Qty]" {
+		t.Error("Unexpected header:", r.Names())
+	}
+	sizes := []int{}
+	for {
+		d, err := r.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if d.Columns["Qty"].colType != "df.Int" {
+			t.Error("Chunk not parsed:", d.Columns["Qty"].colType)
+		}
+		sizes = append(sizes, d.NRows())
+	}
+	if fmt.Sprint(sizes) != "[3 3 1]" {
+		t.Error("Expected chunks of [3 3 1] rows, received", sizes)
+	}
+
+	if _, err := NewChunkReader(strings.NewReader("")); err == nil {
+		t.Error("Expected an error reading an empty file")
+	}
+	r, _ = NewChunkReader(strings.NewReader("A,B\n1,2\n3\n"))
+	if _, err := r.Next(); err == nil {
+		t.Error("Expected an error reading a malformed file")
+	}
+}
+
+// emptyChunks is a sequence without chunks
+type emptyChunks struct{}
+
+func (emptyChunks) Next() (*DataFrame, error) { return nil, io.EOF }
+
+func TestChunks_Streaming(t *testing.T) {
+	types := T{"Amount": "float", "Qty": "int"}
+	whole := DataFrame{}
+	whole.LoadCsv([]byte(chunksCsv))
+	whole.Parse(types)
+
+	for _, size := range []int{1, 2, 3, 100} {
+		open := func() Chunks {
+			r, err := NewChunkReader(strings.NewReader(chunksCsv), ChunkOptions{ChunkSize: size, Types: types})
+			if err != nil {
+				t.Fatal(err)
+			}
+			return r
+		}
+
+		filtered := MutateChunks(FilterChunks(open(), "Qty > 1 && City != \"Lima\""), "Total", "Amount * Qty")
+		received, err := CollectChunks(filtered)
+		if err != nil {
+			t.Error("Size", size, ":", err)
+			continue
+		}
+		expected, _ := whole.Lazy().Filter("Qty > 1 && City != \"Lima\"").Collect()
+		expected, _ = expected.Mutate("Total", "Amount * Qty")
+		if diff := Differences(*received, *expected); diff != nil {
+			t.Error("Size", size, ": streaming filter and mutate differ:", diff)
+		}
+
+		agg, err := AggregateChunks(open(), []string{"City"},
+			Aggregate{Column: "Amount", Agg: AggMean},
+			Aggregate{Column: "Amount", Agg: AggSum},
+			Aggregate{Column: "Amount", Agg: AggCount},
+			Aggregate{Column: "Qty", Agg: AggStd, Name: "sd"},
+			Aggregate{Column: "Qty", Agg: AggMax},
+			Aggregate{Column: "City", Agg: AggFirst},
+		)
+		if err != nil {
+			t.Error("Size", size, ":", err)
+			continue
+		}
+		expectedAgg := "[[City Amount_mean Amount_sum Amount_count sd Qty_max City_first] " +
+			"[Oslo 6.25 12.5 2 2.5166114784235836 6 Oslo] " +
+			"[Rome 2 4 2 2.516611478423583 7 Rome] " +
+			"[Lima 7.25 7.25 1 NA 4 Lima]]"
+		if received := fmt.Sprint(agg.SaveRecords()); received != expectedAgg {
+			t.Error(
+				"Size", size, "\n",
+				"Expected:\n",
+				expectedAgg, "\n",
+				"Received:\n",
+				received,
+			)
+		}
+
+		// Without groups the whole file is a single group
+		total, err := AggregateChunks(open(), nil, Aggregate{Column: "Amount", Agg: AggMean})
+		if err != nil {
+			t.Error("Size", size, ":", err)
+			continue
+		}
+		amounts, _ := whole.Column("Amount")
+		m, _ := Mean(amounts)
+		if received := total.Columns["Amount_mean"].cells[0].String(); received != fmt.Sprint(m) {
+			t.Error("Size", size, ": expected a mean of", m, "received", received)
+		}
+	}
+
+	// Filtering out all the rows gives the same result as GroupBy
+	for _, groupBy := range [][]string{nil, {"City"}} {
+		r, _ := NewChunkReader(strings.NewReader(chunksCsv), ChunkOptions{ChunkSize: 2, Types: types})
+		received, err := AggregateChunks(FilterChunks(r, "Qty > 100"), groupBy, Aggregate{Column: "Amount", Agg: AggSum})
+		if err != nil {
+			t.Error(groupBy, err)
+			continue
+		}
+		none, _ := whole.Lazy().Filter("Qty > 100").Collect()
+		expected, _ := none.GroupBy(groupBy, Aggregate{Column: "Amount", Agg: AggSum})
+		if diff := Differences(*received, *expected); diff != nil {
+			t.Error(groupBy, ": aggregation of no rows differs from GroupBy:", diff)
+		}
+	}
+	r, _ := NewChunkReader(strings.NewReader(chunksCsv))
+	if d, err := CollectChunks(FilterChunks(r, "City == \"Paris\"")); err != nil || d.NRows() != 0 || d.NCols() != 3 {
+		t.Error("Expected an empty DataFrame keeping the columns:", d, err)
+	}
+	if _, err := AggregateChunks(emptyChunks{}, nil, Aggregate{Column: "Amount", Agg: AggSum}); err == nil {
+		t.Error("Expected an error aggregating no chunks")
+	}
+
+	r, _ = NewChunkReader(strings.NewReader(chunksCsv))
+	if _, err := AggregateChunks(r, []string{"City"}, Aggregate{Column: "City", Agg: AggSum}); err == nil {
+		t.Error("Expected an error adding strings")
+	}
+	r, _ = NewChunkReader(strings.NewReader(chunksCsv))
+	if _, err := CollectChunks(FilterChunks(r, "Unknown > 1")); err == nil {
+		t.Error("Expected an error filtering by an unknown column")
+	}
+}
+
+func TestDataFrame_GroupBy(t *testing.T) {
+	d, _ := New(
+		C{"City", Strings("Oslo", "Rome", "Oslo", nil)},
+		C{"Amount", Floats(1, 2, 3, 4)},
+	)
+	empty := d.Empty()
+	aggs := []Aggregate{
+		{Column: "Amount", Agg: AggSum},
+		{Column: "Amount", Agg: AggCount, Name: "n"},
+		{Column: "City", Agg: AggFirst},
+	}
+	var tests = []struct {
+		d        DataFrame
+		groupBy  []string
+		expected string
+		types    string
+	}{
+		{*d, []string{"City"}, "[[City Amount_sum n City_first] [Oslo 4 2 Oslo] [Rome 2 1 Rome] [NA 4 1 NA]]",
+			"[df.String df.Float df.Int df.String]"},
+		{*d, nil, "[[Amount_sum n City_first] [10 4 Oslo]]", "[df.Float df.Int df.String]"},
+		{*empty, []string{"City"}, "[[City Amount_sum n City_first]]", "[df.String df.Float df.Int df.String]"},
+		{*empty, nil, "[[Amount_sum n City_first] [NA 0 NA]]", "[df.Float df.Int df.String]"},
+	}
+	for k, v := range tests {
+		g, err := v.d.GroupBy(v.groupBy, aggs...)
+		if err != nil {
+			t.Error("Test", k, ":", err)
+			continue
+		}
+		received := fmt.Sprint(g.SaveRecords())
+		types := fmt.Sprint(g.Types())
+		if received != v.expected || types != v.types {
+			t.Error(
+				"Test", k, "\n",
+				"Expected:\n",
+				v.expected, v.types, "\n",
+				"Received:\n",
+				received, types,
+			)
+		}
+	}
+
+	if _, err := d.GroupBy([]string{"City"}); err == nil {
+		t.Error("Expected an error without aggregations")
+	}
+	if _, err := d.GroupBy([]string{"Unknown"}, aggs...); err == nil {
+		t.Error("Expected an error grouping by an unknown column")
+	}
+	if _, err := d.GroupBy(nil, Aggregate{Column: "City", Agg: AggMean}); err == nil {
+		t.Error("Expected an error averaging strings")
+	}
+}
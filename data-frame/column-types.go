@@ -194,7 +194,8 @@ func (i Int) Copy() Cell {
 // Int returns the integer value of Int
 func (i Int) Int() (*int, error) {
 	if i.i != nil {
-		return i.i, nil
+		j := *i.i
+		return &j, nil
 	}
 	return nil, errors.New("Could't convert to int")
 }
@@ -374,7 +375,8 @@ func (f Float) Int() (*int, error) {
 // Float returns the float value of Float
 func (f Float) Float() (*float64, error) {
 	if f.f != nil {
-		return f.f, nil
+		g := *f.f
+		return &g, nil
 	}
 	return nil, errors.New("Could't convert to float64")
 }
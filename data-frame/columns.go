@@ -7,11 +7,10 @@ import (
 
 // column represents a column inside a DataFrame
 type column struct {
-	cells    Cells
-	colType  string
-	colName  string
-	numChars int
-	empty    Cell
+	cells   Cells
+	colType string
+	colName string
+	empty   Cell
 }
 
 // newCol is the constructor for a new Column with the given colName and elements
@@ -27,62 +26,30 @@ func newCol(colName string, elements Cells) (*column, error) {
 	return &col, nil
 }
 
+// ParseColumn converts the column to the registered type with the given name
 func (col *column) ParseColumn(t string) error {
-	switch t {
-	case "string":
-		newcells := Strings(col.cells)
-		newcol, err := newCol(col.colName, newcells)
-		if err != nil {
-			return err
-		}
-		*col = *newcol
-	case "int":
-		newcells := Ints(col.cells)
-		newcol, err := newCol(col.colName, newcells)
-		if err != nil {
-			return err
-		}
-		*col = *newcol
-	case "float":
-		newcells := Floats(col.cells)
-		newcol, err := newCol(col.colName, newcells)
-		if err != nil {
-			return err
-		}
-		*col = *newcol
-	case "bool":
-		newcells := Bools(col.cells)
-		newcol, err := newCol(col.colName, newcells)
-		if err != nil {
-			return err
-		}
-		*col = *newcol
-	default:
+	ct, ok := lookupType(t)
+	if !ok {
 		return errors.New("Can't parse the given type")
 	}
-
-	return nil
-}
-
-func (col *column) recountNumChars() {
-	numChars := len(col.colName)
-	for _, cell := range col.cells {
-		cellStr := cell.String()
-		if len(cellStr) > numChars {
-			numChars = len(cellStr)
-		}
+	newcol, err := newCol(col.colName, ct.parseCells(col.cells))
+	if err != nil {
+		return err
 	}
+	*col = *newcol
 
-	col.numChars = numChars
+	return nil
 }
 
 // Append will add a value or values to a column
 func (col column) append(values ...Cell) (column, error) {
 	if len(values) == 0 {
-		col.recountNumChars()
 		return col, nil
 	}
 
+	// The buffer may be shared with other columns, so it is clipped to force
+	// append to allocate a new one instead of writing past its length
+	col.cells = col.cells[:len(col.cells):len(col.cells)]
 	col.empty = values[0].NA()
 	for _, v := range values {
 		t := reflect.TypeOf(v).String()
@@ -96,23 +63,48 @@ func (col column) append(values ...Cell) (column, error) {
 
 		col.cells = append(col.cells, v)
 	}
-
-	col.recountNumChars()
+	col.unifyLevels()
 
 	return col, nil
 }
 
+// emptyCol returns a column without elements with the type of empty
+func emptyCol(colName string, empty Cell) column {
+	col := column{
+		colName: colName,
+		colType: reflect.TypeOf(empty).String(),
+		empty:   empty.NA(),
+	}
+	return col
+}
+
+// take returns a column with the elements on the given rows, in order.
+// Negative rows produce NA elements. Elements are shared, not copied.
+func (col column) take(rows []int) column {
+	cells := make(Cells, 0, len(rows))
+	for _, i := range rows {
+		if i < 0 {
+			cells = append(cells, col.empty)
+		} else {
+			cells = append(cells, col.cells[i])
+		}
+	}
+	col.cells = cells
+	return col
+}
+
+// copy returns a deep copy of the column, used when its elements are handed
+// out of the package
 func (col column) copy() column {
 	cs := make(Cells, 0, len(col.cells))
 	for _, v := range col.cells {
 		cs = append(cs, v.Copy())
 	}
 	newcol := column{
-		cells:    cs,
-		colType:  col.colType,
-		colName:  col.colName,
-		numChars: col.numChars,
-		empty:    col.empty.Copy(),
+		cells:   cs,
+		colType: col.colType,
+		colName: col.colName,
+		empty:   col.empty.Copy(),
 	}
 	return newcol
 }
@@ -101,6 +101,17 @@ func TestColumn_ParseColumn(t *testing.T) {
 		)
 	}
 
+	// NA elements are kept when parsing to String
+	cola, _ = newCol("TestCol", Ints(1, nil))
+	colb = cola.copy()
+	err = colb.ParseColumn("string")
+	if err != nil {
+		t.Error("Error parsing a df.Int column into df.String:", err)
+	}
+	if colb.colType != "df.String" || colb.cells[0].IsNA() || !colb.cells[1].IsNA() {
+		t.Error("Error keeping NA elements when parsing into df.String:", colb.cells)
+	}
+
 	// Float to String
 	cola, _ = newCol("TestCol", Floats(1, 2))
 	colb = cola.copy()
@@ -258,37 +269,33 @@ func TestColumn_na(t *testing.T) {
 		expHasNa bool
 	}{
 		{data: column{
-			cells:    Strings("A", "B"),
-			colType:  "df.String",
-			colName:  "A",
-			numChars: 1,
+			cells:   Strings("A", "B"),
+			colType: "df.String",
+			colName: "A",
 		},
 			expNa:    []bool{false, false},
 			expHasNa: false,
 		},
 		{data: column{
-			cells:    Ints(1, 2, 3, 4),
-			colType:  "df.Int",
-			colName:  "B",
-			numChars: 1,
+			cells:   Ints(1, 2, 3, 4),
+			colType: "df.Int",
+			colName: "B",
 		},
 			expNa:    []bool{false, false, false, false},
 			expHasNa: false,
 		},
 		{data: column{
-			cells:    Floats(1.0, 2.0, nil, 3.0),
-			colType:  "df.Float",
-			colName:  "C",
-			numChars: 1,
+			cells:   Floats(1.0, 2.0, nil, 3.0),
+			colType: "df.Float",
+			colName: "C",
 		},
 			expNa:    []bool{false, false, true, false},
 			expHasNa: true,
 		},
 		{data: column{
-			cells:    Bools(true, nil, false),
-			colType:  "df.Bool",
-			colName:  "A",
-			numChars: 1,
+			cells:   Bools(true, nil, false),
+			colType: "df.Bool",
+			colName: "A",
 		},
 			expNa:    []bool{false, true, false},
 			expHasNa: true,
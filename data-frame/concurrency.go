@@ -0,0 +1,106 @@
+package df
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// workerCount is the number of goroutines used by the operations that process
+// the columns of a DataFrame in parallel. Zero means runtime.GOMAXPROCS.
+var workerCount int32
+
+// SetWorkers sets the maximum number of goroutines used to process the columns
+// of a DataFrame in parallel when loading, parsing or filling it. A value
+// lower than one restores the default, which is the value of
+// runtime.GOMAXPROCS. Setting it to one processes the columns sequentially.
+func SetWorkers(n int) {
+	if n < 1 {
+		n = 0
+	}
+	atomic.StoreInt32(&workerCount, int32(n))
+}
+
+// Workers returns the maximum number of goroutines used to process the
+// columns of a DataFrame in parallel
+func Workers() int {
+	if n := atomic.LoadInt32(&workerCount); n > 0 {
+		return int(n)
+	}
+	return runtime.GOMAXPROCS(0)
+}
+
+// parallel calls f for every index from 0 to n-1 using at most Workers()
+// goroutines. If any call fails the error of the lowest index is returned, so
+// the result doesn't depend on the scheduling of the goroutines.
+func parallel(n int, f func(i int) error) error {
+	workers := Workers()
+	if workers > n {
+		workers = n
+	}
+	errs := make([]error, n)
+	if workers <= 1 {
+		for i := 0; i < n; i++ {
+			errs[i] = f(i)
+		}
+	} else {
+		var wg sync.WaitGroup
+		next := int32(-1)
+		wg.Add(workers)
+		for w := 0; w < workers; w++ {
+			go func() {
+				defer wg.Done()
+				for i := int(atomic.AddInt32(&next, 1)); i < n; i = int(atomic.AddInt32(&next, 1)) {
+					errs[i] = f(i)
+				}
+			}()
+		}
+		wg.Wait()
+	}
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Shared holds a DataFrame that can be read and replaced by several
+// goroutines. Readers get a snapshot that is never modified afterwards, and
+// writers replace the DataFrame with the result of an operation over the
+// current one. Updates are serialized, so none of them is lost, but they
+// don't block readers while they run.
+type Shared struct {
+	mu      sync.RWMutex
+	updates sync.Mutex
+	df      DataFrame
+}
+
+// NewShared returns a Shared holding the given DataFrame
+func NewShared(df DataFrame) *Shared {
+	return &Shared{df: df.copy()}
+}
+
+// Load returns the current DataFrame. The returned value owns its column
+// maps, so modifying it won't affect the Shared or other readers.
+func (s *Shared) Load() DataFrame {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.df.copy()
+}
+
+// Update replaces the DataFrame with the result of calling f with the current
+// one. If f returns an error the DataFrame is kept. Readers see either the old
+// or the new DataFrame, never an intermediate state.
+func (s *Shared) Update(f func(df DataFrame) (*DataFrame, error)) error {
+	s.updates.Lock()
+	defer s.updates.Unlock()
+	newDf, err := f(s.Load())
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	s.df = newDf.copy()
+	s.mu.Unlock()
+	return nil
+}
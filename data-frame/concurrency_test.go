@@ -0,0 +1,159 @@
+package df
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+func TestParallel(t *testing.T) {
+	defer SetWorkers(0)
+	for _, workers := range []int{1, 3, 100} {
+		SetWorkers(workers)
+		if Workers() != workers {
+			t.Error("Expected", workers, "workers, received", Workers())
+		}
+		visited := make([]int, 50)
+		err := parallel(len(visited), func(i int) error {
+			visited[i]++
+			if i == 7 || i == 30 {
+				return errors.New("error " + strconv.Itoa(i))
+			}
+			return nil
+		})
+		if err == nil || err.Error() != "error 7" {
+			t.Error("Workers", workers, ": expected the error of the lowest index, received", err)
+		}
+		for i, v := range visited {
+			if v != 1 {
+				t.Error("Workers", workers, ": index", i, "visited", v, "times")
+			}
+		}
+	}
+	SetWorkers(-1)
+	if Workers() < 1 {
+		t.Error("Expected the default number of workers, received", Workers())
+	}
+}
+
+func TestDataFrame_ParallelLoad(t *testing.T) {
+	defer SetWorkers(0)
+	records := [][]string{{"A", "B", "C", "D"}}
+	for i := 0; i < 100; i++ {
+		s := strconv.Itoa(i)
+		records = append(records, []string{s, s + ".5", "x" + s, "true"})
+	}
+	types := []string{"int", "float", "string", "bool"}
+
+	SetWorkers(1)
+	var expected DataFrame
+	if err := expected.LoadAndParse(records, types); err != nil {
+		t.Fatal(err)
+	}
+	SetWorkers(4)
+	var received DataFrame
+	if err := received.LoadAndParse(records, types); err != nil {
+		t.Fatal(err)
+	}
+	if diff := Differences(received, expected); diff != nil {
+		t.Error("Parallel parsing differs from the sequential one:", diff)
+	}
+
+	// A failing column leaves the DataFrame untouched
+	err := received.Parse(T{"A": "float", "B": "decimal(x"})
+	if err == nil {
+		t.Error("Expected an error parsing an unknown type")
+	}
+	if received.Columns["A"].colType != "df.Int" {
+		t.Error("Parse modified the DataFrame after an error:", received.Columns["A"].colType)
+	}
+}
+
+// Run with -race to check the concurrency contract
+func TestDataFrame_ConcurrentReads(t *testing.T) {
+	d, _ := New(
+		C{"A", Strings("a", "b", "a", nil)},
+		C{"B", Ints(1, 2, 3, 4)},
+		C{"C", Floats(1.5, nil, 2.5, 4.0)},
+	)
+	expected := fmt.Sprint(d.SaveRecords())
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 20; i++ {
+				d1, err := d.SubsetRows([]int{3, 1})
+				if err == nil {
+					d1, err = d1.Mutate("D", "B * 2")
+				}
+				if err == nil {
+					d1, err = Rbind(*d, *d)
+				}
+				if err == nil {
+					d1, err = d.FillNA(FillNAOptions{Strategy: FillForward})
+				}
+				if err == nil {
+					d1, err = d.Arrange(SortKey{Column: "C"})
+				}
+				if err == nil {
+					_ = d1.String()
+					_, err = d.Describe()
+				}
+				if err != nil {
+					t.Error("Goroutine", g, ":", err)
+					return
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+	if received := fmt.Sprint(d.SaveRecords()); received != expected {
+		t.Error("Concurrent reads modified the DataFrame:", received)
+	}
+}
+
+func TestShared(t *testing.T) {
+	d, _ := New(C{"N", Ints(0)})
+	s := NewShared(*d)
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 10; i++ {
+				err := s.Update(func(df DataFrame) (*DataFrame, error) {
+					return df.AddValue("N", "N", 1)
+				})
+				if err != nil {
+					t.Error(err)
+					return
+				}
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 10; i++ {
+				snap := s.Load()
+				// Modifying the snapshot doesn't affect the Shared
+				snap.SetNames([]string{"M"})
+				if snap.NRows() != 1 {
+					t.Error("Unexpected snapshot:", snap)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	if received := fmt.Sprint(s.Load().SaveRecords()); received != "[[N] [80]]" {
+		t.Error("Expected:\n[[N] [80]]\nReceived:\n", received)
+	}
+
+	err := s.Update(func(df DataFrame) (*DataFrame, error) {
+		return nil, errors.New("failed")
+	})
+	if err == nil || fmt.Sprint(s.Load().SaveRecords()) != "[[N] [80]]" {
+		t.Error("A failed update should keep the DataFrame, received:", err, s.Load())
+	}
+}
@@ -1,10 +1,9 @@
 package df
 
 import (
-	"math"
+	"errors"
+	"fmt"
 	"regexp"
-	"strconv"
-	"strings"
 	"util"
 )
 
@@ -21,32 +20,61 @@ func init() {
 	util.IfErrPanic(err)
 }
 
+// NewCondition parses conditions like "Age > 30" into the conditions of
+// ConditionRows, grouped by column. Conditions it doesn't understand are
+// ignored, and unknown operators panic. ParseConditions returns an error in
+// both cases instead.
 func NewCondition(conds []string) map[string]Condition {
+	cs, err := parseConditions(conds, false)
+	if err != nil {
+		panic(err.Error())
+	}
+	return cs
+}
+
+// ParseConditions is like NewCondition but returns an error if any of the
+// conditions can't be parsed
+func ParseConditions(conds []string) (map[string]Condition, error) {
+	return parseConditions(conds, true)
+}
+
+// parseConditions parses the conditions, skipping the invalid ones unless
+// strict is set. Unknown operators are always an error.
+func parseConditions(conds []string, strict bool) (map[string]Condition, error) {
 	cs := make(map[string][]Condition)
 
 	for _, cond := range conds {
 		strs := reg.FindAllStringSubmatch(cond, -1)
-		if len(strs) == 1 && len(strs[0]) == 4 {
-			switch strs[0][2] {
-			case "<":
-				c := LtCondition(strs[0][3])
-				cs[strs[0][1]] = append(cs[strs[0][1]], &c)
-			case ">":
-				c := GtCondition(strs[0][3])
-				cs[strs[0][1]] = append(cs[strs[0][1]], &c)
-			case ">=":
-				c := NltCondition(strs[0][3])
-				cs[strs[0][1]] = append(cs[strs[0][1]], &c)
-			case "<=":
-				c := NgtCondition(strs[0][3])
-				cs[strs[0][1]] = append(cs[strs[0][1]], &c)
-			case "==":
-				c := EqCondition(strs[0][3])
-				cs[strs[0][1]] = append(cs[strs[0][1]], &c)
-			default:
-				panic("invalid operation: " + strs[0][2])
+		if len(strs) != 1 || len(strs[0]) != 4 {
+			if strict {
+				return nil, fmt.Errorf("Invalid condition %q", cond)
+			}
+			continue
+		}
+		var c Condition
+		switch strs[0][2] {
+		case "<":
+			lt := LtCondition(strs[0][3])
+			c = &lt
+		case ">":
+			gt := GtCondition(strs[0][3])
+			c = &gt
+		case ">=":
+			nlt := NltCondition(strs[0][3])
+			c = &nlt
+		case "<=":
+			ngt := NgtCondition(strs[0][3])
+			c = &ngt
+		case "==":
+			eq := EqCondition(strs[0][3])
+			c = &eq
+		default:
+			if strict {
+				return nil, fmt.Errorf("Invalid operation %q in condition %q", strs[0][2], cond)
 			}
+			return nil, errors.New("invalid operation: " + strs[0][2])
 		}
+		cs[strs[0][1]] = append(cs[strs[0][1]], c)
 	}
 
 	rcs := make(map[string]Condition)
@@ -59,7 +87,7 @@ func NewCondition(conds []string) map[string]Condition {
 		}
 	}
 
-	return rcs
+	return rcs, nil
 }
 
 type EqCondition string
@@ -69,33 +97,8 @@ func (c EqCondition) String() string {
 }
 
 func (c EqCondition) Compare(v Cell, t string) bool {
-	switch t {
-	case "df.String":
-		return strings.Compare(string(c), v.String()) == 0
-	case "df.Int":
-		if i, err := strconv.Atoi(string(c)); err == nil {
-			iv, _ := v.Int()
-			return i == *iv
-		} else {
-			return false
-		}
-	case "df.Bool":
-		b, _ := v.Bool()
-		if *b {
-			return string(c) == "true"
-		} else {
-			return string(c) == "false"
-		}
-	case "df.Float":
-		if f, err := strconv.ParseFloat(string(c), 64); err != nil {
-			fv, _ := v.Float()
-			return math.Abs(*fv-f) < 0.0001
-		} else {
-			return false
-		}
-	default:
-		return false
-	}
+	eq, _ := equalLiteral(v, t, string(c))
+	return eq
 }
 
 type GtCondition string
@@ -105,26 +108,8 @@ func (c GtCondition) String() string {
 }
 
 func (c GtCondition) Compare(v Cell, t string) bool {
-	switch t {
-	case "df.String":
-		return strings.Compare(v.String(), string(c)) > 0
-	case "df.Int":
-		if i, err := strconv.Atoi(string(c)); err == nil {
-			iv, _ := v.Int()
-			return i > *iv
-		} else {
-			return false
-		}
-	case "df.Float":
-		if f, err := strconv.ParseFloat(string(c), 64); err != nil {
-			fv, _ := v.Float()
-			return f > *fv
-		} else {
-			return false
-		}
-	default:
-		return false
-	}
+	cmp, ok := compareLiteral(v, t, string(c))
+	return ok && cmp > 0
 }
 
 type LtCondition string
@@ -134,26 +119,8 @@ func (c LtCondition) String() string {
 }
 
 func (c LtCondition) Compare(v Cell, t string) bool {
-	switch t {
-	case "df.String":
-		return strings.Compare(v.String(), string(c)) < 0
-	case "df.Int":
-		if i, err := strconv.Atoi(string(c)); err == nil {
-			iv, _ := v.Int()
-			return i < *iv
-		} else {
-			return false
-		}
-	case "df.Float":
-		if f, err := strconv.ParseFloat(string(c), 64); err != nil {
-			fv, _ := v.Float()
-			return f < *fv
-		} else {
-			return false
-		}
-	default:
-		return false
-	}
+	cmp, ok := compareLiteral(v, t, string(c))
+	return ok && cmp < 0
 }
 
 type NgtCondition string
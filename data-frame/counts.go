@@ -0,0 +1,225 @@
+package df
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// ValueCountsOptions configures ValueCounts. By default the values are
+// returned in order of first appearance with their absolute frequencies and
+// NA elements are not counted.
+type ValueCountsOptions struct {
+	// Normalize returns the relative frequencies instead of the counts
+	Normalize bool
+
+	// Sort orders the values by descending frequency, or ascending if
+	// Ascending is set. Values with the same frequency keep their order of
+	// appearance.
+	Sort      bool
+	Ascending bool
+
+	// IncludeNA counts NA elements as a value
+	IncludeNA bool
+}
+
+// valueCount stores the first appearance of a value and its frequency
+type valueCount struct {
+	value Cell
+	count int
+}
+
+// valueCounts counts the elements of a set of cells by their checksum,
+// returning the values in order of first appearance and the total number of
+// counted elements
+func valueCounts(cells Cells, includeNA bool) ([]valueCount, int) {
+	index := map[[16]byte]int{}
+	counts := []valueCount{}
+	total := 0
+	for _, c := range cells {
+		if c.IsNA() && !includeNA {
+			continue
+		}
+		total++
+		cs := c.Checksum()
+		if i, ok := index[cs]; ok {
+			counts[i].count++
+			continue
+		}
+		index[cs] = len(counts)
+		counts = append(counts, valueCount{c, 1})
+	}
+	return counts, total
+}
+
+// ValueCounts returns a DataFrame with the distinct values of the column
+// colname on the column Value, with the type of the original column, and
+// their frequencies on the column Count.
+func (df DataFrame) ValueCounts(colname string, opts ...ValueCountsOptions) (*DataFrame, error) {
+	col, err := df.col(colname)
+	if err != nil {
+		return nil, err
+	}
+	opt := ValueCountsOptions{}
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+
+	counts, total := valueCounts(col.cells, opt.IncludeNA)
+	if len(counts) == 0 {
+		return nil, errors.New("No values to count")
+	}
+	if opt.Sort {
+		sort.SliceStable(counts, func(i, j int) bool {
+			if opt.Ascending {
+				return counts[i].count < counts[j].count
+			}
+			return counts[i].count > counts[j].count
+		})
+	}
+
+	values := make(Cells, 0, len(counts))
+	freqs := make(Cells, 0, len(counts))
+	for _, v := range counts {
+		values = append(values, v.value.Copy())
+		if opt.Normalize {
+			freqs = append(freqs, Floats(float64(v.count)/float64(total))...)
+		} else {
+			freqs = append(freqs, Ints(v.count)...)
+		}
+	}
+	return New(C{"Value", values}, C{"Count", freqs})
+}
+
+// Bins defines the intervals used by Histogram. Only one of the fields
+// should be set:
+//   - Count divides the range of the values in intervals of equal width.
+//   - Edges gives the limits of the intervals in increasing order.
+//   - Quantiles divides the values in intervals with the same number of
+//     elements. Intervals with repeated limits are merged.
+type Bins struct {
+	Count     int
+	Edges     []float64
+	Quantiles int
+}
+
+// edges returns the limits of the intervals for the given sorted values
+func (b Bins) edges(sorted []float64) ([]float64, error) {
+	set := 0
+	for _, v := range []bool{b.Count != 0, b.Edges != nil, b.Quantiles != 0} {
+		if v {
+			set++
+		}
+	}
+	if set != 1 {
+		return nil, errors.New("Exactly one kind of bins should be given")
+	}
+
+	var edges []float64
+	switch {
+	case b.Edges != nil:
+		if len(b.Edges) < 2 {
+			return nil, errors.New("At least two edges are needed")
+		}
+		for i := 1; i < len(b.Edges); i++ {
+			if !(b.Edges[i] > b.Edges[i-1]) {
+				return nil, errors.New("Edges must be strictly increasing")
+			}
+		}
+		return b.Edges, nil
+	case b.Count > 0:
+		min, max := sorted[0], sorted[len(sorted)-1]
+		if min == max {
+			min, max = min-0.5, max+0.5
+		}
+		width := (max - min) / float64(b.Count)
+		for i := 0; i < b.Count; i++ {
+			edges = append(edges, min+float64(i)*width)
+		}
+		edges = append(edges, max)
+	case b.Quantiles > 0:
+		for i := 0; i <= b.Quantiles; i++ {
+			e := quantile(sorted, float64(i)/float64(b.Quantiles))
+			if len(edges) == 0 || e > edges[len(edges)-1] {
+				edges = append(edges, e)
+			}
+		}
+		if len(edges) == 1 {
+			edges = []float64{edges[0] - 0.5, edges[0] + 0.5}
+		}
+	default:
+		return nil, errors.New("The number of bins must be positive")
+	}
+	return edges, nil
+}
+
+// bin returns the interval where the value v falls or -1 if it is out of the
+// range of the edges. Intervals include their lower limit, and the last one
+// also includes its upper limit.
+func bin(edges []float64, v float64) int {
+	last := len(edges) - 1
+	if v < edges[0] || v > edges[last] || math.IsNaN(v) {
+		return -1
+	}
+	if v == edges[last] {
+		return last - 1
+	}
+	return sort.Search(len(edges), func(i int) bool { return edges[i] > v }) - 1
+}
+
+// Histogram returns a DataFrame with the number of non NA elements of the
+// numeric column colname that fall on each of the given bins. The limits of
+// the intervals are stored on the Lower and Upper columns and the number of
+// elements on the Count column. Values out of the range of the bins are
+// ignored.
+func (df DataFrame) Histogram(colname string, bins Bins) (*DataFrame, error) {
+	col, err := df.col(colname)
+	if err != nil {
+		return nil, err
+	}
+	if numericKind(col.empty) == kindNone {
+		return nil, fmt.Errorf("Can't compute the histogram of the non numeric column %s", colname)
+	}
+
+	counts, _ := valueCounts(col.cells, false)
+	if len(counts) == 0 {
+		return nil, errors.New("No values to count")
+	}
+	values := make([]float64, 0, len(counts))
+	for _, v := range counts {
+		f, err := v.value.Float()
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, *f)
+	}
+	sorted := append([]float64{}, values...)
+	sort.Float64s(sorted)
+	if bins.Quantiles > 0 {
+		// Quantiles are computed over all the elements and not only the
+		// distinct ones
+		if sorted, err = numericValues(col.cells); err != nil {
+			return nil, err
+		}
+		sort.Float64s(sorted)
+	}
+	edges, err := bins.edges(sorted)
+	if err != nil {
+		return nil, err
+	}
+
+	freqs := make([]int, len(edges)-1)
+	for i, v := range counts {
+		if b := bin(edges, values[i]); b >= 0 {
+			freqs[b] += v.count
+		}
+	}
+	lower := append([]float64{}, edges[:len(edges)-1]...)
+	upper := append([]float64{}, edges[1:]...)
+	return New(
+		C{"Lower", Floats(lower)},
+		C{"Upper", Floats(upper)},
+		C{"Count", Ints(freqs)},
+	)
+}
@@ -0,0 +1,101 @@
+package df
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestDataFrame_ValueCounts(t *testing.T) {
+	d, _ := New(
+		C{"A", Strings("b", "a", nil, "a", "c", nil, "a", "b")},
+	)
+	var tests = []struct {
+		opts     ValueCountsOptions
+		expected string
+	}{
+		{ValueCountsOptions{}, "[[Value Count] [b 2] [a 3] [c 1]]"},
+		{ValueCountsOptions{Sort: true}, "[[Value Count] [a 3] [b 2] [c 1]]"},
+		{ValueCountsOptions{Sort: true, Ascending: true}, "[[Value Count] [c 1] [b 2] [a 3]]"},
+		{ValueCountsOptions{IncludeNA: true, Sort: true}, "[[Value Count] [a 3] [b 2] [NA 2] [c 1]]"},
+		{ValueCountsOptions{Normalize: true}, "[[Value Count] [b 0.3333333333333333] [a 0.5] [c 0.16666666666666666]]"},
+		{ValueCountsOptions{Normalize: true, IncludeNA: true}, "[[Value Count] [b 0.25] [a 0.375] [NA 0.25] [c 0.125]]"},
+	}
+	for k, v := range tests {
+		dd, err := d.ValueCounts("A", v.opts)
+		if err != nil {
+			t.Error("Test", k, ":", err)
+			continue
+		}
+		received := fmt.Sprint(dd.SaveRecords())
+		if v.expected != received {
+			t.Error(
+				"Test", k, "\n",
+				"Expected:\n",
+				v.expected, "\n",
+				"Received:\n",
+				received,
+			)
+		}
+	}
+
+	// The values keep the type of the column
+	d, _ = New(C{"A", Decimals(2, "1.5", "1.50", "2")})
+	dd, err := d.ValueCounts("A")
+	if err != nil {
+		t.Error(err)
+	}
+	if received := fmt.Sprint(dd.SaveRecords()); received != "[[Value Count] [1.50 2] [2.00 1]]" {
+		t.Error("Expected: [[Value Count] [1.50 2] [2.00 1]] Received:", received)
+	}
+	if _, err := d.ValueCounts("B"); err == nil {
+		t.Error("Unknown columns should throw an error")
+	}
+}
+
+func TestDataFrame_Histogram(t *testing.T) {
+	d, _ := New(
+		C{"A", Ints(1, 2, 2, 3, 4, 5, 9, 10, nil)},
+		C{"B", Strings("a", "b", "c", "d", "e", "f", "g", "h", "i")},
+	)
+	var tests = []struct {
+		bins     Bins
+		expected string
+	}{
+		{Bins{Count: 3}, "[[Lower Upper Count] [1 4 4] [4 7 2] [7 10 2]]"},
+		{Bins{Edges: []float64{0, 2, 5}}, "[[Lower Upper Count] [0 2 1] [2 5 5]]"},
+		{Bins{Quantiles: 2}, "[[Lower Upper Count] [1 3.5 4] [3.5 10 4]]"},
+	}
+	for k, v := range tests {
+		dd, err := d.Histogram("A", v.bins)
+		if err != nil {
+			t.Error("Test", k, ":", err)
+			continue
+		}
+		received := fmt.Sprint(dd.SaveRecords())
+		if v.expected != received {
+			t.Error(
+				"Test", k, "\n",
+				"Expected:\n",
+				v.expected, "\n",
+				"Received:\n",
+				received,
+			)
+		}
+	}
+
+	var fails = []struct {
+		col  string
+		bins Bins
+	}{
+		{"B", Bins{Count: 2}},
+		{"A", Bins{}},
+		{"A", Bins{Count: 2, Quantiles: 2}},
+		{"A", Bins{Edges: []float64{2, 1}}},
+		{"A", Bins{Count: -1}},
+	}
+	for k, v := range fails {
+		if _, err := d.Histogram(v.col, v.bins); err == nil {
+			t.Error("Test", k, "should throw an error")
+		}
+	}
+}
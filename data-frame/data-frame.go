@@ -3,11 +3,12 @@ package df
 import (
 	"bytes"
 	"encoding/csv"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
 	"reflect"
-	"strconv"
-	"strings"
+	"sort"
 )
 
 // NOTE: The concept of NA is represented by nil pointers
@@ -47,7 +48,14 @@ type tobool interface {
 	Bool() (*bool, error)
 }
 
-// DataFrame is the base data structure
+// DataFrame is the base data structure.
+//
+// A DataFrame is safe for concurrent use by several goroutines as long as
+// none of them modifies it: operations never modify the DataFrames they
+// receive and return new ones instead. The methods with pointer receivers
+// (SetNames, LoadData, Parse...) and writes to the Columns map do modify it
+// and must not run concurrently with any other use. To share a DataFrame that
+// is updated over time use Shared.
 type DataFrame struct {
 	Columns   map[string]column
 	colIndexs map[string]int
@@ -82,6 +90,11 @@ type u struct {
 
 //const defaultDateFormat = "2006-01-02"
 
+// ErrEmptySubset is returned by the selections of rows or columns that would
+// leave a DataFrame without any. Empty returns a DataFrame without rows when
+// one is needed.
+var ErrEmptySubset = errors.New("Empty subset")
+
 // TODO: Implement a custom Error type that stores information about the type of
 // error and the severity of it (Warning vs Error)
 // Error types
@@ -129,54 +142,143 @@ func New(colConst ...C) (*DataFrame, error) {
 			}
 		}
 		df.Columns[val.Colname] = *col
-		df.colIndexs[val.Colname] = k + 1
+		df.colIndexs[val.Colname] = k
 	}
+	df.nRows = colLength
 
 	return df, nil
 }
 
-// Names is the getter method for the column names
+// Names is the getter method for the column names. The names are returned in
+// the order of the columns of the DataFrame.
 func (df DataFrame) Names() []string {
-	var names []string
-	for _, v := range df.Columns {
-		names = append(names, v.colName)
+	names := make([]string, 0, len(df.Columns))
+	for k := range df.Columns {
+		names = append(names, k)
 	}
+	sort.Slice(names, func(i, j int) bool {
+		ii, ij := df.colIndexs[names[i]], df.colIndexs[names[j]]
+		if ii != ij {
+			return ii < ij
+		}
+		return names[i] < names[j]
+	})
 	return names
 }
 
+// Types returns the types of the columns, in the same order as Names
+func (df DataFrame) Types() []string {
+	names := df.Names()
+	types := make([]string, 0, len(names))
+	for _, k := range names {
+		types = append(types, df.Columns[k].colType)
+	}
+	return types
+}
+
+// Empty returns a DataFrame with the same columns and types as df but without
+// rows, which row selections like SubsetRows don't return
+func (df DataFrame) Empty() *DataFrame {
+	newDf, _ := df.takeRows(nil)
+	return &newDf
+}
+
+// copy returns a DataFrame that shares the column buffers of df but owns its
+// column and index maps, so columns can be added, replaced or removed on the
+// copy without affecting df. Elements are never modified in place: operations
+// that change a column build a new buffer for it (copy-on-write).
 func (df DataFrame) copy() DataFrame {
-	columns := make(map[string]column)
+	columns := make(map[string]column, len(df.Columns))
 	for k, v := range df.Columns {
-		columns[k] = v.copy()
+		columns[k] = v
+	}
+	indexes := make(map[string]int, len(df.colIndexs))
+	for k, v := range df.colIndexs {
+		indexes[k] = v
 	}
 	dfc := DataFrame{
 		Columns:   columns,
-		colIndexs: df.colIndexs,
+		colIndexs: indexes,
 		nRows:     df.nRows,
 	}
 	return dfc
 }
 
-// SetNames let us specify the column names of a DataFrame
+// col returns the column with the given name. Column names are resolved in
+// the same way by conditions and expressions.
+func (df DataFrame) col(colname string) (column, error) {
+	col, ok := df.Columns[colname]
+	if !ok {
+		return column{}, errors.New("Column not found: " + colname)
+	}
+	return col, nil
+}
+
+// withColumn returns a copy of the DataFrame where the column colname contains
+// the given cells. If the column doesn't exist it will be added after the
+// last column.
+func (df DataFrame) withColumn(colname string, cells Cells) (*DataFrame, error) {
+	if len(cells) != df.nRows {
+		return nil, errors.New("columns don't have the same dimensions")
+	}
+	col, err := newCol(colname, cells)
+	if err != nil {
+		return nil, err
+	}
+	return df.setColumn(*col), nil
+}
+
+// withEmptyColumn is like withColumn for DataFrames without rows, where the
+// type of the column can't be taken from its elements
+func (df DataFrame) withEmptyColumn(colname string, empty Cell) (*DataFrame, error) {
+	if df.nRows != 0 {
+		return nil, errors.New("columns don't have the same dimensions")
+	}
+	return df.setColumn(emptyCol(colname, empty)), nil
+}
+
+// setColumn returns a copy of the DataFrame with the given column
+func (df DataFrame) setColumn(col column) *DataFrame {
+	colname := col.colName
+	newDf := df.copy()
+	last := -1
+	for _, v := range df.colIndexs {
+		if v > last {
+			last = v
+		}
+	}
+	if _, ok := newDf.colIndexs[colname]; !ok {
+		newDf.colIndexs[colname] = last + 1
+	}
+	newDf.Columns[colname] = col
+	return &newDf
+}
+
+// SetNames let us specify the column names of a DataFrame. There must be
+// a name for every column.
 func (df *DataFrame) SetNames(colnames []string) error {
+	if len(colnames) != len(df.Columns) {
+		return errors.New("Wrong number of column names")
+	}
 	newcolumns := make(map[string]column)
 	newindexes := make(map[string]int)
 
 	for k, v := range df.colIndexs {
+		if _, ok := df.Columns[k]; !ok {
+			continue
+		}
 		if v < len(colnames) {
 			if _, ok := newcolumns[colnames[v]]; ok {
 				return errors.New("duplicate column name: " + colnames[v])
 			}
-			newcolumns[colnames[v]] = df.Columns[k]
+			col := df.Columns[k]
+			col.colName = colnames[v]
+			newcolumns[colnames[v]] = col
 			newindexes[colnames[v]] = v
 		} else {
 			newcolumns[k] = df.Columns[k]
 			newindexes[k] = v
 		}
-
-		if col, ok := df.Columns[k]; ok {
-			col.recountNumChars()
-		}
 	}
 	df.Columns = newcolumns
 	df.colIndexs = newindexes
@@ -229,16 +331,21 @@ func (df *DataFrame) LoadData(records [][]string) error {
 	}
 
 	// Fill the columns on the DataFrame
-	for j := 0; j < nCols; j++ {
-		colstrarr := []string{}
+	cols := make([]*column, nCols)
+	err := parallel(nCols, func(j int) error {
+		colstrarr := make([]string, 0, nRows)
 		for i := 1; i < nRows+1; i++ {
 			colstrarr = append(colstrarr, records[i][j])
 		}
 
-		col, err := newCol(colnames[j], Strings(colstrarr))
-		if err != nil {
-			return err
-		}
+		var err error
+		cols[j], err = newCol(colnames[j], Strings(colstrarr))
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	for j, col := range cols {
 		newDf.Columns[colnames[j]] = *col
 		newDf.colIndexs[colnames[j]] = j
 	}
@@ -326,17 +433,21 @@ func (df *DataFrame) LoadInterface(records [][]interface{}, colnames []string) e
 	}
 
 	// Fill the columns on the DataFrame
-	for j := 0; j < nCols; j++ {
-		colstrarr := []string{}
+	cols := make([]*column, nCols)
+	err := parallel(nCols, func(j int) error {
+		colstrarr := make([]string, 0, nRows)
 		for i := 0; i < nRows; i++ {
 			colstrarr = append(colstrarr, fmt.Sprint(records[i][j]))
 		}
 
-		col, err := newCol(colnames[j], Strings(colstrarr))
-		if err != nil {
-			return err
-		}
-
+		var err error
+		cols[j], err = newCol(colnames[j], Strings(colstrarr))
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	for j, col := range cols {
 		newDf.Columns[colnames[j]] = *col
 		newDf.colIndexs[colnames[j]] = j
 	}
@@ -351,34 +462,49 @@ func (df *DataFrame) LoadInterface(records [][]interface{}, colnames []string) e
 	return nil
 }
 
+// Parse converts the columns of the DataFrame to the given types, which can
+// be given as a []string with the types of the columns in order or as a T.
+// Columns are parsed in parallel and the DataFrame is only modified if all of
+// them succeed.
 func (df *DataFrame) Parse(types interface{}) error {
 	// Parse the DataFrame columns acording to the given types
+	var names, colTypes []string
 	switch types.(type) {
 	case []string:
 		types := types.([]string)
-		for k, v := range df.colIndexs {
-			if v < len(types) {
-				col := df.Columns[k].copy()
-				err := col.ParseColumn(types[v])
-				if err != nil {
-					return nil
-				}
-				df.Columns[k] = col
+		for k, v := range df.Names() {
+			if k < len(types) {
+				names = append(names, v)
+				colTypes = append(colTypes, types[k])
 			}
 		}
 	case T:
 		types := types.(T)
-		for k, v := range types {
-			col := df.Columns[k].copy()
-			err := col.ParseColumn(v)
-			if err != nil {
-				return err
+		for k := range types {
+			if _, ok := df.Columns[k]; !ok {
+				return errors.New("Can't find the given column: " + k)
+			}
+		}
+		for _, k := range df.Names() {
+			if v, ok := types[k]; ok {
+				names = append(names, k)
+				colTypes = append(colTypes, v)
 			}
-
-			df.Columns[k] = col
 		}
 	}
 
+	cols := make([]column, len(names))
+	err := parallel(len(names), func(i int) error {
+		cols[i] = df.Columns[names[i]]
+		return cols[i].ParseColumn(colTypes[i])
+	})
+	if err != nil {
+		return err
+	}
+	for i, k := range names {
+		df.Columns[k] = cols[i]
+	}
+
 	return nil
 }
 
@@ -408,11 +534,13 @@ func (df DataFrame) SaveRecords() [][]string {
 
 	var records [][]string
 
-	records = append(records, df.Names())
+	names := df.Names()
+	records = append(records, names)
 	for i := 0; i < df.nRows; i++ {
 		r := []string{}
-		for _, v := range df.Columns {
-			r = append(r, v.cells[i].String())
+		for _, k := range names {
+			v := df.Columns[k]
+			r = append(r, formatCellType(v.cells[i], v.colType))
 		}
 		records = append(records, r)
 	}
@@ -420,7 +548,79 @@ func (df DataFrame) SaveRecords() [][]string {
 	return records
 }
 
-//// TODO: Save to other formats. JSON? XML?
+// SaveCsv will save the DataFrame in CSV format
+func (df DataFrame) SaveCsv() ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.WriteAll(df.SaveRecords()); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// SaveJson will save the DataFrame as a JSON array of objects, one per row,
+// with the keys in the order of the columns. Numeric and boolean values are
+// encoded as JSON numbers and booleans, NA elements as null and the rest of
+// Cells will be encoded with their json.Marshaler implementation or their
+// formatted string otherwise.
+func (df DataFrame) SaveJson() ([]byte, error) {
+	names := df.Names()
+	keys := make([][]byte, 0, len(names))
+	for _, k := range names {
+		key, err := json.Marshal(k)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	var buf bytes.Buffer
+	buf.WriteByte('[')
+	for i := 0; i < df.nRows; i++ {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		buf.WriteByte('{')
+		for j, k := range names {
+			if j > 0 {
+				buf.WriteByte(',')
+			}
+			v := df.Columns[k]
+			value, err := json.Marshal(jsonValue(v.cells[i], v.colType))
+			if err != nil {
+				return nil, err
+			}
+			buf.Write(keys[j])
+			buf.WriteByte(':')
+			buf.Write(value)
+		}
+		buf.WriteByte('}')
+	}
+	buf.WriteByte(']')
+	return buf.Bytes(), nil
+}
+
+// jsonValue returns the value that represents a Cell on a JSON document
+func jsonValue(cell Cell, colType string) interface{} {
+	if cell.IsNA() {
+		return nil
+	}
+	switch c := cell.(type) {
+	case json.Marshaler:
+		return c
+	case Int:
+		return *c.i
+	case Float:
+		if math.IsNaN(*c.f) || math.IsInf(*c.f, 0) {
+			return nil
+		}
+		return *c.f
+	case Bool:
+		return *c.b
+	}
+	return formatCellType(cell, colType)
+}
+
+//// TODO: Save to other formats. XML?
 
 // Dim will return the current dimensions of the DataFrame in a two element array
 // where the first element is the number of rows and the second the number of
@@ -466,15 +666,35 @@ func (df DataFrame) Subset(subsetCols interface{}, subsetRows interface{}) (*Dat
 
 func (df DataFrame) DropColumn(col string) (*DataFrame, error) {
 	var newcolnames []string
-	for _, c := range df.Columns {
-		if col != c.colName {
-			newcolnames = append(newcolnames, col)
+	for _, c := range df.Names() {
+		if col != c {
+			newcolnames = append(newcolnames, c)
 		}
 	}
 
 	return df.SubsetColumns(newcolnames)
 }
 
+// SelectColumns returns a DataFrame with the given columns in the given
+// order, while SubsetColumns keeps the order of the DataFrame
+func (df DataFrame) SelectColumns(colnames ...string) (*DataFrame, error) {
+	if len(colnames) == 0 {
+		return nil, ErrEmptySubset
+	}
+	seen := make(map[string]bool, len(colnames))
+	for _, k := range colnames {
+		if _, err := df.col(k); err != nil {
+			return nil, err
+		}
+		if seen[k] {
+			return nil, errors.New("Duplicated column: " + k)
+		}
+		seen[k] = true
+	}
+	newDf := df.selectColumns(colnames)
+	return &newDf, nil
+}
+
 // SubsetColumns will return a DataFrame that contains only the columns contained
 // on the given subset
 func (df DataFrame) SubsetColumns(subset interface{}) (*DataFrame, error) {
@@ -515,7 +735,7 @@ func (df DataFrame) SubsetColumns(subset interface{}) (*DataFrame, error) {
 		}
 
 		if len(colindex) == 0 {
-			return nil, errors.New("Empty subset")
+			return nil, ErrEmptySubset
 		}
 
 		newDf := df.copy()
@@ -531,6 +751,7 @@ func (df DataFrame) SubsetColumns(subset interface{}) (*DataFrame, error) {
 			}
 
 		}
+		newDf.colIndexs = colindex
 		return &newDf, nil
 
 	default:
@@ -560,12 +781,21 @@ func (df DataFrame) ConditionRows(cs map[string]Condition) (*DataFrame, error) {
 		return &newDf, nil
 	}
 
+	cols := make(map[string]column, len(cs))
+	for k := range cs {
+		col, err := df.col(k)
+		if err != nil {
+			return nil, err
+		}
+		cols[k] = col
+	}
+
 	var rows []int
 
 	for i := 0; i < df.NRows(); i++ {
 		valid := true
 		for k, c := range cs {
-			if !c.Compare(df.Columns[k].cells[i], df.Columns[k].colType) {
+			if !c.Compare(cols[k].cells[i], cols[k].colType) {
 				valid = false
 				break
 			}
@@ -579,7 +809,10 @@ func (df DataFrame) ConditionRows(cs map[string]Condition) (*DataFrame, error) {
 	return df.SubsetRows(rows)
 }
 
-// SubsetRows will return a DataFrame that contains only the selected rows
+// SubsetRows will return a DataFrame that contains only the selected rows.
+// Negative row numbers produce rows of NA elements. Ranges share the column
+// buffers of the DataFrame, and selecting all the rows in order returns
+// a DataFrame that shares its columns.
 func (df DataFrame) SubsetRows(subset interface{}) (*DataFrame, error) {
 	// Generate a DataFrame to store the temporary values
 	newDf := df.copy()
@@ -592,54 +825,42 @@ func (df DataFrame) SubsetRows(subset interface{}) (*DataFrame, error) {
 			return nil, errors.New("Bad subset: Start greater than Beginning")
 		}
 		if s.From == s.To {
-			return nil, errors.New("Empty subset")
+			return nil, ErrEmptySubset
 		}
 		if s.To > df.nRows || s.To < 0 || s.From < 0 {
 			return nil, errors.New("Subset out of range")
 		}
 
 		newDf.nRows = s.To - s.From
+		if newDf.nRows == df.nRows {
+			return &newDf, nil
+		}
 		for k, v := range df.Columns {
-			col, err := newCol(v.colName, v.cells[s.From:s.To])
-			if err != nil {
-				return nil, err
-			}
-			col.recountNumChars()
-			newDf.Columns[k] = *col
+			v.cells = v.cells[s.From:s.To:s.To]
+			newDf.Columns[k] = v
 		}
 	case []int:
 		rowNums := subset.([]int)
 
 		if len(rowNums) == 0 {
-			return nil, errors.New("Empty subset")
+			return nil, ErrEmptySubset
 		}
 
 		// Check for errors
-		for _, v := range rowNums {
+		identity := len(rowNums) == df.nRows
+		for k, v := range rowNums {
 			if v >= df.nRows {
 				return nil, errors.New("Subset out of range")
 			}
+			identity = identity && v == k
 		}
 
 		newDf.nRows = len(rowNums)
+		if identity {
+			return &newDf, nil
+		}
 		for k, v := range df.Columns {
-			cells := Cells{}
-
-			for _, i := range rowNums {
-				if i < 0 {
-					cells = append(cells, v.empty)
-				} else {
-					cells = append(cells, v.cells[i])
-				}
-			}
-
-			col, err := newCol(v.colName, cells)
-			if err != nil {
-				return nil, err
-			}
-
-			col.recountNumChars()
-			newDf.Columns[k] = *col
+			newDf.Columns[k] = v.take(rowNums)
 		}
 	default:
 		return nil, errors.New("Unknown subsetting option")
@@ -648,17 +869,17 @@ func (df DataFrame) SubsetRows(subset interface{}) (*DataFrame, error) {
 	return &newDf, nil
 }
 
-// Rbind combines the rows of two dataframes
+// Rbind combines the rows of two dataframes. The columns are matched by name
+// and keep the order of the first DataFrame.
 func Rbind(a DataFrame, b DataFrame) (*DataFrame, error) {
-	newDf := &DataFrame{
-		nRows:     a.nRows + b.nRows,
-		colIndexs: a.colIndexs,
-		Columns:   map[string]column{},
-	}
+	newDf := a.copy()
+	newDf.nRows = a.nRows + b.nRows
 
-	for k, v := range a.colIndexs {
-		idx, ok := b.colIndexs[k]
-		if !ok || idx != v {
+	if len(a.Columns) != len(b.Columns) {
+		return nil, errors.New("Mismatching column names")
+	}
+	for k := range a.colIndexs {
+		if _, ok := b.Columns[k]; !ok {
 			return nil, errors.New("Mismatching column names")
 		}
 
@@ -666,16 +887,14 @@ func Rbind(a DataFrame, b DataFrame) (*DataFrame, error) {
 			return nil, errors.New("Mismatching column types")
 		}
 
-		col := a.Columns[k].copy()
-		var err error
-		col, err = col.append(b.Columns[k].copy().cells...)
+		col, err := a.Columns[k].append(b.Columns[k].cells...)
 		if err != nil {
 			return nil, err
 		}
 		newDf.Columns[k] = col
 	}
 
-	return newDf, nil
+	return &newDf, nil
 }
 
 // Cbind combines the columns of two DataFrames
@@ -704,17 +923,31 @@ func Cbind(a DataFrame, b DataFrame) (*DataFrame, error) {
 
 type b []byte
 
+// DuplicatesOptions configures the functions that look for unique or
+// duplicated rows. If KeepOrder is set the rows are returned in the order in
+// which they appear on the DataFrame.
+type DuplicatesOptions struct {
+	KeepOrder bool
+}
+
+// rowChecksum returns the concatenation of the checksums of the elements of
+// the row i on the given columns, which identifies the row on maps
+func rowChecksum(df DataFrame, names []string, i int) string {
+	mdarr := make([]byte, 0, 16*len(names))
+	for _, k := range names {
+		cs := df.Columns[k].cells[i].Checksum()
+		mdarr = append(mdarr, cs[:]...)
+	}
+	return string(mdarr)
+}
+
 // uniqueRowsMap is a helper function that will get a map of unique or duplicated
 // rows for a given DataFrame
 func uniqueRowsMap(df DataFrame) map[string]u {
 	uniqueRows := make(map[string]u)
+	names := df.Names()
 	for i := 0; i < df.nRows; i++ {
-		mdarr := []byte{}
-		for _, v := range df.Columns {
-			cs := v.cells[i].Checksum()
-			mdarr = append(mdarr, cs[:]...)
-		}
-		str := string(mdarr)
+		str := rowChecksum(df, names, i)
 		if a, ok := uniqueRows[str]; ok {
 			a.unique = false
 			a.appears = append(a.appears, i)
@@ -727,9 +960,21 @@ func uniqueRowsMap(df DataFrame) map[string]u {
 	return uniqueRows
 }
 
+// subsetRowsOrder returns the given rows, sorting them first if the options
+// ask to keep the original order of the rows
+func (df DataFrame) subsetRowsOrder(rows []int, opts []DuplicatesOptions) (*DataFrame, error) {
+	for _, o := range opts {
+		if o.KeepOrder {
+			sort.Ints(rows)
+			break
+		}
+	}
+	return df.SubsetRows(rows)
+}
+
 // Unique will return all unique rows inside a DataFrame. The order of the rows
-// will not be preserved.
-func (df DataFrame) Unique() (*DataFrame, error) {
+// will not be preserved unless KeepOrder is set.
+func (df DataFrame) Unique(opts ...DuplicatesOptions) (*DataFrame, error) {
 	uniqueRows := uniqueRowsMap(df)
 	appears := []int{}
 	for _, v := range uniqueRows {
@@ -738,11 +983,12 @@ func (df DataFrame) Unique() (*DataFrame, error) {
 		}
 	}
 
-	return df.SubsetRows(appears)
+	return df.subsetRowsOrder(appears, opts)
 }
 
-// RemoveUnique will return all duplicated rows inside a DataFrame
-func (df DataFrame) RemoveUnique() (*DataFrame, error) {
+// RemoveUnique will return all duplicated rows inside a DataFrame. The order of
+// the rows will not be preserved unless KeepOrder is set.
+func (df DataFrame) RemoveUnique(opts ...DuplicatesOptions) (*DataFrame, error) {
 	uniqueRows := uniqueRowsMap(df)
 	appears := []int{}
 	for _, v := range uniqueRows {
@@ -751,25 +997,26 @@ func (df DataFrame) RemoveUnique() (*DataFrame, error) {
 		}
 	}
 
-	return df.SubsetRows(appears)
+	return df.subsetRowsOrder(appears, opts)
 }
 
 // RemoveDuplicated will return all unique rows in a DataFrame and the first
 // appearance of all duplicated rows. The order of the rows will not be
-// preserved.
-func (df DataFrame) RemoveDuplicated() (*DataFrame, error) {
+// preserved unless KeepOrder is set.
+func (df DataFrame) RemoveDuplicated(opts ...DuplicatesOptions) (*DataFrame, error) {
 	uniqueRows := uniqueRowsMap(df)
 	appears := []int{}
 	for _, v := range uniqueRows {
 		appears = append(appears, v.appears[0])
 	}
 
-	return df.SubsetRows(appears)
+	return df.subsetRowsOrder(appears, opts)
 }
 
 // Duplicated will return the first appearance of the duplicated rows in
-// a DataFrame. The order of the rows will not be preserved.
-func (df DataFrame) Duplicated() (*DataFrame, error) {
+// a DataFrame. The order of the rows will not be preserved unless KeepOrder
+// is set.
+func (df DataFrame) Duplicated(opts ...DuplicatesOptions) (*DataFrame, error) {
 	uniqueRows := uniqueRowsMap(df)
 	appears := []int{}
 	for _, v := range uniqueRows {
@@ -778,47 +1025,7 @@ func (df DataFrame) Duplicated() (*DataFrame, error) {
 		}
 	}
 
-	return df.SubsetRows(appears)
-}
-
-// Implementing the Stringer interface for DataFrame
-func (df DataFrame) String() (str string) {
-	// TODO: We should truncate the maximum length of shown columns and scape newline
-	// characters'
-	addLeftPadding := func(s string, nchar int) string {
-		if len(s) < nchar {
-			return strings.Repeat(" ", nchar-len(s)) + s
-		}
-		return s
-	}
-	addRightPadding := func(s string, nchar int) string {
-		if len(s) < nchar {
-			return s + strings.Repeat(" ", nchar-len(s))
-		}
-		return s
-	}
-
-	nRowsPadding := len(fmt.Sprint(df.nRows))
-	if df.NCols() != 0 {
-		str += addLeftPadding("  ", nRowsPadding+2)
-		for k, v := range df.Columns {
-			str += addRightPadding(v.colName, df.Columns[k].numChars)
-			str += "  "
-		}
-		str += "\n"
-		str += "\n"
-	}
-	for i := 0; i < df.nRows; i++ {
-		str += addLeftPadding(strconv.Itoa(i)+": ", nRowsPadding+2)
-		for _, v := range df.Columns {
-			elem := v.cells[i]
-			str += addRightPadding(formatCell(elem), v.numChars)
-			str += "  "
-		}
-		str += "\n"
-	}
-
-	return str
+	return df.subsetRowsOrder(appears, opts)
 }
 
 // formatCell returns the value of a given element in string format. In case of
@@ -834,360 +1041,14 @@ func formatCell(cell interface{}) string {
 	return fmt.Sprint(cell)
 }
 
-// InnerJoin returns a DataFrame containing the inner join of two other DataFrames.
-// This operation matches all rows that appear on both dataframes.
-/*
-func InnerJoin(a DataFrame, b DataFrame, keys ...string) (*DataFrame, error) {
-	dfa := a.copy()
-	dfb := b.copy()
-	// Check that we have all given keys in both DataFrames
-	errorArr := []string{}
-	for _, key := range keys {
-		ca, oka := dfa.Columns[key]
-		cb, okb := dfb.Columns[key]
-		if !oka {
-			errorArr = append(errorArr, fmt.Sprint("Can't find key \"", key, "\" on left DataFrame"))
-		}
-		if !okb {
-			errorArr = append(errorArr, fmt.Sprint("Can't find key \"", key, "\" on right DataFrame"))
-		}
-		// Check that the column types are the same between DataFrames
-		if len(errorArr) == 0 {
-			ta := ca.colType
-			tb := cb.colType
-			if ta != tb {
-				errorArr = append(errorArr, fmt.Sprint("Different types for key\"", key, "\". Left:", ta, " Right:", tb))
-			}
-		}
-	}
-	if len(errorArr) != 0 {
-		return nil, errors.New(strings.Join(errorArr, "\n"))
-	}
-
-	// Rename non key coumns with the same name on both DataFrames
-	colnamesa := dfa.Names()
-	colnamesb := dfb.Names()
-
-	for k, v := range colnamesa {
-		if idx, err := dfb.colIndex(v); err == nil {
-			if !inStringSlice(v, keys) {
-				colnamesa[k] = v + ".x"
-				colnamesb[idx] = v + ".y"
-			}
-		}
-	}
-	dfa.SetNames(colnamesa)
-	dfb.SetNames(colnamesb)
-
-	// Get the column indexes of both columns for the given keys
-	colIdxa := []int{}
-	colIdxb := []int{}
-	for _, key := range keys {
-		ia, erra := dfa.colIndex(key)
-		ib, errb := dfb.colIndex(key)
-		if erra == nil && errb == nil {
-			colIdxa = append(colIdxa, ia)
-			colIdxb = append(colIdxb, ib)
-		}
-	}
-
-	// Get the combined checksum for all keys in both DataFrames
-	checksumsa := make([][]byte, dfa.nRows)
-	checksumsb := make([][]byte, dfb.nRows)
-	for _, i := range colIdxa {
-		for k, v := range dfa.Columns[i].cells {
-			b := []byte{}
-			cs := v.Checksum()
-			b = append(b, cs[:]...)
-			checksumsa[k] = append(checksumsa[k], b...)
-		}
-	}
-	for _, i := range colIdxb {
-		for k, v := range dfb.Columns[i].cells {
-			b := []byte{}
-			cs := v.Checksum()
-			b = append(b, cs[:]...)
-			checksumsb[k] = append(checksumsb[k], b...)
-		}
-	}
-
-	// Get the indexes of the rows we want to join
-	dfaIndexes := []int{}
-	dfbIndexes := []int{}
-	for ka, ca := range checksumsa {
-		for kb, cb := range checksumsb {
-			if string(ca) == string(cb) {
-				dfaIndexes = append(dfaIndexes, ka)
-				dfbIndexes = append(dfbIndexes, kb)
-			}
-		}
-	}
-
-	// Get the names of the elements that are not keys on the right DataFrame
-	nokeynamesb := []string{}
-	for _, v := range dfb.Columns {
-		if !inStringSlice(v.colName, keys) {
-			nokeynamesb = append(nokeynamesb, v.colName)
-		}
-	}
-
-	newdfa, _ := dfa.SubsetRows(dfaIndexes)
-	newdfb, _ := dfb.Subset(nokeynamesb, dfbIndexes)
-	return Cbind(*newdfa, *newdfb)
-}
-
-// CrossJoin returns a DataFrame containing the cartesian product of the rows on
-// both DataFrames.
-func CrossJoin(a DataFrame, b DataFrame) (*DataFrame, error) {
-	dfa := a.copy()
-	dfb := b.copy()
-	colnamesa := dfa.Names()
-	colnamesb := dfb.Names()
-
-	for k, v := range dfa.Columns {
-		if idx, err := dfb.colIndex(v.colName); err == nil {
-			colnamesa[k] = v.colName + ".x"
-			colnamesb[idx] = v.colName + ".y"
-		}
-	}
-	dfa.SetNames(colnamesa)
-	dfb.SetNames(colnamesb)
-
-	// Get the indexes of the rows we want to join
-	dfaIndexes := []int{}
-	dfbIndexes := []int{}
-	for i := 0; i < dfa.nRows; i++ {
-		for j := 0; j < dfb.nRows; j++ {
-			dfaIndexes = append(dfaIndexes, i)
-			dfbIndexes = append(dfbIndexes, j)
-		}
-	}
-
-	newdfa, _ := dfa.SubsetRows(dfaIndexes)
-	newdfb, _ := dfb.SubsetRows(dfbIndexes)
-	return Cbind(*newdfa, *newdfb)
-}
-
-// LeftJoin returns a DataFrame containing the left join of two other DataFrames.
-// This operation matches all rows that appear on the left DataFrame and matches
-// it with the existing ones on the right one, filling the missing rows on the
-// right with an empty value.
-func LeftJoin(a DataFrame, b DataFrame, keys ...string) (*DataFrame, error) {
-	dfa := a.copy()
-	dfb := b.copy()
-	// Check that we have all given keys in both DataFrames
-	errorArr := []string{}
-	for _, key := range keys {
-		ia, erra := dfa.colIndex(key)
-		ib, errb := dfb.colIndex(key)
-		if erra != nil {
-			errorArr = append(errorArr, fmt.Sprint("Can't find key \"", key, "\" on left DataFrame"))
-		}
-		if errb != nil {
-			errorArr = append(errorArr, fmt.Sprint("Can't find key \"", key, "\" on right DataFrame"))
-		}
-		// Check that the column types are the same between DataFrames
-		if len(errorArr) == 0 {
-			ta := dfa.Columns[ia].colType
-			tb := dfb.Columns[ib].colType
-			if ta != tb {
-				errorArr = append(errorArr, fmt.Sprint("Different types for key\"", key, "\". Left:", ta, " Right:", tb))
-			}
-		}
-	}
-	if len(errorArr) != 0 {
-		return nil, errors.New(strings.Join(errorArr, "\n"))
-	}
-
-	// Rename non key coumns with the same name on both DataFrames
-	colnamesa := dfa.Names()
-	colnamesb := dfb.Names()
-
-	for k, v := range colnamesa {
-		if !inStringSlice(v, keys) {
-			if idx, err := dfb.colIndex(v); err == nil {
-				colnamesa[k] = v + ".x"
-				colnamesb[idx] = v + ".y"
-			}
-		}
-	}
-	dfa.SetNames(colnamesa)
-	dfb.SetNames(colnamesb)
-
-	// Get the column indexes of both columns for the given keys
-	colIdxa := []int{}
-	colIdxb := []int{}
-	for _, key := range keys {
-		ia, erra := dfa.colIndex(key)
-		ib, errb := dfb.colIndex(key)
-		if erra == nil && errb == nil {
-			colIdxa = append(colIdxa, ia)
-			colIdxb = append(colIdxb, ib)
-		}
-	}
-
-	// Get the combined checksum for all keys in both DataFrames
-	checksumsa := make([][]byte, dfa.nRows)
-	checksumsb := make([][]byte, dfb.nRows)
-	for _, i := range colIdxa {
-		for k, v := range dfa.Columns[i].cells {
-			b := []byte{}
-			cs := v.Checksum()
-			b = append(b, cs[:]...)
-			checksumsa[k] = append(checksumsa[k], b...)
-		}
-	}
-	for _, i := range colIdxb {
-		for k, v := range dfb.Columns[i].cells {
-			b := []byte{}
-			cs := v.Checksum()
-			b = append(b, cs[:]...)
-			checksumsb[k] = append(checksumsb[k], b...)
-		}
-	}
-
-	// Get the indexes of the rows we want to join
-	dfaIndexes := []int{}
-	dfbIndexes := []int{}
-	for ka, ca := range checksumsa {
-		found := false
-		for kb, cb := range checksumsb {
-			if string(ca) == string(cb) {
-				dfaIndexes = append(dfaIndexes, ka)
-				dfbIndexes = append(dfbIndexes, kb)
-				found = true
-			}
-		}
-		if !found {
-			dfaIndexes = append(dfaIndexes, ka)
-			dfbIndexes = append(dfbIndexes, -1)
-
-		}
-	}
-
-	// Get the names of the elements that are not keys on the right DataFrame
-	nokeynamesb := []string{}
-	for _, v := range dfb.Columns {
-		if !inStringSlice(v.colName, keys) {
-			nokeynamesb = append(nokeynamesb, v.colName)
-		}
-	}
-
-	newdfa, _ := dfa.SubsetRows(dfaIndexes)
-	newdfb, _ := dfb.Subset(nokeynamesb, dfbIndexes)
-	return Cbind(*newdfa, *newdfb)
-}
-
-// RightJoin returns a DataFrame containing the right join of two other DataFrames.
-// This operation matches all rows that appear on the right DataFrame and matches
-// it with the existing ones on the left one, filling the missing rows on the
-// left with an empty value.
-func RightJoin(b DataFrame, a DataFrame, keys ...string) (*DataFrame, error) {
-	dfa := a.copy()
-	dfb := b.copy()
-	// Check that we have all given keys in both DataFrames
-	errorArr := []string{}
-	for _, key := range keys {
-		ia, erra := dfa.colIndex(key)
-		ib, errb := dfb.colIndex(key)
-		if erra != nil {
-			errorArr = append(errorArr, fmt.Sprint("Can't find key \"", key, "\" on left DataFrame"))
-		}
-		if errb != nil {
-			errorArr = append(errorArr, fmt.Sprint("Can't find key \"", key, "\" on right DataFrame"))
-		}
-		// Check that the column types are the same between DataFrames
-		if len(errorArr) == 0 {
-			ta := dfa.Columns[ia].colType
-			tb := dfb.Columns[ib].colType
-			if ta != tb {
-				errorArr = append(errorArr, fmt.Sprint("Different types for key\"", key, "\". Left:", ta, " Right:", tb))
-			}
-		}
-	}
-	if len(errorArr) != 0 {
-		return nil, errors.New(strings.Join(errorArr, "\n"))
-	}
-
-	// Rename non key coumns with the same name on both DataFrames
-	colnamesa := dfa.Names()
-	colnamesb := dfb.Names()
-
-	for k, v := range colnamesa {
-		if !inStringSlice(v, keys) {
-			if idx, err := dfb.colIndex(v); err == nil {
-				colnamesa[k] = v + ".y"
-				colnamesb[idx] = v + ".x"
-			}
-		}
-	}
-	dfa.SetNames(colnamesa)
-	dfb.SetNames(colnamesb)
-
-	// Get the column indexes of both columns for the given keys
-	colIdxa := []int{}
-	colIdxb := []int{}
-	for _, key := range keys {
-		ia, erra := dfa.colIndex(key)
-		ib, errb := dfb.colIndex(key)
-		if erra == nil && errb == nil {
-			colIdxa = append(colIdxa, ia)
-			colIdxb = append(colIdxb, ib)
-		}
-	}
-
-	// Get the combined checksum for all keys in both DataFrames
-	checksumsa := make([][]byte, dfa.nRows)
-	checksumsb := make([][]byte, dfb.nRows)
-	for _, i := range colIdxa {
-		for k, v := range dfa.Columns[i].cells {
-			b := []byte{}
-			cs := v.Checksum()
-			b = append(b, cs[:]...)
-			checksumsa[k] = append(checksumsa[k], b...)
-		}
-	}
-	for _, i := range colIdxb {
-		for k, v := range dfb.Columns[i].cells {
-			b := []byte{}
-			cs := v.Checksum()
-			b = append(b, cs[:]...)
-			checksumsb[k] = append(checksumsb[k], b...)
-		}
-	}
-
-	// Get the indexes of the rows we want to join
-	dfaIndexes := []int{}
-	dfbIndexes := []int{}
-	for ka, ca := range checksumsa {
-		found := false
-		for kb, cb := range checksumsb {
-			if string(ca) == string(cb) {
-				dfaIndexes = append(dfaIndexes, ka)
-				dfbIndexes = append(dfbIndexes, kb)
-				found = true
-			}
-		}
-		if !found {
-			dfaIndexes = append(dfaIndexes, ka)
-			dfbIndexes = append(dfbIndexes, -1)
-
-		}
-	}
-
-	// Get the names of the elements that are not keys on the right DataFrame
-	nokeynamesb := []string{}
-	for _, v := range dfb.Columns {
-		if !inStringSlice(v.colName, keys) {
-			nokeynamesb = append(nokeynamesb, v.colName)
-		}
+// Column returns a copy of the elements of the column with the given name
+func (df DataFrame) Column(colname string) (Cells, error) {
+	col, err := df.col(colname)
+	if err != nil {
+		return nil, err
 	}
-
-	newdfa, _ := dfa.SubsetRows(dfaIndexes)
-	newdfb, _ := dfb.Subset(nokeynamesb, dfbIndexes)
-	return Cbind(*newdfa, *newdfb)
+	return col.copy().cells, nil
 }
-*/
 
 func (d DataFrame) GetCell(colname string, row int) (Cell, string, error) {
 	col, ok := d.Columns[colname]
@@ -83,7 +83,7 @@ func TestDataFrame_LoadData(t *testing.T) {
 	}
 	df.LoadData(data)
 	expectedColnames := fmt.Sprint([]string{"V0", "V1", "V2", "V3"})
-	receivedColnames := fmt.Sprint(df.colNames)
+	receivedColnames := fmt.Sprint(df.Names())
 	if expectedColnames != receivedColnames {
 		t.Error(
 			"Colnames not being generated properly",
@@ -116,8 +116,12 @@ func TestDataFrame_LoadAndParse(t *testing.T) {
 	// Test parsing two columns as integers
 	df := DataFrame{}
 	df.LoadAndParse(data, T{"A": "int", "C": "int"})
-	if fmt.Sprint(df.colTypes) != "[df.Int df.String df.Int df.String]" {
-		t.Error("Incorrect type parsing" + fmt.Sprint(df.colTypes))
+	var types []string
+	for _, k := range df.Names() {
+		types = append(types, df.Columns[k].colType)
+	}
+	if fmt.Sprint(types) != "[df.Int df.String df.Int df.String]" {
+		t.Error("Incorrect type parsing" + fmt.Sprint(types))
 	}
 }
 
@@ -137,6 +141,34 @@ func TestDataFrame_SaveRecords(t *testing.T) {
 	}
 }
 
+func TestDataFrame_SaveJson(t *testing.T) {
+	d, err := New(
+		C{"B", Strings("a", nil)},
+		C{"A", Ints(1, 2)},
+		C{"C", Floats(1.5, nil)},
+		C{"D", Bools(true, false)},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := d.SaveJson()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Keys keep the order of the columns
+	expected := `[{"B":"a","A":1,"C":1.5,"D":true},{"B":null,"A":2,"C":null,"D":false}]`
+	received := string(b)
+	if expected != received {
+		t.Error(
+			"DataFrame not being saved as JSON properly\n",
+			"Expected:\n",
+			expected, "\n",
+			"Received:\n",
+			received,
+		)
+	}
+}
+
 func TestDataFrame_SubsetColumns(t *testing.T) {
 	data := [][]string{
 		[]string{"A", "B", "C", "D"},
@@ -167,6 +199,42 @@ func TestDataFrame_SubsetColumns(t *testing.T) {
 	}
 }
 
+func TestDataFrame_SelectColumns(t *testing.T) {
+	d, _ := New(
+		C{"A", Ints(1, 2)},
+		C{"B", Strings("a", "b")},
+		C{"C", Floats(1.5, nil)},
+	)
+	var tests = []struct {
+		names    []string
+		expected string
+	}{
+		{[]string{"C", "A"}, "[[C A] [1.5 1] [NA 2]]"},
+		{[]string{"B"}, "[[B] [a] [b]]"},
+		{[]string{"A", "Nope"}, "Column not found: Nope"},
+		{[]string{"A", "B", "A"}, "Duplicated column: A"},
+		{nil, ErrEmptySubset.Error()},
+	}
+	for k, v := range tests {
+		var received string
+		s, err := d.SelectColumns(v.names...)
+		if err != nil {
+			received = err.Error()
+		} else {
+			received = fmt.Sprint(s.SaveRecords())
+		}
+		if v.expected != received {
+			t.Error(
+				"Test", k, "\n",
+				"Expected:\n",
+				v.expected, "\n",
+				"Received:\n",
+				received,
+			)
+		}
+	}
+}
+
 func TestDataFrame_SubsetRows(t *testing.T) {
 	data := [][]string{
 		[]string{"A", "B", "C", "D"},
@@ -190,6 +258,28 @@ func TestDataFrame_SubsetRows(t *testing.T) {
 	if err != nil {
 		t.Error(err)
 	}
+
+	// Selecting no rows
+	if _, err = df.SubsetRows([]int{}); err != ErrEmptySubset {
+		t.Error("Expected ErrEmptySubset, received", err)
+	}
+	if _, err = df.SubsetRows(R{1, 1}); err != ErrEmptySubset {
+		t.Error("Expected ErrEmptySubset, received", err)
+	}
+}
+
+func TestDataFrame_Empty(t *testing.T) {
+	d, _ := New(
+		C{"B", Strings("a", "b")},
+		C{"A", Ints(1, nil)},
+	)
+	e := d.Empty()
+	if e.NRows() != 0 || fmt.Sprint(e.Names(), e.Types()) != "[B A] [df.String df.Int]" {
+		t.Error("Expected no rows with the columns [B A] [df.String df.Int], received", e.NRows(), "rows", e.Names(), e.Types())
+	}
+	if d.NRows() != 2 {
+		t.Error("Original DataFrame was modified")
+	}
 }
 
 func TestDataFrame_Rbind(t *testing.T) {
@@ -212,9 +302,18 @@ func TestDataFrame_Rbind(t *testing.T) {
 	dfB := DataFrame{}
 	dfB.LoadData(dataB)
 
-	_, err := Rbind(dfA, dfB)
+	dfC, err := Rbind(dfA, dfB)
 	if err != nil {
-		t.Error(err)
+		t.Fatal(err)
+	}
+	if a := fmt.Sprint(dfC.Columns["A"].cells); a != "[1 5 9 2 6 10]" {
+		t.Error("Columns not matched by name:", a)
+	}
+
+	// Columns missing on one of the DataFrames
+	dfD, _ := dfB.SubsetColumns([]string{"A", "B", "C"})
+	if _, err := Rbind(dfA, *dfD); err == nil {
+		t.Error("Mismatching columns but no error")
 	}
 }
 
@@ -0,0 +1,124 @@
+// Package dfhttp serves DataFrames as read only REST resources.
+//
+// A Handler answers GET and HEAD requests with the rows of a DataFrame,
+// selected with the query parameters:
+//
+//	columns=A,B      columns of the response, in the given order
+//	filter=Age>30    condition as on df.NewCondition, can be repeated
+//	sort=-Amount,ID  sorting columns, descending if prefixed with -
+//	limit=50         number of rows of the page
+//	offset=100       number of rows skipped before the page
+//
+// Rows are filtered, then sorted and then paginated. The total number of rows
+// that match the filters is sent on the X-Total-Count header, and the
+// previous and next pages are linked on the Link header.
+//
+// The format of the response is negotiated with the Accept header between
+// JSON (application/json), CSV (text/csv) and newline delimited JSON
+// (application/x-ndjson), or given with the format parameter. JSON responses
+// are an array with an object per row, as written by SaveJson. Errors are
+// returned as a JSON object with an error field.
+package dfhttp
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/kniren/gota/data-frame"
+)
+
+// Options configures a Handler. DefaultLimit is the number of rows of a page
+// when the request doesn't give a limit, 100 by default, and MaxLimit the
+// maximum number of rows of a page, 1000 by default. Larger limits are
+// reduced to MaxLimit.
+type Options struct {
+	DefaultLimit int
+	MaxLimit     int
+}
+
+// Handler serves a DataFrame over HTTP. It is safe for concurrent use.
+type Handler struct {
+	data *df.Shared
+	opts Options
+}
+
+// New returns a Handler that serves the given DataFrame
+func New(d df.DataFrame, opts ...Options) *Handler {
+	return NewShared(df.NewShared(d), opts...)
+}
+
+// NewShared returns a Handler that serves the current DataFrame of s, so the
+// data can be updated while it is served. Each request reads a snapshot.
+func NewShared(s *df.Shared, opts ...Options) *Handler {
+	var o Options
+	if len(opts) > 0 {
+		o = opts[0]
+	}
+	if o.DefaultLimit <= 0 {
+		o.DefaultLimit = 100
+	}
+	if o.MaxLimit <= 0 {
+		o.MaxLimit = 1000
+	}
+	if o.DefaultLimit > o.MaxLimit {
+		o.DefaultLimit = o.MaxLimit
+	}
+	return &Handler{data: s, opts: o}
+}
+
+// ServeHTTP answers a request with a page of the rows of the DataFrame
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		writeError(w, http.StatusMethodNotAllowed, "Method "+r.Method+" not allowed")
+		return
+	}
+	params := r.URL.Query()
+	f, err := negotiate(params.Get("format"), r.Header.Get("Accept"))
+	if err != nil {
+		status := http.StatusNotAcceptable
+		if params.Get("format") != "" {
+			status = http.StatusBadRequest
+		}
+		writeError(w, status, err.Error())
+		return
+	}
+	q, err := parseQuery(params, h.opts)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	page, total, err := q.apply(h.data.Load())
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	body, err := f.encode(page)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	header := w.Header()
+	header.Set("Content-Type", f.contentType)
+	header.Set("Vary", "Accept")
+	header.Set("X-Total-Count", strconv.Itoa(total))
+	if links := q.links(r.URL, total); links != "" {
+		header.Set("Link", links)
+	}
+	header.Set("Content-Length", strconv.Itoa(len(body)))
+	w.WriteHeader(http.StatusOK)
+	if r.Method != http.MethodHead {
+		w.Write(body)
+	}
+}
+
+// writeError writes an error response as a JSON object
+func writeError(w http.ResponseWriter, status int, msg string) {
+	body, _ := json.Marshal(map[string]string{"error": msg})
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(status)
+	w.Write(append(body, '\n'))
+}
@@ -0,0 +1,208 @@
+package dfhttp
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kniren/gota/data-frame"
+)
+
+func testFrame(t *testing.T) df.DataFrame {
+	d, err := df.New(
+		df.C{"ID", df.Ints(1, 2, 3, 4, 5)},
+		df.C{"Name", df.Strings("Alice", "Bob", "Carol", "Dan", "Eve")},
+		df.C{"Age", df.Ints(32, 25, 41, nil, 38)},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return *d
+}
+
+func TestHandler(t *testing.T) {
+	h := New(testFrame(t), Options{DefaultLimit: 2})
+	table := []struct {
+		method   string
+		url      string
+		accept   string
+		status   int
+		ctype    string
+		total    string
+		link     string
+		expected string
+	}{
+		{
+			"GET", "/people", "",
+			200, "application/json", "5",
+			`</people?limit=2&offset=2>; rel="next"`,
+			`[{"ID":1,"Name":"Alice","Age":32},{"ID":2,"Name":"Bob","Age":25}]` + "\n",
+		},
+		{
+			"GET", "/people?offset=2&limit=2", "",
+			200, "application/json", "5",
+			`</people?limit=2&offset=0>; rel="prev", </people?limit=2&offset=4>; rel="next"`,
+			`[{"ID":3,"Name":"Carol","Age":41},{"ID":4,"Name":"Dan","Age":null}]` + "\n",
+		},
+		{
+			"GET", "/people?offset=4", "text/csv",
+			200, "text/csv; charset=utf-8", "5",
+			`</people?limit=2&offset=2>; rel="prev"`,
+			"ID,Name,Age\n5,Eve,38\n",
+		},
+		{
+			"GET", "/people?columns=Name,ID&filter=Age>30&sort=-Age&limit=10", "application/x-ndjson",
+			200, "application/x-ndjson", "3",
+			"",
+			`{"Name":"Carol","ID":3}` + "\n" + `{"Name":"Eve","ID":5}` + "\n" + `{"Name":"Alice","ID":1}` + "\n",
+		},
+		{
+			"GET", "/people?filter=Age>30&filter=ID<3&format=csv", "",
+			200, "text/csv; charset=utf-8", "1",
+			"",
+			"ID,Name,Age\n1,Alice,32\n",
+		},
+		{
+			"GET", "/people?filter=Age>50&format=csv", "",
+			200, "text/csv; charset=utf-8", "0",
+			"",
+			"ID,Name,Age\n",
+		},
+		{
+			"GET", "/people?offset=10&columns=Name", "application/json",
+			200, "application/json", "5",
+			`</people?columns=Name&limit=2&offset=8>; rel="prev"`,
+			"[]\n",
+		},
+		{
+			"GET", "/people?limit=1", "text/*;q=0.5, application/json;q=0.4",
+			200, "text/csv; charset=utf-8", "5",
+			`</people?limit=1&offset=1>; rel="next"`,
+			"ID,Name,Age\n1,Alice,32\n",
+		},
+		{
+			"GET", "/people?limit=1", "application/json;q=0, */*",
+			200, "text/csv; charset=utf-8", "5",
+			`</people?limit=1&offset=1>; rel="next"`,
+			"ID,Name,Age\n1,Alice,32\n",
+		},
+		{
+			"HEAD", "/people?limit=1", "application/ndjson",
+			200, "application/x-ndjson", "5",
+			`</people?limit=1&offset=1>; rel="next"`,
+			"",
+		},
+		{
+			"GET", "/people", "application/xml",
+			406, "application/json", "",
+			"",
+			`{"error":"None of the accepted types is supported: application/xml"}` + "\n",
+		},
+		{
+			"GET", "/people?format=xml", "",
+			400, "application/json", "",
+			"",
+			`{"error":"Unknown format \"xml\""}` + "\n",
+		},
+		{
+			"GET", "/people?limit=-1", "",
+			400, "application/json", "",
+			"",
+			`{"error":"Invalid limit \"-1\""}` + "\n",
+		},
+		{
+			"GET", "/people?offset=x", "",
+			400, "application/json", "",
+			"",
+			`{"error":"Invalid offset \"x\""}` + "\n",
+		},
+		{
+			"GET", "/people?filter=Age", "",
+			400, "application/json", "",
+			"",
+			`{"error":"Invalid condition \"Age\""}` + "\n",
+		},
+		{
+			"GET", "/people?columns=Name,Email", "",
+			400, "application/json", "",
+			"",
+			`{"error":"Column not found: Email"}` + "\n",
+		},
+		{
+			"GET", "/people?sort=Email", "",
+			400, "application/json", "",
+			"",
+			`{"error":"Column not found: Email"}` + "\n",
+		},
+		{
+			"POST", "/people", "",
+			405, "application/json", "",
+			"",
+			`{"error":"Method POST not allowed"}` + "\n",
+		},
+	}
+	for k, v := range table {
+		r := httptest.NewRequest(v.method, v.url, nil)
+		if v.accept != "" {
+			r.Header.Set("Accept", v.accept)
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		received := []string{
+			http.StatusText(w.Code),
+			w.Header().Get("Content-Type"),
+			w.Header().Get("X-Total-Count"),
+			w.Header().Get("Link"),
+			w.Body.String(),
+		}
+		expected := []string{http.StatusText(v.status), v.ctype, v.total, v.link, v.expected}
+		for i := range expected {
+			if expected[i] != received[i] {
+				t.Error(
+					"Test", k, v.url, "\n",
+					"Expected:\n",
+					expected, "\n",
+					"Received:\n",
+					received,
+				)
+				break
+			}
+		}
+	}
+}
+
+func TestHandler_MaxLimit(t *testing.T) {
+	h := New(testFrame(t), Options{MaxLimit: 3})
+	r := httptest.NewRequest("GET", "/?limit=100&format=csv", nil)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	expected := "ID,Name,Age\n1,Alice,32\n2,Bob,25\n3,Carol,41\n"
+	if w.Body.String() != expected {
+		t.Error("Expected:\n", expected, "\nReceived:\n", w.Body.String())
+	}
+	if link := w.Header().Get("Link"); link != `</?format=csv&limit=3&offset=3>; rel="next"` {
+		t.Error("Unexpected Link header:", link)
+	}
+}
+
+func TestNewShared(t *testing.T) {
+	s := df.NewShared(testFrame(t))
+	h := NewShared(s)
+	get := func() string {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest("GET", "/?columns=ID&format=csv", nil))
+		return w.Body.String()
+	}
+	if received := get(); received != "ID\n1\n2\n3\n4\n5\n" {
+		t.Error("Unexpected body before the update:\n", received)
+	}
+	err := s.Update(func(d df.DataFrame) (*df.DataFrame, error) {
+		return d.SubsetRows(df.R{From: 0, To: 2})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if received := get(); received != "ID\n1\n2\n" {
+		t.Error("Unexpected body after the update:\n", received)
+	}
+}
@@ -0,0 +1,120 @@
+package dfhttp
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"strconv"
+	"strings"
+
+	"github.com/kniren/gota/data-frame"
+)
+
+// format is a representation of the DataFrame that can be served
+type format struct {
+	name        string
+	mediaType   string
+	contentType string
+	encode      func(d *df.DataFrame) ([]byte, error)
+}
+
+// formats are the supported formats, by order of preference
+var formats = []format{
+	{"json", "application/json", "application/json", encodeJson},
+	{"csv", "text/csv", "text/csv; charset=utf-8", func(d *df.DataFrame) ([]byte, error) { return d.SaveCsv() }},
+	{"ndjson", "application/x-ndjson", "application/x-ndjson", encodeNdjson},
+}
+
+func encodeJson(d *df.DataFrame) ([]byte, error) {
+	b, err := d.SaveJson()
+	if err != nil {
+		return nil, err
+	}
+	return append(b, '\n'), nil
+}
+
+// encodeNdjson writes a JSON object per row, each on its own line
+func encodeNdjson(d *df.DataFrame) ([]byte, error) {
+	b, err := d.SaveJson()
+	if err != nil {
+		return nil, err
+	}
+	var rows []json.RawMessage
+	if err := json.Unmarshal(b, &rows); err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	for _, row := range rows {
+		buf.Write(row)
+		buf.WriteByte('\n')
+	}
+	return buf.Bytes(), nil
+}
+
+// negotiate returns the format given by name, or otherwise the one preferred
+// by the Accept header. JSON is used when the header is empty.
+func negotiate(name, accept string) (format, error) {
+	if name != "" {
+		for _, f := range formats {
+			if f.name == name {
+				return f, nil
+			}
+		}
+		return format{}, fmt.Errorf("Unknown format %q", name)
+	}
+	if strings.TrimSpace(accept) == "" {
+		return formats[0], nil
+	}
+
+	// Each format takes the quality of the most specific range that
+	// includes it
+	qs := make([]float64, len(formats))
+	specificity := make([]int, len(formats))
+	for _, r := range strings.Split(accept, ",") {
+		mediaRange, params, err := mime.ParseMediaType(strings.TrimSpace(r))
+		if err != nil {
+			continue
+		}
+		q := 1.0
+		if s, ok := params["q"]; ok {
+			if q, err = strconv.ParseFloat(s, 64); err != nil {
+				continue
+			}
+		}
+		for i, f := range formats {
+			if n := matches(mediaRange, f.mediaType); n > specificity[i] {
+				qs[i], specificity[i] = q, n
+			}
+		}
+	}
+	// Ties are broken by the order of preference of the formats
+	best := -1
+	for i, q := range qs {
+		if q > 0 && (best < 0 || q > qs[best]) {
+			best = i
+		}
+	}
+	if best < 0 {
+		return format{}, fmt.Errorf("None of the accepted types is supported: %s", accept)
+	}
+	return formats[best], nil
+}
+
+// matches returns how specific the media range of an Accept header is if it
+// includes the given media type: 3 for the type itself, 2 for type/* and 1
+// for */*. It returns 0 if the range doesn't include the type.
+func matches(mediaRange, mediaType string) int {
+	switch {
+	case mediaRange == mediaType:
+		return 3
+	// application/ndjson is also used for newline delimited JSON
+	case mediaRange == "application/ndjson" && mediaType == "application/x-ndjson":
+		return 3
+	case strings.HasSuffix(mediaRange, "/*") && strings.HasPrefix(mediaType, strings.TrimSuffix(mediaRange, "*")):
+		return 2
+	case mediaRange == "*/*":
+		return 1
+	}
+	return 0
+}
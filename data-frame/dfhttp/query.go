@@ -0,0 +1,139 @@
+package dfhttp
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/kniren/gota/data-frame"
+)
+
+// query is the selection of rows and columns of a request
+type query struct {
+	columns []string
+	filters map[string]df.Condition
+	sort    []df.SortKey
+	limit   int
+	offset  int
+}
+
+// parseQuery reads the selection from the parameters of a request
+func parseQuery(params url.Values, opts Options) (query, error) {
+	q := query{limit: opts.DefaultLimit}
+	var err error
+	if q.columns, err = names(params, "columns"); err != nil {
+		return query{}, err
+	}
+	sort, err := names(params, "sort")
+	if err != nil {
+		return query{}, err
+	}
+	for _, k := range sort {
+		key := df.SortKey{Column: k}
+		if strings.HasPrefix(k, "-") {
+			key.Column, key.Desc = k[1:], true
+		}
+		q.sort = append(q.sort, key)
+	}
+	if q.filters, err = df.ParseConditions(params["filter"]); err != nil {
+		return query{}, err
+	}
+	if s := params.Get("limit"); s != "" {
+		if q.limit, err = strconv.Atoi(s); err != nil || q.limit < 0 {
+			return query{}, fmt.Errorf("Invalid limit %q", s)
+		}
+		if q.limit > opts.MaxLimit {
+			q.limit = opts.MaxLimit
+		}
+	}
+	if s := params.Get("offset"); s != "" {
+		if q.offset, err = strconv.Atoi(s); err != nil || q.offset < 0 {
+			return query{}, fmt.Errorf("Invalid offset %q", s)
+		}
+	}
+	return q, nil
+}
+
+// names returns the column names of a parameter, given as a comma separated
+// list
+func names(params url.Values, key string) ([]string, error) {
+	s := params.Get(key)
+	if s == "" {
+		return nil, nil
+	}
+	ret := strings.Split(s, ",")
+	for i, k := range ret {
+		if ret[i] = strings.TrimSpace(k); ret[i] == "" {
+			return nil, fmt.Errorf("Empty column name on %s", key)
+		}
+	}
+	return ret, nil
+}
+
+// apply returns the page of the DataFrame selected by the query and the
+// number of rows that match the filters
+func (q query) apply(d df.DataFrame) (*df.DataFrame, int, error) {
+	ret := &d
+	var err error
+	if len(q.filters) > 0 {
+		if ret, err = d.ConditionRows(q.filters); err == df.ErrEmptySubset {
+			ret, err = d.Empty(), nil
+		}
+		if err != nil {
+			return nil, 0, err
+		}
+	}
+	if len(q.sort) > 0 {
+		if ret, err = ret.Arrange(q.sort...); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	total := ret.NRows()
+	from, to := q.offset, q.offset+q.limit
+	if to > total {
+		to = total
+	}
+	switch {
+	case from >= to:
+		ret = ret.Empty()
+	case from > 0 || to < total:
+		if ret, err = ret.SubsetRows(df.R{From: from, To: to}); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	if len(q.columns) > 0 {
+		if ret, err = ret.SelectColumns(q.columns...); err != nil {
+			return nil, 0, err
+		}
+	}
+	return ret, total, nil
+}
+
+// links returns the Link header with the previous and next pages, if any
+func (q query) links(u *url.URL, total int) string {
+	if q.limit == 0 {
+		return ""
+	}
+	link := func(offset int, rel string) string {
+		params := u.Query()
+		params.Set("offset", strconv.Itoa(offset))
+		params.Set("limit", strconv.Itoa(q.limit))
+		page := url.URL{Path: u.Path, RawQuery: params.Encode()}
+		return fmt.Sprintf("<%s>; rel=%q", page.String(), rel)
+	}
+	var links []string
+	if q.offset > 0 {
+		prev := q.offset - q.limit
+		if prev < 0 {
+			prev = 0
+		}
+		links = append(links, link(prev, "prev"))
+	}
+	if q.offset+q.limit < total {
+		links = append(links, link(q.offset+q.limit, "next"))
+	}
+	return strings.Join(links, ", ")
+}
@@ -0,0 +1,27 @@
+// Package dftest provides helpers to compare DataFrames on tests.
+package dftest
+
+import (
+	"strings"
+
+	"github.com/kniren/gota/data-frame"
+)
+
+// TestingT is the subset of testing.TB used by the assertions
+type TestingT interface {
+	Helper()
+	Errorf(format string, args ...interface{})
+}
+
+// AssertFrameEqual reports a test error listing the differing elements if
+// the DataFrames got and want are not equal following the given options. It
+// returns true if they are equal.
+func AssertFrameEqual(t TestingT, got, want df.DataFrame, opts df.EqualOptions) bool {
+	t.Helper()
+	diff := df.Differences(got, want, opts)
+	if len(diff) == 0 {
+		return true
+	}
+	t.Errorf("DataFrames are not equal:\n  %s", strings.Join(diff, "\n  "))
+	return false
+}
@@ -0,0 +1,55 @@
+package dftest
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/kniren/gota/data-frame"
+)
+
+type recorder struct {
+	errors []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func TestAssertFrameEqual(t *testing.T) {
+	want, _ := df.New(
+		df.C{"A", df.Strings("a", "b", nil)},
+		df.C{"B", df.Floats(1.0, 2.0, 3.0)},
+	)
+	got, _ := df.New(
+		df.C{"B", df.Floats(3.0, 1.0001, 2.5)},
+		df.C{"A", df.Strings(nil, "a", "b")},
+	)
+
+	r := &recorder{}
+	if AssertFrameEqual(r, *got, *want, df.EqualOptions{}) {
+		t.Error("Expected the DataFrames to be different")
+	}
+	expected := "[DataFrames are not equal:\n  columns: got [B A], want [A B]]"
+	if fmt.Sprint(r.errors) != expected {
+		t.Error("Expected:\n", expected, "\nReceived:\n", r.errors)
+	}
+
+	r = &recorder{}
+	opts := df.EqualOptions{
+		IgnoreColumnOrder: true,
+		IgnoreRowOrder:    true,
+		Tolerance:         0.001,
+	}
+	AssertFrameEqual(r, *got, *want, opts)
+	expected = "[DataFrames are not equal:\n  sorted row 1, column B: got 2.5, want 2]"
+	if fmt.Sprint(r.errors) != expected {
+		t.Error("Expected:\n", expected, "\nReceived:\n", r.errors)
+	}
+
+	r = &recorder{}
+	if !AssertFrameEqual(r, *want, *want, df.EqualOptions{}) || len(r.errors) != 0 {
+		t.Error("Expected the DataFrames to be equal, received:", r.errors)
+	}
+}
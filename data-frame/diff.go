@@ -0,0 +1,250 @@
+package df
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// CompareOptions configures how the elements of two DataFrames are compared.
+// Numeric elements whose difference is not greater than Tolerance are
+// considered equal. Columns on Ignore are not compared.
+type CompareOptions struct {
+	Tolerance float64
+	Ignore    []string
+}
+
+// CellChange is a change of an element of a row between two DataFrames. The
+// row is identified by the elements of its key columns.
+type CellChange struct {
+	Key    Cells
+	Column string
+	Old    Cell
+	New    Cell
+}
+
+// Diff contains the differences between two versions of a DataFrame. Added
+// has the rows of the new version whose key doesn't appear on the old one,
+// Removed the rows of the old version whose key doesn't appear on the new one
+// and Modified the rows of the new version with changes on any compared
+// column. Changes lists the changed elements in the order of the rows of the
+// new version and of its columns.
+type Diff struct {
+	Keys           []string
+	Added          *DataFrame
+	Removed        *DataFrame
+	Modified       *DataFrame
+	Changes        []CellChange
+	AddedColumns   []string
+	RemovedColumns []string
+}
+
+// Empty returns true if there are no differences
+func (d Diff) Empty() bool {
+	return d.Added.NRows() == 0 && d.Removed.NRows() == 0 &&
+		len(d.Changes) == 0 && len(d.AddedColumns) == 0 && len(d.RemovedColumns) == 0
+}
+
+// equalCells returns true if two elements of a column are equal following
+// the given options
+func equalCells(a, b Cell, colType string, opts CompareOptions) (bool, error) {
+	switch {
+	case a.IsNA() && b.IsNA():
+		return true, nil
+	case a.IsNA() || b.IsNA():
+		return false, nil
+	}
+	if opts.Tolerance > 0 && numericKind(a) != kindNone && numericKind(b) != kindNone {
+		fa, _ := a.Float()
+		fb, _ := b.Float()
+		return math.Abs(*fa-*fb) <= opts.Tolerance, nil
+	}
+	cmp, err := compareCells(a, b, colType)
+	return cmp == 0, err
+}
+
+// keyIndex returns the position of each row of the DataFrame by the checksum
+// of its keys. Duplicated keys throw an error.
+func keyIndex(df DataFrame, keys []string) (map[string]int, error) {
+	index := make(map[string]int, df.nRows)
+	for i := 0; i < df.nRows; i++ {
+		k := rowChecksum(df, keys, i)
+		if _, ok := index[k]; ok {
+			return nil, fmt.Errorf("Duplicated key on row %d", i)
+		}
+		index[k] = i
+	}
+	return index, nil
+}
+
+// Compare returns the differences between the before and after versions of a
+// DataFrame whose rows are identified by the given key columns
+func Compare(before, after DataFrame, keys ...string) (*Diff, error) {
+	return CompareWith(before, after, CompareOptions{}, keys...)
+}
+
+// CompareWith returns the differences between the before and after versions of a
+// DataFrame whose rows are identified by the given key columns, comparing the
+// elements with the given options. Only the columns present on both versions
+// are compared, and must have the same type.
+func CompareWith(before, after DataFrame, opts CompareOptions, keys ...string) (*Diff, error) {
+	if len(keys) == 0 {
+		return nil, errors.New("No key columns given")
+	}
+	for _, k := range keys {
+		o, err := before.col(k)
+		if err != nil {
+			return nil, err
+		}
+		n, err := after.col(k)
+		if err != nil {
+			return nil, err
+		}
+		if o.colType != n.colType {
+			return nil, errors.New("Mismatching types on key column " + k)
+		}
+	}
+
+	diff := &Diff{Keys: keys}
+	skip := map[string]bool{}
+	for _, k := range append(keys, opts.Ignore...) {
+		skip[k] = true
+	}
+	compared := []string{}
+	for _, k := range after.Names() {
+		o, ok := before.Columns[k]
+		switch {
+		case !ok:
+			diff.AddedColumns = append(diff.AddedColumns, k)
+		case skip[k]:
+		case o.colType != after.Columns[k].colType:
+			return nil, errors.New("Mismatching types on column " + k)
+		default:
+			compared = append(compared, k)
+		}
+	}
+	for _, k := range before.Names() {
+		if _, ok := after.Columns[k]; !ok {
+			diff.RemovedColumns = append(diff.RemovedColumns, k)
+		}
+	}
+
+	oldIndex, err := keyIndex(before, keys)
+	if err != nil {
+		return nil, fmt.Errorf("before: %v", err)
+	}
+	newIndex, err := keyIndex(after, keys)
+	if err != nil {
+		return nil, fmt.Errorf("after: %v", err)
+	}
+
+	added, modified, removed := []setRow{}, []setRow{}, []setRow{}
+	for i := 0; i < after.nRows; i++ {
+		j, ok := oldIndex[rowChecksum(after, keys, i)]
+		if !ok {
+			added = append(added, setRow{&after, i})
+			continue
+		}
+		changed := false
+		for _, k := range compared {
+			col := after.Columns[k]
+			a, b := before.Columns[k].cells[j], col.cells[i]
+			eq, err := equalCells(a, b, col.colType, opts)
+			if err != nil {
+				return nil, err
+			}
+			if !eq {
+				key := Cells{}
+				for _, kk := range keys {
+					key = append(key, after.Columns[kk].cells[i].Copy())
+				}
+				diff.Changes = append(diff.Changes, CellChange{key, k, a.Copy(), b.Copy()})
+				changed = true
+			}
+		}
+		if changed {
+			modified = append(modified, setRow{&after, i})
+		}
+	}
+	for i := 0; i < before.nRows; i++ {
+		if _, ok := newIndex[rowChecksum(before, keys, i)]; !ok {
+			removed = append(removed, setRow{&before, i})
+		}
+	}
+
+	diff.Added = setResult(after, after.Names(), added)
+	diff.Modified = setResult(after, after.Names(), modified)
+	diff.Removed = setResult(before, before.Names(), removed)
+	return diff, nil
+}
+
+// formatKey returns the key of a row as "key=value" pairs
+func (d Diff) formatKey(key Cells) string {
+	parts := make([]string, 0, len(key))
+	for i, k := range d.Keys {
+		parts = append(parts, k+"="+key[i].String())
+	}
+	return strings.Join(parts, ", ")
+}
+
+// rowKeys returns the formatted keys of the rows of a DataFrame of the Diff
+func (d Diff) rowKeys(df *DataFrame) []string {
+	ret := []string{}
+	for i := 0; i < df.NRows(); i++ {
+		key := Cells{}
+		for _, k := range d.Keys {
+			key = append(key, df.Columns[k].cells[i])
+		}
+		ret = append(ret, d.formatKey(key))
+	}
+	return ret
+}
+
+func plural(n int, s string) string {
+	if n == 1 {
+		return fmt.Sprintf("%d %s", n, s)
+	}
+	return fmt.Sprintf("%d %ss", n, s)
+}
+
+// Report returns a human readable description of the differences
+func (d Diff) Report() string {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "%s added, %s removed, %s modified\n",
+		plural(d.Added.NRows(), "row"),
+		plural(d.Removed.NRows(), "row"),
+		plural(d.Modified.NRows(), "row"),
+	)
+	if len(d.AddedColumns) > 0 {
+		fmt.Fprintf(&buf, "Added columns: %s\n", strings.Join(d.AddedColumns, ", "))
+	}
+	if len(d.RemovedColumns) > 0 {
+		fmt.Fprintf(&buf, "Removed columns: %s\n", strings.Join(d.RemovedColumns, ", "))
+	}
+	for _, v := range []struct {
+		title string
+		df    *DataFrame
+	}{{"Added", d.Added}, {"Removed", d.Removed}} {
+		if v.df.NRows() == 0 {
+			continue
+		}
+		fmt.Fprintf(&buf, "%s:\n", v.title)
+		for _, k := range d.rowKeys(v.df) {
+			fmt.Fprintf(&buf, "  %s\n", k)
+		}
+	}
+	if len(d.Changes) > 0 {
+		fmt.Fprintf(&buf, "Modified:\n")
+		last := ""
+		for _, c := range d.Changes {
+			if key := d.formatKey(c.Key); key != last {
+				fmt.Fprintf(&buf, "  %s\n", key)
+				last = key
+			}
+			fmt.Fprintf(&buf, "    %s: %s -> %s\n", c.Column, c.Old, c.New)
+		}
+	}
+	return buf.String()
+}
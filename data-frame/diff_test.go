@@ -0,0 +1,119 @@
+package df
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestCompare(t *testing.T) {
+	before, _ := New(
+		C{"ID", Ints(1, 2, 3, 4)},
+		C{"Name", Strings("a", "b", "c", "d")},
+		C{"Price", Floats(1.0, 2.0, 3.0, nil)},
+		C{"Notes", Strings("x", "y", "z", "w")},
+	)
+	after, _ := New(
+		C{"ID", Ints(4, 2, 3, 5)},
+		C{"Name", Strings("d", "B", "c", "e")},
+		C{"Price", Floats(4.0, 2.0, 3.001, 5.0)},
+		C{"Notes", Strings("w", "y", "changed", "v")},
+	)
+
+	var tests = []struct {
+		opts     CompareOptions
+		added    string
+		removed  string
+		modified string
+		changes  string
+	}{
+		{
+			CompareOptions{},
+			"[[ID Name Price Notes] [5 e 5 v]]",
+			"[[ID Name Price Notes] [1 a 1 x]]",
+			"[[ID Name Price Notes] [4 d 4 w] [2 B 2 y] [3 c 3.001 changed]]",
+			"[{[4] Price NA 4} {[2] Name b B} {[3] Price 3 3.001} {[3] Notes z changed}]",
+		},
+		{
+			CompareOptions{Tolerance: 0.01, Ignore: []string{"Notes"}},
+			"[[ID Name Price Notes] [5 e 5 v]]",
+			"[[ID Name Price Notes] [1 a 1 x]]",
+			"[[ID Name Price Notes] [4 d 4 w] [2 B 2 y]]",
+			"[{[4] Price NA 4} {[2] Name b B}]",
+		},
+	}
+	for k, v := range tests {
+		d, err := CompareWith(*before, *after, v.opts, "ID")
+		if err != nil {
+			t.Error("Test", k, ":", err)
+			continue
+		}
+		received := []string{
+			fmt.Sprint(d.Added.SaveRecords()),
+			fmt.Sprint(d.Removed.SaveRecords()),
+			fmt.Sprint(d.Modified.SaveRecords()),
+			fmt.Sprint(d.Changes),
+		}
+		expected := []string{v.added, v.removed, v.modified, v.changes}
+		for i := range expected {
+			if expected[i] != received[i] {
+				t.Error(
+					"Test", k, "\n",
+					"Expected:\n",
+					expected[i], "\n",
+					"Received:\n",
+					received[i],
+				)
+			}
+		}
+	}
+
+	d, _ := Compare(*before, *after, "ID")
+	expected := `1 row added, 1 row removed, 3 rows modified
+Added:
+  ID=5
+Removed:
+  ID=1
+Modified:
+  ID=4
+    Price: NA -> 4
+  ID=2
+    Name: b -> B
+  ID=3
+    Price: 3 -> 3.001
+    Notes: z -> changed
+`
+	if received := d.Report(); received != expected {
+		t.Error("Expected report:\n", expected, "\nReceived:\n", received)
+	}
+
+	d, err := Compare(*before, *before, "ID", "Name")
+	if err != nil {
+		t.Error(err)
+	} else if !d.Empty() || d.Report() != "0 rows added, 0 rows removed, 0 rows modified\n" {
+		t.Error("Expected no differences, received:\n", d.Report())
+	}
+
+	renamed, _ := before.SubsetColumns([]string{"ID", "Name", "Price"})
+	renamed, _ = renamed.withColumn("Comment", Strings("x", "y", "z", "w"))
+	d, err = Compare(*before, *renamed, "ID")
+	if err != nil {
+		t.Error(err)
+	} else if fmt.Sprint(d.AddedColumns, d.RemovedColumns, d.Empty()) != "[Comment] [Notes] false" {
+		t.Error("Expected column changes, received:", d.AddedColumns, d.RemovedColumns)
+	}
+
+	// Errors
+	dup, _ := New(C{"ID", Ints(1, 1)}, C{"Name", Strings("a", "b")})
+	if _, err := Compare(*before, *dup, "ID"); err == nil {
+		t.Error("Expected an error for duplicated keys")
+	}
+	if _, err := Compare(*before, *after, "Unknown"); err == nil {
+		t.Error("Expected an error for a missing key column")
+	}
+	if _, err := Compare(*before, *after); err == nil {
+		t.Error("Expected an error when no keys are given")
+	}
+	if fmt.Sprint(before.Columns["Name"].cells) != "[a b c d]" {
+		t.Error("Original DataFrame was modified:", before.Columns["Name"].cells)
+	}
+}
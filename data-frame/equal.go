@@ -0,0 +1,107 @@
+package df
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// EqualOptions configures the comparison of two DataFrames with Equal. With
+// IgnoreColumnOrder the columns are matched by name and with IgnoreRowOrder
+// the rows of both DataFrames are sorted by all their columns before being
+// compared. Numeric elements whose difference is not greater than Tolerance
+// are considered equal. NA elements are equal to each other unless StrictNA
+// is set.
+type EqualOptions struct {
+	IgnoreColumnOrder bool
+	IgnoreRowOrder    bool
+	Tolerance         float64
+	StrictNA          bool
+}
+
+// maxDifferences is the maximum number of differing elements listed by
+// Differences
+const maxDifferences = 10
+
+// Equal returns true if both DataFrames have the same columns, with the same
+// types, and the same elements
+func Equal(a, b DataFrame, opts ...EqualOptions) bool {
+	return len(Differences(a, b, opts...)) == 0
+}
+
+// Differences returns a readable description of the differences between the
+// DataFrames got and want, or nil if they are equal following the given
+// options. If the schemas differ only the schema is described, otherwise the
+// differing elements are listed by row and column.
+func Differences(got, want DataFrame, opts ...EqualOptions) []string {
+	var o EqualOptions
+	if len(opts) > 0 {
+		o = opts[0]
+	}
+
+	gotNames, wantNames := got.Names(), want.Names()
+	if o.IgnoreColumnOrder {
+		gotNames = append([]string(nil), gotNames...)
+		sort.Strings(gotNames)
+		wantNames = append([]string(nil), wantNames...)
+		sort.Strings(wantNames)
+	}
+	if strings.Join(gotNames, "\x00") != strings.Join(wantNames, "\x00") {
+		return []string{fmt.Sprintf("columns: got %v, want %v", got.Names(), want.Names())}
+	}
+	ret := []string{}
+	for _, k := range wantNames {
+		if g, w := got.Columns[k].colType, want.Columns[k].colType; g != w {
+			ret = append(ret, fmt.Sprintf("column %s: got type %s, want %s", k, g, w))
+		}
+	}
+	if got.nRows != want.nRows {
+		ret = append(ret, fmt.Sprintf("rows: got %d, want %d", got.nRows, want.nRows))
+	}
+	if len(ret) > 0 {
+		return ret
+	}
+
+	row := "row %d"
+	if o.IgnoreRowOrder && want.nRows > 1 {
+		keys := make([]SortKey, 0, len(wantNames))
+		for _, k := range wantNames {
+			keys = append(keys, SortKey{Column: k})
+		}
+		g, err := got.Arrange(keys...)
+		if err != nil {
+			return []string{err.Error()}
+		}
+		w, err := want.Arrange(keys...)
+		if err != nil {
+			return []string{err.Error()}
+		}
+		got, want = *g, *w
+		row = "sorted row %d"
+	}
+
+	n := 0
+	for i := 0; i < want.nRows; i++ {
+		for _, k := range want.Names() {
+			col := want.Columns[k]
+			g, w := got.Columns[k].cells[i], col.cells[i]
+			eq, err := equalCells(g, w, col.colType, CompareOptions{Tolerance: o.Tolerance})
+			if err != nil {
+				return []string{err.Error()}
+			}
+			if eq && !(o.StrictNA && g.IsNA()) {
+				continue
+			}
+			if n++; n <= maxDifferences {
+				ret = append(ret, fmt.Sprintf(row+", column %s: got %s, want %s", i, k, g, w))
+			}
+		}
+	}
+	if n > maxDifferences {
+		ret = append(ret, fmt.Sprintf("... and %d more differences", n-maxDifferences))
+	}
+	if len(ret) == 0 {
+		return nil
+	}
+	return ret
+}
@@ -0,0 +1,56 @@
+package df
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestEqual(t *testing.T) {
+	a, _ := New(
+		C{"A", Strings("a", "b", nil)},
+		C{"B", Floats(1.0, 2.0, nil)},
+	)
+	reordered, _ := New(
+		C{"B", Floats(nil, 1.0, 2.0)},
+		C{"A", Strings(nil, "a", "b")},
+	)
+	near, _ := New(
+		C{"A", Strings("a", "b", nil)},
+		C{"B", Floats(1.0005, 2.0, nil)},
+	)
+	ints, _ := New(
+		C{"A", Strings("a", "b", nil)},
+		C{"B", Ints(1, 2, nil)},
+	)
+	many, _ := New(C{"A", Ints(0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11)})
+	zeros, _ := New(C{"A", Ints(1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12)})
+
+	var tests = []struct {
+		got, want DataFrame
+		opts      EqualOptions
+		expected  string
+	}{
+		{*a, *a, EqualOptions{}, "[]"},
+		{*a, *a, EqualOptions{StrictNA: true}, "[row 2, column A: got NA, want NA row 2, column B: got NA, want NA]"},
+		{*reordered, *a, EqualOptions{}, "[columns: got [B A], want [A B]]"},
+		{*reordered, *a, EqualOptions{IgnoreColumnOrder: true}, "[row 0, column A: got NA, want a row 0, column B: got NA, want 1 row 1, column A: got a, want b row 1, column B: got 1, want 2 row 2, column A: got b, want NA row 2, column B: got 2, want NA]"},
+		{*reordered, *a, EqualOptions{IgnoreColumnOrder: true, IgnoreRowOrder: true}, "[]"},
+		{*near, *a, EqualOptions{}, "[row 0, column B: got 1.0005, want 1]"},
+		{*near, *a, EqualOptions{Tolerance: 0.001}, "[]"},
+		{*ints, *a, EqualOptions{}, "[column B: got type df.Int, want df.Float]"},
+		{*many, *a, EqualOptions{}, "[columns: got [A], want [A B]]"},
+		{*many, *zeros, EqualOptions{}, "[row 0, column A: got 0, want 1 row 1, column A: got 1, want 2 row 2, column A: got 2, want 3 row 3, column A: got 3, want 4 row 4, column A: got 4, want 5 row 5, column A: got 5, want 6 row 6, column A: got 6, want 7 row 7, column A: got 7, want 8 row 8, column A: got 8, want 9 row 9, column A: got 9, want 10 ... and 2 more differences]"},
+	}
+	for k, v := range tests {
+		received := fmt.Sprint(Differences(v.got, v.want, v.opts))
+		if v.expected != received || Equal(v.got, v.want, v.opts) != (v.expected == "[]") {
+			t.Error(
+				"Test", k, "\n",
+				"Expected:\n",
+				v.expected, "\n",
+				"Received:\n",
+				received,
+			)
+		}
+	}
+}
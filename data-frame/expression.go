@@ -0,0 +1,1004 @@
+package df
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// exprTokenKind identifies the kind of the tokens of an expression
+type exprTokenKind int
+
+const (
+	tokEOF exprTokenKind = iota
+	tokNumber
+	tokString
+	tokIdent
+	tokColumn
+	tokOp
+)
+
+// exprToken is a token of an expression and its position on it
+type exprToken struct {
+	kind exprTokenKind
+	text string
+	pos  int
+}
+
+// exprError returns a syntax error for the given position of the expression
+func exprError(pos int, format string, args ...interface{}) error {
+	return fmt.Errorf("expression error at position %d: %s", pos, fmt.Sprintf(format, args...))
+}
+
+// tokenizeExpr splits an expression into tokens. Column names that contain
+// characters other than letters, digits, '_' and '.' can be written between
+// backquotes.
+func tokenizeExpr(s string) ([]exprToken, error) {
+	toks := []exprToken{}
+	rs := []rune(s)
+	for i := 0; i < len(rs); {
+		r := rs[i]
+		pos := i + 1
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case unicode.IsDigit(r) || (r == '.' && i+1 < len(rs) && unicode.IsDigit(rs[i+1])):
+			j := i
+			for j < len(rs) && (unicode.IsDigit(rs[j]) || rs[j] == '.') {
+				j++
+			}
+			if j < len(rs) && (rs[j] == 'e' || rs[j] == 'E') {
+				k := j + 1
+				if k < len(rs) && (rs[k] == '+' || rs[k] == '-') {
+					k++
+				}
+				if k < len(rs) && unicode.IsDigit(rs[k]) {
+					for j = k; j < len(rs) && unicode.IsDigit(rs[j]); j++ {
+					}
+				}
+			}
+			toks = append(toks, exprToken{tokNumber, string(rs[i:j]), pos})
+			i = j
+		case r == '"' || r == '\'' || r == '`':
+			j := i + 1
+			var sb strings.Builder
+			for ; j < len(rs) && rs[j] != r; j++ {
+				if rs[j] == '\\' && j+1 < len(rs) {
+					j++
+				}
+				sb.WriteRune(rs[j])
+			}
+			if j >= len(rs) {
+				return nil, exprError(pos, "unterminated %c", r)
+			}
+			kind := tokString
+			if r == '`' {
+				kind = tokColumn
+			}
+			toks = append(toks, exprToken{kind, sb.String(), pos})
+			i = j + 1
+		case unicode.IsLetter(r) || r == '_':
+			j := i
+			for j < len(rs) && (unicode.IsLetter(rs[j]) || unicode.IsDigit(rs[j]) || rs[j] == '_' || rs[j] == '.') {
+				j++
+			}
+			toks = append(toks, exprToken{tokIdent, string(rs[i:j]), pos})
+			i = j
+		default:
+			op := ""
+			if i+1 < len(rs) {
+				switch two := string(rs[i : i+2]); two {
+				case "==", "!=", "<=", ">=", "&&", "||":
+					op = two
+				}
+			}
+			if op == "" {
+				if !strings.ContainsRune("+-*/%^()<>!,", r) {
+					return nil, exprError(pos, "unexpected character %q", r)
+				}
+				op = string(r)
+			}
+			toks = append(toks, exprToken{tokOp, op, pos})
+			i += len([]rune(op))
+		}
+	}
+	toks = append(toks, exprToken{tokEOF, "", len(rs) + 1})
+	return toks, nil
+}
+
+// exprNode is a node of a compiled expression. cellType returns the column
+// type of the values returned by eval, or an empty string if it can't be
+// known (NA literals).
+type exprNode interface {
+	eval(row int) (Cell, error)
+	cellType() string
+}
+
+// kindOfType returns the numeric kind of the given column type
+func kindOfType(t string) numKind {
+	switch t {
+	case "df.Int":
+		return kindInt
+	case "df.Int64":
+		return kindInt64
+	case "df.Uint64":
+		return kindUint64
+	case "df.Decimal":
+		return kindDecimal
+	case "df.Float":
+		return kindFloat
+	}
+	return kindNone
+}
+
+// typeOfKind returns the column type of the given numeric kind
+func typeOfKind(k numKind) string {
+	switch k {
+	case kindInt:
+		return "df.Int"
+	case kindInt64:
+		return "df.Int64"
+	case kindUint64:
+		return "df.Uint64"
+	case kindDecimal:
+		return "df.Decimal"
+	}
+	return "df.Float"
+}
+
+// emptyOfType returns the NA element of the given column type
+func emptyOfType(t string) Cell {
+	if ct, ok := typeOf(t); ok {
+		return ct.Empty.NA()
+	}
+	return Float{nil}
+}
+
+// unifyTypes returns the type that can hold the values of all the given
+// types. Unknown types are ignored and numeric types are promoted.
+func unifyTypes(types ...string) (string, error) {
+	ret := ""
+	for _, t := range types {
+		switch {
+		case t == "" || t == ret:
+		case ret == "":
+			ret = t
+		case kindOfType(t) != kindNone && kindOfType(ret) != kindNone:
+			ret = typeOfKind(promoteKinds(kindOfType(ret), kindOfType(t), Add, ArithOptions{}))
+		default:
+			return "", fmt.Errorf("incompatible types %s and %s", ret, t)
+		}
+	}
+	return ret, nil
+}
+
+// castCell converts a cell to the given column type. Only numeric types can
+// be converted between them.
+func castCell(c Cell, t string) (Cell, error) {
+	if t == "" {
+		return c, nil
+	}
+	if c.IsNA() {
+		return emptyOfType(t), nil
+	}
+	if kindOfType(t) == numericKind(c) {
+		return c, nil
+	}
+	switch kindOfType(t) {
+	case kindFloat:
+		f, err := c.Float()
+		if err != nil {
+			return nil, err
+		}
+		return Float{f}, nil
+	case kindDecimal:
+		return toDecimal(c)
+	case kindInt64:
+		i, err := cellInt64(c)
+		if err != nil {
+			return nil, err
+		}
+		return Int64{&i}, nil
+	case kindUint64:
+		return Uint64s(c)[0], nil
+	case kindInt:
+		i, err := c.Int()
+		if err != nil {
+			return nil, err
+		}
+		return Int{i}, nil
+	}
+	return c, nil
+}
+
+type constNode struct {
+	c Cell
+	t string
+}
+
+func (n constNode) eval(row int) (Cell, error) { return n.c, nil }
+func (n constNode) cellType() string           { return n.t }
+
+type columnNode struct {
+	col column
+}
+
+func (n columnNode) eval(row int) (Cell, error) { return n.col.cells[row], nil }
+func (n columnNode) cellType() string           { return n.col.colType }
+
+type arithNode struct {
+	op   Operator
+	a, b exprNode
+	t    string
+}
+
+func (n arithNode) eval(row int) (Cell, error) {
+	a, err := n.a.eval(row)
+	if err != nil {
+		return nil, err
+	}
+	b, err := n.b.eval(row)
+	if err != nil {
+		return nil, err
+	}
+	if a.IsNA() || b.IsNA() {
+		return emptyOfType(n.t), nil
+	}
+	return arith(a, b, n.op, ArithOptions{FloatDivision: true})
+}
+func (n arithNode) cellType() string { return n.t }
+
+type compareNode struct {
+	op      string
+	a, b    exprNode
+	literal *string
+	pos     int
+}
+
+func (n compareNode) eval(row int) (Cell, error) {
+	a, err := n.a.eval(row)
+	if err != nil {
+		return nil, err
+	}
+	if a.IsNA() {
+		return Bool{nil}, nil
+	}
+	if n.literal != nil && (n.op == "==" || n.op == "!=") {
+		eq, ok := equalLiteral(a, n.a.cellType(), *n.literal)
+		if !ok {
+			return nil, exprError(n.pos, "can't compare %s with %q", a, *n.literal)
+		}
+		r := eq == (n.op == "==")
+		return Bool{&r}, nil
+	}
+	var cmp int
+	if n.literal != nil {
+		var ok bool
+		if cmp, ok = compareLiteral(a, n.a.cellType(), *n.literal); !ok {
+			return nil, exprError(n.pos, "can't compare %s with %q", a, *n.literal)
+		}
+	} else {
+		b, err := n.b.eval(row)
+		if err != nil {
+			return nil, err
+		}
+		if b.IsNA() {
+			return Bool{nil}, nil
+		}
+		if cmp, err = compareValues(a, b, n.a.cellType(), n.b.cellType()); err != nil {
+			return nil, err
+		}
+	}
+	var r bool
+	switch n.op {
+	case "==":
+		r = cmp == 0
+	case "!=":
+		r = cmp != 0
+	case "<":
+		r = cmp < 0
+	case "<=":
+		r = cmp <= 0
+	case ">":
+		r = cmp > 0
+	case ">=":
+		r = cmp >= 0
+	}
+	return Bool{&r}, nil
+}
+func (n compareNode) cellType() string { return "df.Bool" }
+
+// compareValues compares two non NA cells of the given types. Numeric types
+// are compared by value even if their types are different.
+func compareValues(a, b Cell, ta, tb string) (int, error) {
+	if ta == tb {
+		return compareCells(a, b, ta)
+	}
+	ka, kb := numericKind(a), numericKind(b)
+	if ka == kindNone || kb == kindNone {
+		return 0, fmt.Errorf("Can't compare elements of type %s and %s", ta, tb)
+	}
+	if ka == kindFloat || kb == kindFloat {
+		fa, _ := a.Float()
+		fb, _ := b.Float()
+		return compareFloats(*fa, *fb), nil
+	}
+	da, err := toDecimal(a)
+	if err != nil {
+		return 0, err
+	}
+	db, err := toDecimal(b)
+	if err != nil {
+		return 0, err
+	}
+	return da.Cmp(db), nil
+}
+
+type logicNode struct {
+	op   string
+	a, b exprNode
+}
+
+func (n logicNode) eval(row int) (Cell, error) {
+	a, err := evalBool(n.a, row)
+	if err != nil {
+		return nil, err
+	}
+	b, err := evalBool(n.b, row)
+	if err != nil {
+		return nil, err
+	}
+	// Three valued logic: NA && false is false and NA || true is true
+	t, f := true, false
+	if n.op == "&&" {
+		switch {
+		case (a != nil && !*a) || (b != nil && !*b):
+			return Bool{&f}, nil
+		case a == nil || b == nil:
+			return Bool{nil}, nil
+		}
+		return Bool{&t}, nil
+	}
+	switch {
+	case (a != nil && *a) || (b != nil && *b):
+		return Bool{&t}, nil
+	case a == nil || b == nil:
+		return Bool{nil}, nil
+	}
+	return Bool{&f}, nil
+}
+func (n logicNode) cellType() string { return "df.Bool" }
+
+type notNode struct {
+	a exprNode
+}
+
+func (n notNode) eval(row int) (Cell, error) {
+	a, err := evalBool(n.a, row)
+	if err != nil || a == nil {
+		return Bool{nil}, err
+	}
+	r := !*a
+	return Bool{&r}, nil
+}
+func (n notNode) cellType() string { return "df.Bool" }
+
+// evalBool evaluates a boolean node returning nil for NA
+func evalBool(n exprNode, row int) (*bool, error) {
+	c, err := n.eval(row)
+	if err != nil || c.IsNA() {
+		return nil, err
+	}
+	return cellBool(c)
+}
+
+// cellBool returns the value of a non NA boolean cell
+func cellBool(c Cell) (*bool, error) {
+	if b, ok := c.(Bool); ok {
+		return b.b, nil
+	}
+	return c.Bool()
+}
+
+type funcNode struct {
+	name string
+	args []exprNode
+	t    string
+	f    func(args Cells) (Cell, error)
+}
+
+func (n funcNode) eval(row int) (Cell, error) {
+	args := make(Cells, 0, len(n.args))
+	for _, a := range n.args {
+		c, err := a.eval(row)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, c)
+	}
+	c, err := n.f(args)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", n.name, err)
+	}
+	return castCell(c, n.t)
+}
+func (n funcNode) cellType() string { return n.t }
+
+// exprParser compiles an expression against the columns of a DataFrame
+type exprParser struct {
+	df   DataFrame
+	toks []exprToken
+	pos  int
+	refs []string
+}
+
+func (p *exprParser) peek() exprToken {
+	return p.toks[p.pos]
+}
+
+func (p *exprParser) next() exprToken {
+	t := p.toks[p.pos]
+	if t.kind != tokEOF {
+		p.pos++
+	}
+	return t
+}
+
+// accept consumes the next token if it is one of the given operators or
+// keywords
+func (p *exprParser) accept(ops ...string) (exprToken, bool) {
+	t := p.peek()
+	if t.kind != tokOp && t.kind != tokIdent {
+		return t, false
+	}
+	for _, op := range ops {
+		if t.text == op {
+			p.pos++
+			return t, true
+		}
+	}
+	return t, false
+}
+
+func (p *exprParser) expect(op string) error {
+	if _, ok := p.accept(op); !ok {
+		t := p.peek()
+		if t.kind == tokEOF {
+			return exprError(t.pos, "expected %q but found end of expression", op)
+		}
+		return exprError(t.pos, "expected %q but found %q", op, t.text)
+	}
+	return nil
+}
+
+func (p *exprParser) parseOr() (exprNode, error) {
+	a, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		t, ok := p.accept("||", "or")
+		if !ok {
+			return a, nil
+		}
+		b, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		if err := checkBool(t.pos, a, b); err != nil {
+			return nil, err
+		}
+		a = logicNode{"||", a, b}
+	}
+}
+
+func (p *exprParser) parseAnd() (exprNode, error) {
+	a, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		t, ok := p.accept("&&", "and")
+		if !ok {
+			return a, nil
+		}
+		b, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		if err := checkBool(t.pos, a, b); err != nil {
+			return nil, err
+		}
+		a = logicNode{"&&", a, b}
+	}
+}
+
+func (p *exprParser) parseNot() (exprNode, error) {
+	if t, ok := p.accept("!", "not"); ok {
+		a, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		if err := checkBool(t.pos, a); err != nil {
+			return nil, err
+		}
+		return notNode{a}, nil
+	}
+	return p.parseComparison()
+}
+
+// checkBool verifies that the given nodes return boolean values
+func checkBool(pos int, nodes ...exprNode) error {
+	for _, n := range nodes {
+		if t := n.cellType(); t != "df.Bool" && t != "" {
+			return exprError(pos, "logical operation over non boolean type %s", t)
+		}
+	}
+	return nil
+}
+
+// literalComparison returns the comparison of a with the string literal s.
+// The literal must be a valid value of the type of a, unless said type
+// compares literals by itself as Categorical does with its levels.
+func literalComparison(op string, a exprNode, s string, pos int) (exprNode, error) {
+	if ct, ok := typeOf(a.cellType()); ok {
+		if _, ok := ct.Empty.(literalComparer); !ok {
+			if c, err := ct.Parse(s); err != nil || c == nil || c.IsNA() {
+				return nil, exprError(pos, "can't compare %s with %q", a.cellType(), s)
+			}
+		}
+	}
+	return compareNode{op: op, a: a, literal: &s, pos: pos}, nil
+}
+
+func (p *exprParser) parseComparison() (exprNode, error) {
+	a, err := p.parseAdditive()
+	if err != nil {
+		return nil, err
+	}
+	t, ok := p.accept("==", "!=", "<", "<=", ">", ">=")
+	if !ok {
+		return a, nil
+	}
+	b, err := p.parseAdditive()
+	if err != nil {
+		return nil, err
+	}
+	op := t.text
+
+	// String literals are parsed with the type of the other element
+	if c, ok := b.(constNode); ok && c.t == "df.String" && a.cellType() != "df.String" {
+		return literalComparison(op, a, c.c.String(), t.pos)
+	}
+	if c, ok := a.(constNode); ok && c.t == "df.String" && b.cellType() != "df.String" {
+		flipped := map[string]string{"<": ">", "<=": ">=", ">": "<", ">=": "<="}
+		if f, ok := flipped[op]; ok {
+			op = f
+		}
+		return literalComparison(op, b, c.c.String(), t.pos)
+	}
+
+	ta, tb := a.cellType(), b.cellType()
+	if ta != "" && tb != "" && ta != tb && (kindOfType(ta) == kindNone || kindOfType(tb) == kindNone) {
+		return nil, exprError(t.pos, "can't compare types %s and %s", ta, tb)
+	}
+	return compareNode{op: op, a: a, b: b, pos: t.pos}, nil
+}
+
+func (p *exprParser) parseAdditive() (exprNode, error) {
+	a, err := p.parseMultiplicative()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		t, ok := p.accept("+", "-")
+		if !ok {
+			return a, nil
+		}
+		b, err := p.parseMultiplicative()
+		if err != nil {
+			return nil, err
+		}
+		op := Add
+		if t.text == "-" {
+			op = Sub
+		}
+		if a, err = newArithNode(t.pos, op, a, b); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (p *exprParser) parseMultiplicative() (exprNode, error) {
+	a, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		t, ok := p.accept("*", "/", "%")
+		if !ok {
+			return a, nil
+		}
+		b, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		op := map[string]Operator{"*": Mul, "/": Div, "%": Mod}[t.text]
+		if a, err = newArithNode(t.pos, op, a, b); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (p *exprParser) parseUnary() (exprNode, error) {
+	if t, ok := p.accept("-"); ok {
+		a, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		zero := 0
+		return newArithNode(t.pos, Sub, constNode{Int{&zero}, "df.Int"}, a)
+	}
+	return p.parsePower()
+}
+
+func (p *exprParser) parsePower() (exprNode, error) {
+	a, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	if t, ok := p.accept("^"); ok {
+		b, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return newArithNode(t.pos, Pow, a, b)
+	}
+	return a, nil
+}
+
+// newArithNode checks that the operands are numeric and infers the type of
+// the result
+func newArithNode(pos int, op Operator, a, b exprNode) (exprNode, error) {
+	ta, tb := a.cellType(), b.cellType()
+	ka, kb := kindOfType(ta), kindOfType(tb)
+	if (ta != "" && ka == kindNone) || (tb != "" && kb == kindNone) {
+		return nil, exprError(pos, "arithmetic operation %s over non numeric types %s and %s", op, ta, tb)
+	}
+	switch {
+	case ta == "" && tb == "":
+		return arithNode{op, a, b, ""}, nil
+	case ta == "":
+		ka = kb
+	case tb == "":
+		kb = ka
+	}
+	t := typeOfKind(promoteKinds(ka, kb, op, ArithOptions{FloatDivision: true}))
+	return arithNode{op, a, b, t}, nil
+}
+
+func (p *exprParser) parsePrimary() (exprNode, error) {
+	t := p.next()
+	switch t.kind {
+	case tokNumber:
+		if i, err := strconv.Atoi(t.text); err == nil {
+			return constNode{Int{&i}, "df.Int"}, nil
+		}
+		f, err := strconv.ParseFloat(t.text, 64)
+		if err != nil {
+			return nil, exprError(t.pos, "invalid number %q", t.text)
+		}
+		return constNode{Float{&f}, "df.Float"}, nil
+	case tokString:
+		s := t.text
+		return constNode{String{&s}, "df.String"}, nil
+	case tokColumn:
+		return p.columnRef(t)
+	case tokIdent:
+		switch t.text {
+		case "true", "false":
+			b := t.text == "true"
+			return constNode{Bool{&b}, "df.Bool"}, nil
+		case "NA":
+			return constNode{Float{nil}, ""}, nil
+		}
+		if next := p.peek(); next.kind == tokOp && next.text == "(" {
+			return p.parseCall(t)
+		}
+		return p.columnRef(t)
+	case tokOp:
+		if t.text == "(" {
+			n, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect(")"); err != nil {
+				return nil, err
+			}
+			return n, nil
+		}
+		return nil, exprError(t.pos, "unexpected %q", t.text)
+	}
+	return nil, exprError(t.pos, "unexpected end of expression")
+}
+
+func (p *exprParser) columnRef(t exprToken) (exprNode, error) {
+	col, err := p.df.col(t.text)
+	if err != nil {
+		return nil, exprError(t.pos, "%v", err)
+	}
+	if !inStringSlice(t.text, p.refs) {
+		p.refs = append(p.refs, t.text)
+	}
+	return columnNode{col}, nil
+}
+
+func (p *exprParser) parseCall(name exprToken) (exprNode, error) {
+	p.next()
+	args := []exprNode{}
+	if _, ok := p.accept(")"); !ok {
+		for {
+			a, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			args = append(args, a)
+			if _, ok := p.accept(","); ok {
+				continue
+			}
+			if err := p.expect(")"); err != nil {
+				return nil, err
+			}
+			break
+		}
+	}
+	builtin, ok := exprFunctions[name.text]
+	if !ok {
+		return nil, exprError(name.pos, "unknown function %s", name.text)
+	}
+	n, err := builtin(args)
+	if err != nil {
+		return nil, exprError(name.pos, "%s: %v", name.text, err)
+	}
+	n.name = name.text
+	n.args = args
+	return n, nil
+}
+
+// exprFunctions are the builtin functions available on expressions. Each one
+// checks its arguments and returns a node with the inferred type.
+var exprFunctions = map[string]func(args []exprNode) (funcNode, error){
+	"abs": func(args []exprNode) (funcNode, error) {
+		if err := checkNumericArgs(args, 1, 1); err != nil {
+			return funcNode{}, err
+		}
+		return funcNode{t: args[0].cellType(), f: absCell}, nil
+	},
+	"round": func(args []exprNode) (funcNode, error) {
+		if err := checkNumericArgs(args, 1, 2); err != nil {
+			return funcNode{}, err
+		}
+		digits := 0
+		if len(args) == 2 {
+			c, ok := args[1].(constNode)
+			if !ok || c.t != "df.Int" {
+				return funcNode{}, errors.New("the number of digits must be an integer literal")
+			}
+			digits = *c.c.(Int).i
+		}
+		return funcNode{t: args[0].cellType(), f: func(args Cells) (Cell, error) {
+			return roundCell(args[0], digits)
+		}}, nil
+	},
+	"log": func(args []exprNode) (funcNode, error) {
+		if err := checkNumericArgs(args, 1, 1); err != nil {
+			return funcNode{}, err
+		}
+		return funcNode{t: "df.Float", f: func(args Cells) (Cell, error) {
+			f, err := args[0].Float()
+			if err != nil || *f <= 0 {
+				return Float{nil}, nil
+			}
+			r := math.Log(*f)
+			return Float{&r}, nil
+		}}, nil
+	},
+	"coalesce": func(args []exprNode) (funcNode, error) {
+		if len(args) == 0 {
+			return funcNode{}, errors.New("expected at least one argument")
+		}
+		types := []string{}
+		for _, a := range args {
+			types = append(types, a.cellType())
+		}
+		t, err := unifyTypes(types...)
+		if err != nil {
+			return funcNode{}, err
+		}
+		return funcNode{t: t, f: func(args Cells) (Cell, error) {
+			for _, a := range args {
+				if !a.IsNA() {
+					return a, nil
+				}
+			}
+			return emptyOfType(t), nil
+		}}, nil
+	},
+	"isna": func(args []exprNode) (funcNode, error) {
+		if len(args) != 1 {
+			return funcNode{}, fmt.Errorf("expected 1 argument but received %d", len(args))
+		}
+		return funcNode{t: "df.Bool", f: func(args Cells) (Cell, error) {
+			b := args[0].IsNA()
+			return Bool{&b}, nil
+		}}, nil
+	},
+	"if": func(args []exprNode) (funcNode, error) {
+		if len(args) != 3 {
+			return funcNode{}, fmt.Errorf("expected 3 arguments but received %d", len(args))
+		}
+		if err := checkBool(0, args[0]); err != nil {
+			return funcNode{}, errors.New("the condition must be boolean")
+		}
+		t, err := unifyTypes(args[1].cellType(), args[2].cellType())
+		if err != nil {
+			return funcNode{}, err
+		}
+		return funcNode{t: t, f: func(args Cells) (Cell, error) {
+			if args[0].IsNA() {
+				return emptyOfType(t), nil
+			}
+			b, err := cellBool(args[0])
+			if err != nil {
+				return nil, err
+			}
+			if *b {
+				return args[1], nil
+			}
+			return args[2], nil
+		}}, nil
+	},
+}
+
+// checkNumericArgs verifies the number of arguments of a function and that
+// all of them are numeric
+func checkNumericArgs(args []exprNode, min, max int) error {
+	if len(args) < min || len(args) > max {
+		if min == max {
+			return fmt.Errorf("expected %d arguments but received %d", min, len(args))
+		}
+		return fmt.Errorf("expected %d to %d arguments but received %d", min, max, len(args))
+	}
+	for _, a := range args {
+		if t := a.cellType(); t != "" && kindOfType(t) == kindNone {
+			return fmt.Errorf("non numeric argument of type %s", t)
+		}
+	}
+	return nil
+}
+
+func absCell(args Cells) (Cell, error) {
+	c := args[0]
+	if c.IsNA() {
+		return c, nil
+	}
+	switch e := c.(type) {
+	case Int:
+		i := *e.i
+		if i < 0 {
+			i = -i
+		}
+		return Int{&i}, nil
+	case Int64:
+		if *e.i == math.MinInt64 {
+			return nil, errors.New("Int64 overflow")
+		}
+		i := *e.i
+		if i < 0 {
+			i = -i
+		}
+		return Int64{&i}, nil
+	case Decimal:
+		return Decimal{new(big.Int).Abs(e.v), e.scale}, nil
+	case Float:
+		f := math.Abs(*e.f)
+		return Float{&f}, nil
+	}
+	return c, nil
+}
+
+func roundCell(c Cell, digits int) (Cell, error) {
+	if c.IsNA() {
+		return c, nil
+	}
+	switch e := c.(type) {
+	case Decimal:
+		if digits < 0 {
+			digits = 0
+		}
+		return e.Rescale(digits), nil
+	case Float:
+		p := math.Pow(10, float64(digits))
+		f := math.Round(*e.f*p) / p
+		return Float{&f}, nil
+	}
+	return c, nil
+}
+
+// compileExpr parses an expression over the columns of the DataFrame
+func (df DataFrame) compileExpr(expr string) (exprNode, error) {
+	n, _, err := df.compileExprRefs(expr)
+	return n, err
+}
+
+// compileExprRefs parses an expression over the columns of the DataFrame and
+// returns the names of the columns it refers to, in order of appearance
+func (df DataFrame) compileExprRefs(expr string) (exprNode, []string, error) {
+	toks, err := tokenizeExpr(expr)
+	if err != nil {
+		return nil, nil, err
+	}
+	p := &exprParser{df: df, toks: toks}
+	n, err := p.parseOr()
+	if err != nil {
+		return nil, nil, err
+	}
+	if t := p.peek(); t.kind != tokEOF {
+		return nil, nil, exprError(t.pos, "unexpected %q", t.text)
+	}
+	if n.cellType() == "" {
+		return nil, nil, errors.New("Can't infer the type of the expression: " + expr)
+	}
+	return n, p.refs, nil
+}
+
+// evalExpr evaluates an expression over all the rows of the DataFrame and
+// returns the results and their type
+func (df DataFrame) evalExpr(expr string) (Cells, string, error) {
+	n, err := df.compileExpr(expr)
+	if err != nil {
+		return nil, "", err
+	}
+	cells := make(Cells, 0, df.nRows)
+	for i := 0; i < df.nRows; i++ {
+		c, err := n.eval(i)
+		if err != nil {
+			return nil, "", fmt.Errorf("row %d: %v", i, err)
+		}
+		if c, err = castCell(c, n.cellType()); err != nil {
+			return nil, "", fmt.Errorf("row %d: %v", i, err)
+		}
+		cells = append(cells, c)
+	}
+	return cells, n.cellType(), nil
+}
+
+// Mutate returns a new DataFrame where the column colname contains the result
+// of evaluating the expression expr on each row. If the column already exists
+// it will be replaced. The type of the column is inferred from the expression.
+//
+// Expressions can contain column names, numeric, string and boolean literals,
+// NA, arithmetic (+ - * / % ^), comparisons (== != < <= > >=), logical
+// operators (&& || ! or and, or, not) and the functions abs(x), round(x, [digits]),
+// log(x), coalesce(x, ...), isna(x) and if(cond, a, b). Division always returns
+// decimals or floats. Column names that are not valid identifiers can be
+// written between backquotes:
+//
+//	d.Mutate("price_per_unit", "Amount / Qty")
+//	d.Mutate("is_adult", "Age >= 18")
+//	d.Mutate("total", "coalesce(`Net amount`, 0) * 1.21")
+func (df DataFrame) Mutate(colname, expr string) (*DataFrame, error) {
+	cells, t, err := df.evalExpr(expr)
+	if err != nil {
+		return nil, err
+	}
+	if len(cells) == 0 {
+		// Without elements the type of the column comes from the expression
+		return df.withEmptyColumn(colname, emptyOfType(t))
+	}
+	return df.withColumn(colname, cells)
+}
@@ -0,0 +1,168 @@
+package df
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestDataFrame_Mutate(t *testing.T) {
+	d, _ := New(
+		C{"Amount", Floats(10, 7.5, nil, 3)},
+		C{"Qty", Ints(4, 3, 2, 0)},
+		C{"Age", Ints(17, 18, nil, 40)},
+		C{"Net amount", Decimals(2, "1.10", nil, "3.00", "-0.10")},
+		C{"Country", Strings("ES", "FR", "ES", nil)},
+	)
+	var tests = []struct {
+		expr     string
+		expected string
+		colType  string
+	}{
+		{"Amount / Qty", "[2.5 2.5 NA NA]", "df.Float"},
+		{"Qty * 2 + 1", "[9 7 5 1]", "df.Int"},
+		{"Qty / 2", "[2 1.5 1 0]", "df.Float"},
+		{"-Qty ^ 2", "[-16 -9 -4 0]", "df.Float"},
+		{"(Qty + 1) % 3", "[2 1 0 1]", "df.Int"},
+		{"Age >= 18", "[false true NA true]", "df.Bool"},
+		{"Age >= 18 && Country == \"ES\"", "[false false NA NA]", "df.Bool"},
+		{"Age < 18 or Qty == 2", "[true false true false]", "df.Bool"},
+		{"not (Qty > 2)", "[false false true true]", "df.Bool"},
+		{"coalesce(`Net amount`, 0) * 2", "[2.20 0 6.00 -0.20]", "df.Decimal"},
+		{"abs(`Net amount`)", "[1.10 NA 3.00 0.10]", "df.Decimal"},
+		{"round(Amount / 4, 1)", "[2.5 1.9 NA 0.8]", "df.Float"},
+		{"round(`Net amount`, 0)", "[1 NA 3 0]", "df.Decimal"},
+		{"log(Qty)", "[1.3862943611198906 1.0986122886681096 0.6931471805599453 NA]", "df.Float"},
+		{"if(Age >= 18, \"adult\", \"minor\")", "[minor adult NA adult]", "df.String"},
+		{"if(Qty > 2, Amount, NA)", "[10 7.5 NA NA]", "df.Float"},
+		{"if(Qty > 2, Qty, Amount)", "[4 3 NA 3]", "df.Float"},
+		{"coalesce(Country, 'unknown')", "[ES FR ES unknown]", "df.String"},
+		{"Amount > Qty", "[true true NA true]", "df.Bool"},
+		{"1.5e1", "[15 15 15 15]", "df.Float"},
+		{"isna(Amount) || isna(Country)", "[false false true true]", "df.Bool"},
+	}
+	for k, v := range tests {
+		dd, err := d.Mutate("R", v.expr)
+		if err != nil {
+			t.Error("Test", k, ":", err)
+			continue
+		}
+		col := dd.Columns["R"]
+		received := fmt.Sprint(col.cells)
+		if v.expected != received || v.colType != col.colType {
+			t.Error(
+				"Test", k, v.expr, "\n",
+				"Expected:\n",
+				v.expected, v.colType, "\n",
+				"Received:\n",
+				received, col.colType,
+			)
+		}
+	}
+
+	// Existing columns are replaced keeping their position
+	dd, err := d.Mutate("Qty", "Qty + 1")
+	if err != nil {
+		t.Error(err)
+	}
+	if fmt.Sprint(dd.Columns["Qty"].cells) != "[5 4 3 1]" || fmt.Sprint(dd.Names()) != fmt.Sprint(d.Names()) {
+		t.Error("Column not properly replaced:", dd.Columns["Qty"].cells, dd.Names())
+	}
+	if fmt.Sprint(d.Columns["Qty"].cells) != "[4 3 2 0]" {
+		t.Error("Original DataFrame was modified:", d.Columns["Qty"].cells)
+	}
+
+	// Without rows the type of the column comes from the expression
+	empty := d.Empty()
+	dd, err = empty.Mutate("R", "Qty / 2")
+	if err != nil {
+		t.Error(err)
+	} else if dd.NRows() != 0 || dd.Columns["R"].colType != "df.Float" {
+		t.Error("Expected an empty df.Float column, received", dd.NRows(), "rows of type", dd.Columns["R"].colType)
+	}
+	if _, err := empty.Mutate("R", "Qty == 'abc'"); err == nil {
+		t.Error("Literals of the wrong type should throw an error without rows")
+	}
+
+	var errors = []string{
+		"Unknown + 1",
+		"Country + 1",
+		"Age >= ",
+		"abs(Qty",
+		"abs(Qty, 1)",
+		"foo(Qty)",
+		"Qty && true",
+		"if(Qty, 1, 2)",
+		"if(Age > 1, Country, 1)",
+		"NA",
+		"Qty # 2",
+		"'open",
+		"Qty 2",
+		"isna(Qty, Age)",
+		"Qty == 'abc'",
+		"'abc' < Amount",
+	}
+	for _, expr := range errors {
+		if _, err := d.Mutate("R", expr); err == nil {
+			t.Error("Expression should throw an error:", expr)
+		}
+	}
+}
+
+func TestDataFrame_MutateCategorical(t *testing.T) {
+	size, _ := CategoricalsWithLevels([]string{"S", "M", "L"}, true, "M", "S", nil, "L")
+	d, _ := New(
+		C{"Color", Categoricals("b", "a", "b", nil)},
+		C{"Size", size},
+	)
+	var tests = []struct {
+		expr     string
+		expected string
+	}{
+		{"Color == \"b\"", "[true false true NA]"},
+		{"Color != \"b\"", "[false true false NA]"},
+		{"Color != \"zz\"", "[true true true NA]"},
+		{"Color == 'a' || Size == 'L'", "[false true NA true]"},
+		{"Size > \"S\"", "[true false NA true]"},
+		{"Size != \"XL\"", "[true true NA true]"},
+	}
+	for k, v := range tests {
+		dd, err := d.Mutate("R", v.expr)
+		if err != nil {
+			t.Error("Test", k, ":", err)
+			continue
+		}
+		received := fmt.Sprint(dd.Columns["R"].cells)
+		if v.expected != received {
+			t.Error(
+				"Test", k, v.expr, "\n",
+				"Expected:\n",
+				v.expected, "\n",
+				"Received:\n",
+				received,
+			)
+		}
+	}
+
+	dl, err := d.Lazy().Filter("Color == \"b\"").Collect()
+	if err != nil {
+		t.Error(err)
+	} else if fmt.Sprint(dl.Columns["Size"].cells) != "[M NA]" {
+		t.Error("Expected [M NA], received", dl.Columns["Size"].cells)
+	}
+
+	// Unordered levels have no order, and ordered ones only know their levels
+	for _, expr := range []string{"Color < \"b\"", "Size > \"XL\""} {
+		if _, err := d.Mutate("R", expr); err == nil {
+			t.Error("Expression should throw an error:", expr)
+		}
+	}
+}
+
+func TestTokenizeExpr_ErrorPosition(t *testing.T) {
+	d, _ := New(C{"A", Ints(1)})
+	_, err := d.Mutate("R", "A + * 2")
+	expected := "expression error at position 5: unexpected \"*\""
+	if err == nil || err.Error() != expected {
+		t.Error("Expected:", expected, "Received:", err)
+	}
+}
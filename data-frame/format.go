@@ -0,0 +1,226 @@
+package df
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// FormatOptions modifies how Pretty prints a DataFrame. The zero value prints
+// every row and column at full width, as String does.
+type FormatOptions struct {
+	// MaxRows is the maximum number of rows printed. Larger DataFrames show
+	// their first and last rows separated by an ellipsis.
+	MaxRows int
+	// MaxColumns is the maximum number of columns printed. Wider DataFrames
+	// show their first and last columns separated by an ellipsis.
+	MaxColumns int
+	// MaxWidth is the maximum number of characters of a name or element.
+	// Longer ones are cut and end with an ellipsis, if it fits.
+	MaxWidth int
+	// AlignNumbers aligns the numeric columns to the right.
+	AlignNumbers bool
+	// Precision is the number of decimals of the Float elements, if greater
+	// than zero.
+	Precision int
+	// ShowTypes adds a row with the type of each column under the header.
+	ShowTypes bool
+	// NA is the text shown for the missing elements, "NA" by default.
+	NA string
+}
+
+const ellipsis = "..."
+
+// String implements the Stringer interface for DataFrame, printing every row
+// and column.
+func (df DataFrame) String() string {
+	return df.Pretty()
+}
+
+// Pretty returns the DataFrame as a table, with the rows, columns and widths
+// limited by the given options. Newlines and tabs inside the elements are
+// escaped so that every row takes a single line.
+func (df DataFrame) Pretty(opts ...FormatOptions) string {
+	var o FormatOptions
+	if len(opts) > 0 {
+		o = opts[0]
+	}
+	t := df.layout(o)
+
+	widths := make([]int, len(t.cols))
+	for j, col := range t.cols {
+		widths[j] = utf8.RuneCountInString(col.name)
+		if o.ShowTypes {
+			widths[j] = maxInt(widths[j], utf8.RuneCountInString(col.typ)+2)
+		}
+		if t.rowGap >= 0 {
+			widths[j] = maxInt(widths[j], len(ellipsis))
+		}
+		for _, c := range col.cells {
+			widths[j] = maxInt(widths[j], utf8.RuneCountInString(c))
+		}
+	}
+
+	var b strings.Builder
+	indexWidth := len(strconv.Itoa(df.nRows)) + 2
+	if t.rowGap >= 0 {
+		indexWidth = maxInt(indexWidth, len(ellipsis)+1)
+	}
+	line := func(index string, cell func(col printedColumn) string) {
+		pad(&b, index, indexWidth, true)
+		for j, col := range t.cols {
+			if j == t.colGap {
+				pad(&b, ellipsis, len(ellipsis), false)
+				b.WriteString("  ")
+			}
+			pad(&b, cell(col), widths[j], col.right)
+			b.WriteString("  ")
+		}
+		if t.colGap == len(t.cols) {
+			b.WriteString(ellipsis + "  ")
+		}
+		b.WriteString("\n")
+	}
+	if len(t.cols) != 0 {
+		line("  ", func(col printedColumn) string { return col.name })
+		if o.ShowTypes {
+			line("  ", func(col printedColumn) string { return "<" + col.typ + ">" })
+		}
+		b.WriteString("\n")
+	}
+	for i, r := range t.rows {
+		if i == t.rowGap {
+			line(ellipsis+" ", func(printedColumn) string { return ellipsis })
+		}
+		line(strconv.Itoa(r)+": ", func(col printedColumn) string { return col.cells[i] })
+	}
+	if t.rowGap == len(t.rows) {
+		line(ellipsis+" ", func(printedColumn) string { return ellipsis })
+	}
+	if t.truncated() {
+		b.WriteString(t.dims() + "\n")
+	}
+	return b.String()
+}
+
+// printedTable is the part of a DataFrame selected to be printed by the given
+// FormatOptions, shared by Pretty and the Markdown, HTML and LaTeX writers.
+// rowGap and colGap are the positions of the ellipsis on the printed rows and
+// columns, which can be after the last one, or -1 if nothing was left out.
+type printedTable struct {
+	rows           []int
+	cols           []printedColumn
+	rowGap, colGap int
+	nRows, nCols   int
+}
+
+// printedColumn holds the name, type and elements of a printed column as they
+// are shown. Numeric columns are right aligned if AlignNumbers is set.
+type printedColumn struct {
+	name, typ string
+	cells     []string
+	right     bool
+}
+
+// layout selects the rows and columns to print and formats their elements
+func (df DataFrame) layout(o FormatOptions) printedTable {
+	if o.NA == "" {
+		o.NA = "NA"
+	}
+	t := printedTable{rowGap: -1, colGap: -1, nRows: df.nRows, nCols: df.NCols()}
+
+	names := df.Names()
+	if o.MaxColumns > 0 && len(names) > o.MaxColumns {
+		t.colGap = (o.MaxColumns + 1) / 2
+		names = append(names[:t.colGap:t.colGap], names[len(names)-o.MaxColumns/2:]...)
+	}
+	t.rows = make([]int, 0, df.nRows)
+	if o.MaxRows > 0 && df.nRows > o.MaxRows {
+		t.rowGap = (o.MaxRows + 1) / 2
+		for i := 0; i < t.rowGap; i++ {
+			t.rows = append(t.rows, i)
+		}
+		for i := df.nRows - o.MaxRows/2; i < df.nRows; i++ {
+			t.rows = append(t.rows, i)
+		}
+	} else {
+		for i := 0; i < df.nRows; i++ {
+			t.rows = append(t.rows, i)
+		}
+	}
+
+	t.cols = make([]printedColumn, len(names))
+	for j, k := range names {
+		col := df.Columns[k]
+		typ := col.colType
+		if ct, ok := typeOf(typ); ok {
+			typ = ct.Name
+		}
+		p := printedColumn{
+			name:  o.cut(escape(k)),
+			typ:   typ,
+			cells: make([]string, len(t.rows)),
+			right: o.AlignNumbers && numericKind(col.empty) != kindNone,
+		}
+		for i, r := range t.rows {
+			p.cells[i] = o.cut(escape(o.format(col.cells[r], col.colType)))
+		}
+		t.cols[j] = p
+	}
+	return t
+}
+
+// truncated reports whether some rows or columns were left out
+func (t printedTable) truncated() bool {
+	return t.rowGap >= 0 || t.colGap >= 0
+}
+
+// dims returns the dimensions of the whole DataFrame
+func (t printedTable) dims() string {
+	return fmt.Sprintf("[%d rows x %d columns]", t.nRows, t.nCols)
+}
+
+// format returns the text of an element of a column of the given type
+func (o FormatOptions) format(cell Cell, colType string) string {
+	if cell.IsNA() {
+		return o.NA
+	}
+	if f, ok := cell.(Float); ok && o.Precision > 0 {
+		return strconv.FormatFloat(*f.f, 'f', o.Precision, 64)
+	}
+	return formatCellType(cell, colType)
+}
+
+// cut shortens s to MaxWidth characters, ending it with an ellipsis unless
+// MaxWidth is too small to hold it
+func (o FormatOptions) cut(s string) string {
+	if o.MaxWidth <= 0 || utf8.RuneCountInString(s) <= o.MaxWidth {
+		return s
+	}
+	r := []rune(s)
+	if o.MaxWidth <= len(ellipsis) {
+		return string(r[:o.MaxWidth])
+	}
+	return string(r[:o.MaxWidth-len(ellipsis)]) + ellipsis
+}
+
+var escaper = strings.NewReplacer("\n", `\n`, "\r", `\r`, "\t", `\t`)
+
+// escape replaces the characters that would break the lines of a table
+func escape(s string) string {
+	return escaper.Replace(s)
+}
+
+// pad writes s filled with spaces up to the given number of characters, on
+// the left if right aligned or otherwise on the right
+func pad(b *strings.Builder, s string, width int, right bool) {
+	n := width - utf8.RuneCountInString(s)
+	if right && n > 0 {
+		b.WriteString(strings.Repeat(" ", n))
+	}
+	b.WriteString(s)
+	if !right && n > 0 {
+		b.WriteString(strings.Repeat(" ", n))
+	}
+}
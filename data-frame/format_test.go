@@ -0,0 +1,141 @@
+package df
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDataFrame_Pretty(t *testing.T) {
+	a, err := New(
+		C{"Name", Strings("Alice", "Bob\nSmith", "Carol", nil, "Eve")},
+		C{"Age", Ints(32, 5, nil, 41, 7)},
+		C{"Amount", Floats(10.5, 1234.567, 3, nil, 0.25)},
+		C{"City", Strings("Oslo", "Rome", "Lisbon", "Oslo", "Paris")},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	table := []struct {
+		opts     []FormatOptions
+		expected []string
+	}{
+		{
+			nil,
+			[]string{
+				"   Name        Age  Amount    City    ",
+				"",
+				"0: Alice       32   10.5      Oslo    ",
+				`1: Bob\nSmith  5    1234.567  Rome    `,
+				"2: Carol       NA   3         Lisbon  ",
+				"3: NA          41   NA        Oslo    ",
+				"4: Eve         7    0.25      Paris   ",
+			},
+		},
+		{
+			[]FormatOptions{{MaxRows: 3, MaxColumns: 3}},
+			[]string{
+				"    Name        Age  ...  City   ",
+				"",
+				" 0: Alice       32   ...  Oslo   ",
+				` 1: Bob\nSmith  5    ...  Rome   `,
+				"... ...         ...  ...  ...    ",
+				" 4: Eve         7    ...  Paris  ",
+				"[5 rows x 4 columns]",
+			},
+		},
+		{
+			[]FormatOptions{{MaxWidth: 7, AlignNumbers: true, Precision: 2, NA: "-"}},
+			[]string{
+				"   Name     Age   Amount  City    ",
+				"",
+				"0: Alice     32    10.50  Oslo    ",
+				`1: Bob\...    5  1234.57  Rome    `,
+				"2: Carol      -     3.00  Lisbon  ",
+				"3: -         41        -  Oslo    ",
+				"4: Eve        7     0.25  Paris   ",
+			},
+		},
+		{
+			[]FormatOptions{{MaxRows: 2, ShowTypes: true, AlignNumbers: true}},
+			[]string{
+				"    Name        Age   Amount  City      ",
+				"    <string>  <int>  <float>  <string>  ",
+				"",
+				" 0: Alice        32     10.5  Oslo      ",
+				"... ...         ...      ...  ...       ",
+				" 4: Eve           7     0.25  Paris     ",
+				"[5 rows x 4 columns]",
+			},
+		},
+		{
+			[]FormatOptions{{MaxRows: 1, MaxColumns: 1}},
+			[]string{
+				"    Name   ...  ",
+				"",
+				" 0: Alice  ...  ",
+				"... ...    ...  ",
+				"[5 rows x 4 columns]",
+			},
+		},
+	}
+	for k, v := range table {
+		expected := strings.Join(v.expected, "\n") + "\n"
+		received := a.Pretty(v.opts...)
+		if expected != received {
+			t.Error(
+				"Test", k, "\n",
+				"Expected:\n",
+				expected, "\n",
+				"Received:\n",
+				received,
+			)
+		}
+	}
+}
+
+func TestDataFrame_PrettyAlignEmpty(t *testing.T) {
+	a, err := New(
+		C{"Name", Strings("Alice")},
+		C{"Amount", Floats(10.5)},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	table := []struct {
+		df       *DataFrame
+		opts     FormatOptions
+		expected []string
+	}{
+		{
+			a.Empty(),
+			FormatOptions{AlignNumbers: true, ShowTypes: true},
+			[]string{
+				"   Name       Amount  ",
+				"   <string>  <float>  ",
+				"",
+			},
+		},
+		{
+			a,
+			FormatOptions{MaxWidth: 2},
+			[]string{
+				"   Na  Am  ",
+				"",
+				"0: Al  10  ",
+			},
+		},
+	}
+	for k, v := range table {
+		expected := strings.Join(v.expected, "\n") + "\n"
+		received := v.df.Pretty(v.opts)
+		if expected != received {
+			t.Error(
+				"Test", k, "\n",
+				"Expected:\n",
+				expected, "\n",
+				"Received:\n",
+				received,
+			)
+		}
+	}
+}
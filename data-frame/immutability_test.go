@@ -0,0 +1,168 @@
+package df
+
+import (
+	"fmt"
+	"testing"
+)
+
+// snapshot returns a description of the whole state of a DataFrame, including
+// the values of its elements and its column indexes
+func snapshot(df DataFrame) string {
+	types := []string{}
+	for _, k := range df.Names() {
+		types = append(types, df.Columns[k].colType)
+	}
+	return fmt.Sprint(df.SaveRecords(), df.colIndexs, types, df.nRows)
+}
+
+func TestDataFrame_Immutability(t *testing.T) {
+	a, _ := New(
+		C{"A", Strings("a", "b", "a", nil)},
+		C{"B", Ints(1, 2, 1, 4)},
+		C{"C", Floats(1.5, nil, 1.5, 4.0)},
+		C{"T", Times("2016-01-01", "2016-01-02", "2016-01-01", "2016-01-04")},
+		C{"K", Strings("x", "y", "x", "z")},
+	)
+	if err := a.Parse(T{"K": "category"}); err != nil {
+		t.Fatal(err)
+	}
+	b, _ := New(
+		C{"A", Strings("c", "a")},
+		C{"B", Ints(3, 1)},
+		C{"C", Floats(3.0, 1.5)},
+		C{"T", Times("2016-01-03", "2016-01-01")},
+		C{"K", Strings("x", "x")},
+	)
+	b.Parse(T{"K": "category"})
+	c, _ := New(
+		C{"D", Ints(10, 20, 30, 40)},
+	)
+	before := []string{snapshot(*a), snapshot(*b), snapshot(*c)}
+
+	var ops = map[string]func() (interface{}, error){
+		"Subset":           func() (interface{}, error) { return a.Subset([]string{"A", "B"}, R{1, 3}) },
+		"SubsetColumns":    func() (interface{}, error) { return a.SubsetColumns([]int{0, 2}) },
+		"SubsetRows":       func() (interface{}, error) { return a.SubsetRows([]int{3, -1, 0}) },
+		"SubsetRowsAll":    func() (interface{}, error) { return a.SubsetRows(R{0, 4}) },
+		"DropColumn":       func() (interface{}, error) { return a.DropColumn("B") },
+		"FilterRows":       func() (interface{}, error) { return a.FilterRows("A", func(c Cell) bool { return !c.IsNA() }) },
+		"ConditionRows":    func() (interface{}, error) { return a.ConditionRows(NewCondition([]string{"B > 1"})) },
+		"Rbind":            func() (interface{}, error) { return Rbind(*a, *b) },
+		"Cbind":            func() (interface{}, error) { return Cbind(*a, *c) },
+		"Unique":           func() (interface{}, error) { return a.Unique() },
+		"RemoveUnique":     func() (interface{}, error) { return a.RemoveUnique() },
+		"RemoveDuplicated": func() (interface{}, error) { return a.RemoveDuplicated() },
+		"Duplicated":       func() (interface{}, error) { return a.Duplicated() },
+		"Column":           func() (interface{}, error) { return a.Column("B") },
+		"Mutate":           func() (interface{}, error) { return a.Mutate("B", "B * 2 + C") },
+		"Arrange":          func() (interface{}, error) { return a.Arrange(SortKey{Column: "C", Desc: true}) },
+		"ApplyColumns": func() (interface{}, error) {
+			return a.ApplyColumns(func(k string, cells Cells) (Cells, error) { return cells, nil })
+		},
+		"ApplyRows":    func() (interface{}, error) { return a.ApplyRows(func(r Row) (Cells, error) { return r.Cells(), nil }) },
+		"MapColumn":    func() (interface{}, error) { return a.MapColumn("A", func(c Cell) Cell { return Strings("z")[0] }) },
+		"ArithColumns": func() (interface{}, error) { return a.AddColumn("B", "B", "C") },
+		"ArithValue":   func() (interface{}, error) { return a.MulValue("C", "C", 2) },
+		"SetLevels":    func() (interface{}, error) { return a.SetLevels("K", []string{"z", "y", "x"}, true) },
+		"RenameLevels": func() (interface{}, error) { return a.RenameLevels("K", map[string]string{"x": "y"}) },
+		"Describe":     func() (interface{}, error) { return a.Describe() },
+		"ValueCounts":  func() (interface{}, error) { return a.ValueCounts("A") },
+		"Histogram":    func() (interface{}, error) { return a.Histogram("B", Bins{Count: 2}) },
+		"DropNA":       func() (interface{}, error) { return a.DropNA(nil, "any", 0) },
+		"FillNA": func() (interface{}, error) {
+			return a.FillNA(FillNAOptions{Strategy: FillForward})
+		},
+		"Interpolate": func() (interface{}, error) { return a.Interpolate("C") },
+		"NACounts":    func() (interface{}, error) { return a.NACounts() },
+		"Rolling":     func() (interface{}, error) { return a.Rolling("B", 2, AggSum, WindowOptions{GroupBy: []string{"A"}}) },
+		"CumSum":      func() (interface{}, error) { return a.CumSum("C") },
+		"Shift":       func() (interface{}, error) { return a.Shift("A", 1) },
+		"Rank":        func() (interface{}, error) { return a.Rank("C", RankDense) },
+		"Resample": func() (interface{}, error) {
+			return a.Resample("T", "D", Aggregate{Column: "B", Agg: AggSum})
+		},
+		"Upsample":  func() (interface{}, error) { return a.Upsample("T", "D") },
+		"UnionAll":  func() (interface{}, error) { return UnionAll(*a, *b) },
+		"Union":     func() (interface{}, error) { return Union(*a, *b) },
+		"Intersect": func() (interface{}, error) { return Intersect(*a, *b) },
+		"Except":    func() (interface{}, error) { return Except(*a, *b) },
+		"Compare":   func() (interface{}, error) { return Compare(*a, *b, "T", "B") },
+		"Equal": func() (interface{}, error) {
+			return Equal(*a, *b, EqualOptions{IgnoreRowOrder: true}), nil
+		},
+		"String":     func() (interface{}, error) { return a.String(), nil },
+		"SaveCsv":    func() (interface{}, error) { return a.SaveCsv() },
+		"SaveJson":   func() (interface{}, error) { return a.SaveJson() },
+		"SaveRecord": func() (interface{}, error) { return a.SaveRecords(), nil },
+	}
+	for name, f := range ops {
+		if _, err := f(); err != nil && name != "Compare" {
+			t.Error(name, ":", err)
+		}
+		after := []string{snapshot(*a), snapshot(*b), snapshot(*c)}
+		for i := range before {
+			if before[i] != after[i] {
+				t.Error(
+					name, "modified its input\n",
+					"Expected:\n",
+					before[i], "\n",
+					"Received:\n",
+					after[i],
+				)
+			}
+		}
+	}
+}
+
+func TestDataFrame_CopyOnWrite(t *testing.T) {
+	a, _ := New(
+		C{"A", Strings("a", "b", "c")},
+		C{"B", Ints(1, 2, 3)},
+	)
+	x, _ := New(C{"A", Strings("x")}, C{"B", Ints(10)})
+	y, _ := New(C{"A", Strings("y")}, C{"B", Ints(20)})
+
+	// Ranges share the buffers of the original columns
+	sub, _ := a.SubsetRows(R{0, 2})
+	if &sub.Columns["B"].cells[0] != &a.Columns["B"].cells[0] {
+		t.Error("Expected SubsetRows to share the column buffers")
+	}
+	same, _ := a.SubsetRows([]int{0, 1, 2})
+	if &same.Columns["A"].cells[0] != &a.Columns["A"].cells[0] {
+		t.Error("Expected SubsetRows to share unchanged columns")
+	}
+
+	// Appending to a shared buffer must not overwrite the rows of others
+	r1, _ := Rbind(*sub, *x)
+	r2, _ := Rbind(*sub, *y)
+	if received := fmt.Sprint(r1.SaveRecords(), r2.SaveRecords(), a.SaveRecords()); received !=
+		"[[A B] [a 1] [b 2] [x 10]] [[A B] [a 1] [b 2] [y 20]] [[A B] [a 1] [b 2] [c 3]]" {
+		t.Error("Rbind overwrote a shared buffer:", received)
+	}
+
+	// Cbind must not add the new column indexes to its inputs
+	c, _ := New(C{"C", Floats(1, 2, 3)})
+	if _, err := Cbind(*a, *c); err != nil {
+		t.Error(err)
+	}
+	if fmt.Sprint(a.colIndexs) != "map[A:0 B:1]" {
+		t.Error("Cbind modified the column indexes of its input:", a.colIndexs)
+	}
+
+	// Values returned by the elements can't modify them
+	i, _ := a.Columns["B"].cells[0].Int()
+	*i = 100
+	cells, _ := a.Column("B")
+	cells[1] = Ints(200)[0]
+	if fmt.Sprint(a.Columns["B"].cells) != "[1 2 3]" {
+		t.Error("Elements were modified through returned values:", a.Columns["B"].cells)
+	}
+
+	// Modifying a derived DataFrame in place doesn't affect the original one
+	d, _ := a.SubsetRows(R{0, 3})
+	d.SetNames([]string{"X", "Y"})
+	d.Parse(T{"Y": "float"})
+	if fmt.Sprint(a.Names()) != "[A B]" || a.Columns["B"].colType != "df.Int" {
+		t.Error("Original DataFrame was modified:", a.Names(), a.Columns["B"].colType)
+	}
+}
@@ -0,0 +1,207 @@
+package df
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// JoinType is the kind of join between two DataFrames
+type JoinType int
+
+const (
+	// JoinInner keeps the rows with a match on both DataFrames
+	JoinInner JoinType = iota
+	// JoinLeft keeps all the rows of the left DataFrame
+	JoinLeft
+	// JoinRight keeps all the rows of the right DataFrame
+	JoinRight
+	// JoinCross returns the cartesian product of the rows of both DataFrames
+	JoinCross
+)
+
+func (j JoinType) String() string {
+	switch j {
+	case JoinInner:
+		return "inner"
+	case JoinLeft:
+		return "left"
+	case JoinRight:
+		return "right"
+	case JoinCross:
+		return "cross"
+	}
+	return "unknown"
+}
+
+// joinLayout describes the columns of the result of a join: the columns of
+// each DataFrame that appear on it, in order, and the names they get. Non key
+// columns present on both DataFrames are suffixed with ".x" on the left and
+// ".y" on the right. The key columns appear once, on their position on the
+// left DataFrame.
+type joinLayout struct {
+	left, leftOut   []string
+	right, rightOut []string
+}
+
+func newJoinLayout(a, b DataFrame, keys []string) joinLayout {
+	var l joinLayout
+	for _, k := range a.Names() {
+		l.left = append(l.left, k)
+		if _, ok := b.Columns[k]; ok && !inStringSlice(k, keys) {
+			l.leftOut = append(l.leftOut, k+".x")
+		} else {
+			l.leftOut = append(l.leftOut, k)
+		}
+	}
+	for _, k := range b.Names() {
+		if inStringSlice(k, keys) {
+			continue
+		}
+		l.right = append(l.right, k)
+		if _, ok := a.Columns[k]; ok {
+			l.rightOut = append(l.rightOut, k+".y")
+		} else {
+			l.rightOut = append(l.rightOut, k)
+		}
+	}
+	return l
+}
+
+// checkJoinKeys checks that the keys exist on both DataFrames with the same
+// type
+func checkJoinKeys(a, b DataFrame, keys []string) error {
+	errorArr := []string{}
+	for _, key := range keys {
+		ca, oka := a.Columns[key]
+		cb, okb := b.Columns[key]
+		if !oka {
+			errorArr = append(errorArr, fmt.Sprint("Can't find key \"", key, "\" on left DataFrame"))
+		}
+		if !okb {
+			errorArr = append(errorArr, fmt.Sprint("Can't find key \"", key, "\" on right DataFrame"))
+		}
+		if oka && okb && ca.colType != cb.colType {
+			errorArr = append(errorArr, fmt.Sprint("Different types for key \"", key, "\". Left: ", ca.colType, " Right: ", cb.colType))
+		}
+	}
+	if len(errorArr) != 0 {
+		return errors.New(strings.Join(errorArr, "\n"))
+	}
+	return nil
+}
+
+// joinRows returns the pairs of rows of a and b that form the result of the
+// join. Rows without a match get -1 as the row of the other DataFrame. Rows
+// follow the order of a, or of b on right joins, and matches follow the order
+// of the other DataFrame.
+func joinRows(a, b DataFrame, keys []string, how JoinType) (ra, rb []int) {
+	ra, rb = []int{}, []int{}
+	if how == JoinCross {
+		for i := 0; i < a.nRows; i++ {
+			for j := 0; j < b.nRows; j++ {
+				ra = append(ra, i)
+				rb = append(rb, j)
+			}
+		}
+		return ra, rb
+	}
+
+	outer, inner := a, b
+	if how == JoinRight {
+		outer, inner = b, a
+	}
+	index := make(map[string][]int, inner.nRows)
+	for j := 0; j < inner.nRows; j++ {
+		k := rowChecksum(inner, keys, j)
+		index[k] = append(index[k], j)
+	}
+	ro, ri := []int{}, []int{}
+	for i := 0; i < outer.nRows; i++ {
+		matches := index[rowChecksum(outer, keys, i)]
+		for _, j := range matches {
+			ro = append(ro, i)
+			ri = append(ri, j)
+		}
+		if len(matches) == 0 && how != JoinInner {
+			ro = append(ro, i)
+			ri = append(ri, -1)
+		}
+	}
+	if how == JoinRight {
+		return ri, ro
+	}
+	return ro, ri
+}
+
+// joinFrames joins two DataFrames placing the columns following the given
+// layout. Columns of the layout missing from the DataFrames are skipped.
+func joinFrames(a, b DataFrame, keys []string, how JoinType, l joinLayout) (*DataFrame, error) {
+	if how == JoinCross {
+		keys = nil
+	} else if len(keys) == 0 {
+		return nil, errors.New("No join keys given")
+	}
+	if err := checkJoinKeys(a, b, keys); err != nil {
+		return nil, err
+	}
+	ra, rb := joinRows(a, b, keys, how)
+
+	newDf := DataFrame{
+		Columns:   map[string]column{},
+		colIndexs: map[string]int{},
+		nRows:     len(ra),
+	}
+	add := func(col column, name string, rows []int) {
+		col = col.take(rows)
+		col.colName = name
+		newDf.Columns[name] = col
+		newDf.colIndexs[name] = len(newDf.colIndexs)
+	}
+	for i, k := range l.left {
+		col, ok := a.Columns[k]
+		if !ok {
+			continue
+		}
+		if how == JoinRight && inStringSlice(k, keys) {
+			// The keys of the rows without a match come from the right
+			add(b.Columns[k], l.leftOut[i], rb)
+			continue
+		}
+		add(col, l.leftOut[i], ra)
+	}
+	for i, k := range l.right {
+		if col, ok := b.Columns[k]; ok {
+			add(col, l.rightOut[i], rb)
+		}
+	}
+	return &newDf, nil
+}
+
+// InnerJoin returns a DataFrame containing the inner join of two other DataFrames.
+// This operation matches all rows that appear on both dataframes. Non key
+// columns present on both DataFrames are suffixed with ".x" and ".y".
+func InnerJoin(a DataFrame, b DataFrame, keys ...string) (*DataFrame, error) {
+	return joinFrames(a, b, keys, JoinInner, newJoinLayout(a, b, keys))
+}
+
+// LeftJoin returns a DataFrame containing the left join of two other DataFrames.
+// This operation matches all rows that appear on the left DataFrame and matches
+// it with the existing ones on the right one, filling the missing rows on the
+// right with an empty value.
+func LeftJoin(a DataFrame, b DataFrame, keys ...string) (*DataFrame, error) {
+	return joinFrames(a, b, keys, JoinLeft, newJoinLayout(a, b, keys))
+}
+
+// RightJoin returns all the rows of the right DataFrame matched with the rows
+// of the left one, filling the missing rows on the left with an empty value.
+// Columns are placed as on LeftJoin.
+func RightJoin(a DataFrame, b DataFrame, keys ...string) (*DataFrame, error) {
+	return joinFrames(a, b, keys, JoinRight, newJoinLayout(a, b, keys))
+}
+
+// CrossJoin returns a DataFrame containing the cartesian product of the rows on
+// both DataFrames.
+func CrossJoin(a DataFrame, b DataFrame) (*DataFrame, error) {
+	return joinFrames(a, b, nil, JoinCross, newJoinLayout(a, b, nil))
+}
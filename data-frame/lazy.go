@@ -0,0 +1,622 @@
+package df
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// planOp is the kind of a step of a query plan
+type planOp int
+
+const (
+	planScan planOp = iota
+	planFilter
+	planSelect
+	planRows
+	planJoin
+)
+
+// planNode is a step of a query plan. Nodes are never modified once they are
+// part of a LazyFrame, so several plans can share them, and the optimizer
+// builds new nodes instead.
+type planNode struct {
+	op     planOp
+	inputs []*planNode
+	// schema has the columns produced by the node and no rows
+	schema DataFrame
+
+	df     DataFrame // scan: the DataFrame that is read
+	preds  []string  // filter and scan: predicates that must hold on every row
+	cols   []string  // select and scan: the columns that are kept, nil for all
+	rows   []int     // rows and scan: the rows that are kept, nil for all
+	how    JoinType
+	keys   []string
+	layout joinLayout
+}
+
+// LazyFrame is a query over DataFrames that is only evaluated when it is
+// collected. Its methods return new LazyFrames and don't modify the receiver,
+// so a LazyFrame can be the base of several queries. Errors are kept until
+// the query is collected or explained.
+//
+// Before evaluating the query its plan is optimized: consecutive row
+// selections are merged and applied when reading the DataFrames, filters are
+// evaluated as early as possible, even before joins, and only the columns
+// needed by the rest of the query are read. Filters are never moved before a
+// row selection, since they would change the position of the rows.
+type LazyFrame struct {
+	node *planNode
+	err  error
+}
+
+// Lazy returns a LazyFrame that reads the DataFrame. Later changes to the
+// DataFrame made through pointer methods don't affect the query.
+func (df DataFrame) Lazy() *LazyFrame {
+	return &LazyFrame{node: &planNode{
+		op:     planScan,
+		df:     df.copy(),
+		schema: df.emptySchema(),
+	}}
+}
+
+// then returns a LazyFrame with the node built by f on top of the plan of l
+func (l *LazyFrame) then(f func(in *planNode) (*planNode, error)) *LazyFrame {
+	if l.err != nil {
+		return l
+	}
+	n, err := f(l.node)
+	if err != nil {
+		return &LazyFrame{err: err}
+	}
+	return &LazyFrame{node: n}
+}
+
+// Filter keeps the rows where the boolean expression expr is true. Rows where
+// it is NA are removed. See Mutate for the syntax of the expressions.
+func (l *LazyFrame) Filter(expr string) *LazyFrame {
+	return l.then(func(in *planNode) (*planNode, error) {
+		if _, err := predicateRefs(in.schema, expr); err != nil {
+			return nil, err
+		}
+		return &planNode{
+			op:     planFilter,
+			inputs: []*planNode{in},
+			schema: in.schema,
+			preds:  []string{expr},
+		}, nil
+	})
+}
+
+// Select keeps the given columns. As on SubsetColumns, the columns keep their
+// order.
+func (l *LazyFrame) Select(colnames ...string) *LazyFrame {
+	return l.then(func(in *planNode) (*planNode, error) {
+		for _, k := range colnames {
+			if _, err := in.schema.col(k); err != nil {
+				return nil, err
+			}
+		}
+		cols := []string{}
+		for _, k := range in.schema.Names() {
+			if inStringSlice(k, colnames) {
+				cols = append(cols, k)
+			}
+		}
+		if len(cols) == 0 {
+			return nil, ErrEmptySubset
+		}
+		return &planNode{
+			op:     planSelect,
+			inputs: []*planNode{in},
+			schema: in.schema.selectColumns(cols),
+			cols:   cols,
+		}, nil
+	})
+}
+
+// Rows keeps the given rows, given as on SubsetRows with an R or a []int
+func (l *LazyFrame) Rows(subset interface{}) *LazyFrame {
+	return l.then(func(in *planNode) (*planNode, error) {
+		var rows []int
+		switch s := subset.(type) {
+		case R:
+			if s.From > s.To {
+				return nil, errors.New("Bad subset: Start greater than Beginning")
+			}
+			if s.From < 0 {
+				return nil, errors.New("Subset out of range")
+			}
+			for i := s.From; i < s.To; i++ {
+				rows = append(rows, i)
+			}
+		case []int:
+			rows = append(rows, s...)
+		default:
+			return nil, errors.New("Unknown subsetting option")
+		}
+		if len(rows) == 0 {
+			return nil, ErrEmptySubset
+		}
+		return &planNode{
+			op:     planRows,
+			inputs: []*planNode{in},
+			schema: in.schema,
+			rows:   rows,
+		}, nil
+	})
+}
+
+// Join joins the rows with the ones of another LazyFrame by the given keys,
+// with the same result as InnerJoin, LeftJoin, RightJoin or CrossJoin
+// depending on how.
+func (l *LazyFrame) Join(other *LazyFrame, how JoinType, keys ...string) *LazyFrame {
+	return l.then(func(in *planNode) (*planNode, error) {
+		if other.err != nil {
+			return nil, other.err
+		}
+		if how == JoinCross {
+			keys = nil
+		}
+		layout := newJoinLayout(in.schema, other.node.schema, keys)
+		schema, err := joinFrames(in.schema, other.node.schema, keys, how, layout)
+		if err != nil {
+			return nil, err
+		}
+		return &planNode{
+			op:     planJoin,
+			inputs: []*planNode{in, other.node},
+			schema: *schema,
+			how:    how,
+			keys:   keys,
+			layout: layout,
+		}, nil
+	})
+}
+
+// Collect optimizes the plan and evaluates it, returning the resulting
+// DataFrame. Unlike the eager operations, queries that select no rows return
+// an empty DataFrame instead of an error.
+func (l *LazyFrame) Collect() (*DataFrame, error) {
+	if l.err != nil {
+		return nil, l.err
+	}
+	n, err := optimizePlan(l.node)
+	if err != nil {
+		return nil, err
+	}
+	d, err := n.exec()
+	if err != nil {
+		return nil, err
+	}
+	d = d.selectColumns(l.node.schema.Names())
+	return &d, nil
+}
+
+// Explain returns a description of the optimized plan, one step per line
+// with the inputs of each step indented below it
+func (l *LazyFrame) Explain() (string, error) {
+	if l.err != nil {
+		return "", l.err
+	}
+	n, err := optimizePlan(l.node)
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	n.explain(&buf, 0)
+	return buf.String(), nil
+}
+
+// emptySchema returns a DataFrame with the columns of df and no rows
+func (df DataFrame) emptySchema() DataFrame {
+	s := df.copy()
+	for k, v := range s.Columns {
+		v.cells = nil
+		s.Columns[k] = v
+	}
+	s.nRows = 0
+	return s
+}
+
+// selectColumns returns a DataFrame with the given columns of df, which must
+// exist, in the given order
+func (df DataFrame) selectColumns(colnames []string) DataFrame {
+	newDf := DataFrame{
+		Columns:   make(map[string]column, len(colnames)),
+		colIndexs: make(map[string]int, len(colnames)),
+		nRows:     df.nRows,
+	}
+	for i, k := range colnames {
+		newDf.Columns[k] = df.Columns[k]
+		newDf.colIndexs[k] = i
+	}
+	return newDf
+}
+
+// takeRows returns a DataFrame with the given rows of df. Unlike SubsetRows
+// the result can be empty.
+func (df DataFrame) takeRows(rows []int) (DataFrame, error) {
+	for _, i := range rows {
+		if i >= df.nRows {
+			return DataFrame{}, errors.New("Subset out of range")
+		}
+	}
+	newDf := df.copy()
+	newDf.nRows = len(rows)
+	for k, v := range df.Columns {
+		newDf.Columns[k] = v.take(rows)
+	}
+	return newDf, nil
+}
+
+// filterRows returns the rows of df where all the predicates are true
+func (df DataFrame) filterRows(preds []string) (DataFrame, error) {
+	if len(preds) == 0 {
+		return df, nil
+	}
+	nodes := make([]exprNode, 0, len(preds))
+	for _, p := range preds {
+		n, err := df.compileExpr(p)
+		if err != nil {
+			return DataFrame{}, err
+		}
+		nodes = append(nodes, n)
+	}
+	rows := []int{}
+	for i := 0; i < df.nRows; i++ {
+		keep := true
+		for _, n := range nodes {
+			b, err := evalBool(n, i)
+			if err != nil {
+				return DataFrame{}, fmt.Errorf("row %d: %v", i, err)
+			}
+			if b == nil || !*b {
+				keep = false
+				break
+			}
+		}
+		if keep {
+			rows = append(rows, i)
+		}
+	}
+	if len(rows) == df.nRows {
+		return df, nil
+	}
+	return df.takeRows(rows)
+}
+
+// predicateRefs checks that expr is a boolean expression over the columns of
+// the schema and returns the columns it refers to
+func predicateRefs(schema DataFrame, expr string) ([]string, error) {
+	n, refs, err := schema.compileExprRefs(expr)
+	if err != nil {
+		return nil, err
+	}
+	if n.cellType() != "df.Bool" {
+		return nil, errors.New("Filter expression is not boolean: " + expr)
+	}
+	return refs, nil
+}
+
+// optimizePlan returns an equivalent plan where row selections have been
+// merged, filters pushed down and unused columns pruned from the scans
+func optimizePlan(n *planNode) (*planNode, error) {
+	n, err := fuseRows(n)
+	if err != nil {
+		return nil, err
+	}
+	if n, err = pushFilters(n, nil); err != nil {
+		return nil, err
+	}
+	return pruneColumns(n, nil), nil
+}
+
+// fuseRows merges the row selections of the plan with the ones below them
+func fuseRows(n *planNode) (*planNode, error) {
+	c := *n
+	c.inputs = make([]*planNode, len(n.inputs))
+	for i, in := range n.inputs {
+		var err error
+		if c.inputs[i], err = fuseRows(in); err != nil {
+			return nil, err
+		}
+	}
+	if c.op != planRows {
+		return &c, nil
+	}
+	return applyRows(c.inputs[0], c.rows)
+}
+
+// applyRows returns a node that selects the given rows of the node in,
+// merging the selection into it when possible. Row selections can be moved
+// below column selections, merged with other row selections and with scans
+// that don't filter.
+func applyRows(in *planNode, rows []int) (*planNode, error) {
+	var err error
+	c := *in
+	switch in.op {
+	case planRows:
+		if c.rows, err = composeRows(in.rows, rows, len(in.rows)); err != nil {
+			return nil, err
+		}
+		return &c, nil
+	case planScan:
+		if len(in.preds) > 0 {
+			break
+		}
+		if c.rows, err = composeRows(in.rows, rows, in.df.nRows); err != nil {
+			return nil, err
+		}
+		return &c, nil
+	case planSelect:
+		child, err := applyRows(in.inputs[0], rows)
+		if err != nil {
+			return nil, err
+		}
+		c.inputs = []*planNode{child}
+		return &c, nil
+	}
+	return &planNode{
+		op:     planRows,
+		inputs: []*planNode{in},
+		schema: in.schema,
+		rows:   rows,
+	}, nil
+}
+
+// composeRows returns the rows selected by outer over the selection inner of
+// n rows. A nil inner selects all the rows.
+func composeRows(inner, outer []int, n int) ([]int, error) {
+	ret := make([]int, 0, len(outer))
+	for _, i := range outer {
+		switch {
+		case i >= n:
+			return nil, errors.New("Subset out of range")
+		case i < 0:
+			ret = append(ret, -1)
+		case inner == nil:
+			ret = append(ret, i)
+		default:
+			ret = append(ret, inner[i])
+		}
+	}
+	return ret, nil
+}
+
+// withFilter returns the node n filtered by the given predicates
+func withFilter(n *planNode, preds []string) *planNode {
+	if len(preds) == 0 {
+		return n
+	}
+	return &planNode{
+		op:     planFilter,
+		inputs: []*planNode{n},
+		schema: n.schema,
+		preds:  preds,
+	}
+}
+
+// pushFilters moves the filters of the plan, together with the given
+// predicates coming from above n, as close to the scans as possible
+func pushFilters(n *planNode, preds []string) (*planNode, error) {
+	c := *n
+	switch n.op {
+	case planScan:
+		c.preds = append(append([]string{}, n.preds...), preds...)
+		return &c, nil
+	case planFilter:
+		return pushFilters(n.inputs[0], append(append([]string{}, n.preds...), preds...))
+	case planSelect:
+		child, err := pushFilters(n.inputs[0], preds)
+		if err != nil {
+			return nil, err
+		}
+		c.inputs = []*planNode{child}
+		return &c, nil
+	case planRows:
+		child, err := pushFilters(n.inputs[0], nil)
+		if err != nil {
+			return nil, err
+		}
+		c.inputs = []*planNode{child}
+		return withFilter(&c, preds), nil
+	}
+
+	var left, right, keep []string
+	for _, p := range preds {
+		refs, err := predicateRefs(n.schema, p)
+		if err != nil {
+			return nil, err
+		}
+		onLeft, onRight := n.pushdown(refs)
+		if onLeft {
+			left = append(left, p)
+		}
+		if onRight {
+			right = append(right, p)
+		}
+		if !onLeft && !onRight {
+			keep = append(keep, p)
+		}
+	}
+	l, err := pushFilters(n.inputs[0], left)
+	if err != nil {
+		return nil, err
+	}
+	r, err := pushFilters(n.inputs[1], right)
+	if err != nil {
+		return nil, err
+	}
+	c.inputs = []*planNode{l, r}
+	return withFilter(&c, keep), nil
+}
+
+// pushdown returns whether a predicate over the given columns of the result
+// of a join can be evaluated on its left or right input instead. Predicates
+// over the key columns of an inner join are evaluated on both inputs.
+// Columns renamed by the join can't be pushed down.
+func (n *planNode) pushdown(refs []string) (left, right bool) {
+	left, right = true, true
+	for _, k := range refs {
+		key := inStringSlice(k, n.keys)
+		left = left && (key || inStringSlice(k, n.layout.left) && inStringSlice(k, n.layout.leftOut))
+		right = right && (key || inStringSlice(k, n.layout.right) && inStringSlice(k, n.layout.rightOut))
+	}
+	switch n.how {
+	case JoinLeft:
+		return left, false
+	case JoinRight:
+		return false, right
+	}
+	return left, right
+}
+
+// pruneColumns restricts the scans of the plan to the columns needed to
+// produce the required ones, or all of them if required is nil
+func pruneColumns(n *planNode, required []string) *planNode {
+	c := *n
+	var needed []string
+	if required != nil {
+		needed = append(needed, required...)
+		for _, p := range n.preds {
+			refs, _ := predicateRefs(n.schema, p)
+			needed = append(needed, refs...)
+		}
+	}
+
+	switch n.op {
+	case planScan:
+		if needed != nil {
+			cols := []string{}
+			for _, k := range n.schema.Names() {
+				if inStringSlice(k, needed) {
+					cols = append(cols, k)
+				}
+			}
+			if len(cols) < len(n.schema.Columns) {
+				c.cols = cols
+			}
+		}
+	case planFilter, planRows:
+		c.inputs = []*planNode{pruneColumns(n.inputs[0], needed)}
+	case planSelect:
+		cols := []string{}
+		for _, k := range n.cols {
+			if needed == nil || inStringSlice(k, needed) {
+				cols = append(cols, k)
+			}
+		}
+		c.cols = cols
+		c.inputs = []*planNode{pruneColumns(n.inputs[0], cols)}
+	case planJoin:
+		var left, right []string
+		if needed != nil {
+			left = append(left, n.keys...)
+			right = append(right, n.keys...)
+			for i, k := range n.layout.leftOut {
+				if inStringSlice(k, needed) {
+					left = append(left, n.layout.left[i])
+				}
+			}
+			for i, k := range n.layout.rightOut {
+				if inStringSlice(k, needed) {
+					right = append(right, n.layout.right[i])
+				}
+			}
+		}
+		c.inputs = []*planNode{
+			pruneColumns(n.inputs[0], left),
+			pruneColumns(n.inputs[1], right),
+		}
+	}
+	return &c
+}
+
+// exec evaluates the plan
+func (n *planNode) exec() (DataFrame, error) {
+	inputs := make([]DataFrame, len(n.inputs))
+	for i, in := range n.inputs {
+		var err error
+		if inputs[i], err = in.exec(); err != nil {
+			return DataFrame{}, err
+		}
+	}
+
+	switch n.op {
+	case planScan:
+		d := n.df
+		if n.cols != nil {
+			d = d.selectColumns(n.cols)
+		}
+		if n.rows != nil {
+			var err error
+			if d, err = d.takeRows(n.rows); err != nil {
+				return DataFrame{}, err
+			}
+		}
+		return d.filterRows(n.preds)
+	case planFilter:
+		return inputs[0].filterRows(n.preds)
+	case planSelect:
+		return inputs[0].selectColumns(n.cols), nil
+	case planRows:
+		return inputs[0].takeRows(n.rows)
+	}
+	d, err := joinFrames(inputs[0], inputs[1], n.keys, n.how, n.layout)
+	if err != nil {
+		return DataFrame{}, err
+	}
+	return *d, nil
+}
+
+// explain writes the description of the node and its inputs
+func (n *planNode) explain(buf *bytes.Buffer, depth int) {
+	buf.WriteString(strings.Repeat("  ", depth))
+	switch n.op {
+	case planScan:
+		cols := n.cols
+		if cols == nil {
+			cols = n.schema.Names()
+		}
+		fmt.Fprintf(buf, "Scan %v", cols)
+		if n.rows != nil {
+			fmt.Fprintf(buf, " rows=%s", formatRows(n.rows))
+		}
+		if len(n.preds) > 0 {
+			fmt.Fprintf(buf, " filter=%s", formatPreds(n.preds))
+		}
+	case planFilter:
+		fmt.Fprintf(buf, "Filter %s", formatPreds(n.preds))
+	case planSelect:
+		fmt.Fprintf(buf, "Select %v", n.cols)
+	case planRows:
+		fmt.Fprintf(buf, "Rows %s", formatRows(n.rows))
+	case planJoin:
+		fmt.Fprintf(buf, "Join %s", n.how)
+		if n.how != JoinCross {
+			fmt.Fprintf(buf, " on %v", n.keys)
+		}
+	}
+	buf.WriteString("\n")
+	for _, in := range n.inputs {
+		in.explain(buf, depth+1)
+	}
+}
+
+// formatPreds joins several predicates with &&
+func formatPreds(preds []string) string {
+	if len(preds) == 1 {
+		return preds[0]
+	}
+	return "(" + strings.Join(preds, ") && (") + ")"
+}
+
+// formatRows returns the list of rows, shortened if it is too long
+func formatRows(rows []int) string {
+	if len(rows) <= 10 {
+		return fmt.Sprint(rows)
+	}
+	return fmt.Sprintf("%v (%d rows)", strings.TrimSuffix(fmt.Sprint(rows[:10]), "]")+" ...]", len(rows))
+}
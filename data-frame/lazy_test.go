@@ -0,0 +1,222 @@
+package df
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestJoins(t *testing.T) {
+	a, _ := New(
+		C{"ID", Ints(1, 2, 3, 2)},
+		C{"Name", Strings("a", "b", "c", "d")},
+		C{"V", Floats(1.5, 2.5, 3.5, 4.5)},
+	)
+	b, _ := New(
+		C{"V", Ints(10, 20, 30)},
+		C{"ID", Ints(2, 4, 1)},
+	)
+	var tests = []struct {
+		f        func() (*DataFrame, error)
+		expected string
+	}{
+		{func() (*DataFrame, error) { return InnerJoin(*a, *b, "ID") }, "[[ID Name V.x V.y] [1 a 1.5 30] [2 b 2.5 10] [2 d 4.5 10]]"},
+		{func() (*DataFrame, error) { return LeftJoin(*a, *b, "ID") }, "[[ID Name V.x V.y] [1 a 1.5 30] [2 b 2.5 10] [3 c 3.5 NA] [2 d 4.5 10]]"},
+		{func() (*DataFrame, error) { return RightJoin(*a, *b, "ID") }, "[[ID Name V.x V.y] [2 b 2.5 10] [2 d 4.5 10] [4 NA NA 20] [1 a 1.5 30]]"},
+		{func() (*DataFrame, error) {
+			d, _ := a.SubsetRows(R{0, 2})
+			e, _ := b.SubsetColumns([]string{"V"})
+			return CrossJoin(*d, *e)
+		}, "[[ID Name V.x V.y] [1 a 1.5 10] [1 a 1.5 20] [1 a 1.5 30] [2 b 2.5 10] [2 b 2.5 20] [2 b 2.5 30]]"},
+	}
+	for k, v := range tests {
+		dd, err := v.f()
+		if err != nil {
+			t.Error("Test", k, ":", err)
+			continue
+		}
+		received := fmt.Sprint(dd.SaveRecords())
+		if v.expected != received {
+			t.Error(
+				"Test", k, "\n",
+				"Expected:\n",
+				v.expected, "\n",
+				"Received:\n",
+				received,
+			)
+		}
+	}
+
+	if _, err := InnerJoin(*a, *b, "ID", "X"); err == nil {
+		t.Error("Expected an error joining by a missing key")
+	}
+	if _, err := InnerJoin(*a, *b, "V"); err == nil {
+		t.Error("Expected an error joining by keys of different types")
+	}
+}
+
+func TestLazyFrame(t *testing.T) {
+	people, _ := New(
+		C{"ID", Ints(1, 2, 3, 4, 5)},
+		C{"Name", Strings("Ann", "Bob", "Cid", "Dee", "Eve")},
+		C{"Age", Ints(34, 17, 52, nil, 41)},
+		C{"City", Strings("Oslo", "Rome", "Oslo", "Lima", "Rome")},
+	)
+	orders, _ := New(
+		C{"ID", Ints(1, 3, 3, 5, 6)},
+		C{"Total", Floats(10.5, 20, 5, 7.5, 1)},
+		C{"City", Strings("Oslo", "Oslo", "Rome", "Rome", "Lima")},
+	)
+	before := []string{snapshot(*people), snapshot(*orders)}
+
+	// Eager version of each query to compare with
+	filter := func(d *DataFrame, expr string) *DataFrame {
+		cells, _, err := d.evalExpr(expr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rows := []int{}
+		for i, c := range cells {
+			if b, _ := cellBool(c); b != nil && *b {
+				rows = append(rows, i)
+			}
+		}
+		ret, _ := d.takeRows(rows)
+		return &ret
+	}
+
+	var tests = []struct {
+		lazy    *LazyFrame
+		eager   func() (*DataFrame, error)
+		explain string
+	}{
+		{
+			people.Lazy().Filter("Age > 30").Select("Name", "ID"),
+			func() (*DataFrame, error) {
+				return filter(people, "Age > 30").SubsetColumns([]string{"ID", "Name"})
+			},
+			"Select [ID Name]\n  Scan [ID Name Age] filter=Age > 30\n",
+		},
+		{
+			people.Lazy().Rows(R{1, 5}).Rows([]int{3, 0, 2}).Filter("City != \"Lima\""),
+			func() (*DataFrame, error) {
+				d, _ := people.SubsetRows(R{1, 5})
+				d, _ = d.SubsetRows([]int{3, 0, 2})
+				return filter(d, "City != \"Lima\""), nil
+			},
+			"Scan [ID Name Age City] rows=[4 1 3] filter=City != \"Lima\"\n",
+		},
+		{
+			people.Lazy().Filter("Age > 20").Rows([]int{0, 1}),
+			func() (*DataFrame, error) {
+				return filter(people, "Age > 20").SubsetRows([]int{0, 1})
+			},
+			"Rows [0 1]\n  Scan [ID Name Age City] filter=Age > 20\n",
+		},
+		{
+			people.Lazy().Join(orders.Lazy(), JoinInner, "ID").
+				Filter("Age > 30").
+				Filter("Total > 6").
+				Filter("ID < 5").
+				Filter("City.x == City.y").
+				Select("Name", "Total"),
+			func() (*DataFrame, error) {
+				d, _ := InnerJoin(*people, *orders, "ID")
+				d = filter(d, "Age > 30 && Total > 6 && ID < 5 && City.x == City.y")
+				return d.SubsetColumns([]string{"Name", "Total"})
+			},
+			"Select [Name Total]\n" +
+				"  Filter City.x == City.y\n" +
+				"    Join inner on [ID]\n" +
+				"      Scan [ID Name Age City] filter=(Age > 30) && (ID < 5)\n" +
+				"      Scan [ID Total City] filter=(Total > 6) && (ID < 5)\n",
+		},
+		{
+			people.Lazy().Join(orders.Lazy().Select("ID", "Total"), JoinLeft, "ID").
+				Filter("Total > 6").
+				Filter("ID > 1").
+				Select("Name"),
+			func() (*DataFrame, error) {
+				o, _ := orders.SubsetColumns([]string{"ID", "Total"})
+				d, _ := LeftJoin(*people, *o, "ID")
+				d = filter(d, "Total > 6 && ID > 1")
+				return d.SubsetColumns([]string{"Name"})
+			},
+			"Select [Name]\n" +
+				"  Filter Total > 6\n" +
+				"    Join left on [ID]\n" +
+				"      Scan [ID Name] filter=ID > 1\n" +
+				"      Select [ID Total]\n" +
+				"        Scan [ID Total]\n",
+		},
+		{
+			people.Lazy().Join(orders.Lazy(), JoinRight, "ID").Filter("ID >= 5").Rows([]int{1, 0}),
+			func() (*DataFrame, error) {
+				d, _ := RightJoin(*people, *orders, "ID")
+				return filter(d, "ID >= 5").SubsetRows([]int{1, 0})
+			},
+			"Rows [1 0]\n" +
+				"  Join right on [ID]\n" +
+				"    Scan [ID Name Age City]\n" +
+				"    Scan [ID Total City] filter=ID >= 5\n",
+		},
+	}
+	for k, v := range tests {
+		expected, err := v.eager()
+		if err != nil {
+			t.Error("Test", k, ":", err)
+			continue
+		}
+		received, err := v.lazy.Collect()
+		if err != nil {
+			t.Error("Test", k, ":", err)
+			continue
+		}
+		if diff := Differences(*received, *expected); diff != nil {
+			t.Error("Test", k, ": lazy and eager results differ:", diff)
+		}
+		explain, err := v.lazy.Explain()
+		if err != nil || explain != v.explain {
+			t.Error(
+				"Test", k, "\n",
+				"Expected:\n",
+				v.explain, "\n",
+				"Received:\n",
+				explain, err,
+			)
+		}
+	}
+
+	// Queries can be reused and don't modify their inputs
+	base := people.Lazy().Filter("Age > 30")
+	d1, _ := base.Select("Name").Collect()
+	d2, _ := base.Collect()
+	if d1.NCols() != 1 || d2.NCols() != 4 || d2.NRows() != 3 {
+		t.Error("Unexpected results from a shared query:", d1, d2)
+	}
+	empty, err := base.Filter("Age > 100").Collect()
+	if err != nil || empty.NRows() != 0 || empty.NCols() != 4 {
+		t.Error("Expected an empty DataFrame, received:", empty, err)
+	}
+	if after := []string{snapshot(*people), snapshot(*orders)}; fmt.Sprint(after) != fmt.Sprint(before) {
+		t.Error("Lazy queries modified their inputs:", after)
+	}
+
+	// Errors
+	var errs = []*LazyFrame{
+		people.Lazy().Filter("Unknown > 1"),
+		people.Lazy().Filter("Age + 1"),
+		people.Lazy().Select("Unknown"),
+		people.Lazy().Rows([]int{10}),
+		people.Lazy().Rows(R{3, 1}),
+		people.Lazy().Join(orders.Lazy(), JoinInner, "Name"),
+		people.Lazy().Select("Age").Filter("Name == \"Ann\""),
+	}
+	for k, l := range errs {
+		if _, err := l.Collect(); err == nil {
+			t.Error("Error test", k, ": expected an error")
+		}
+		if _, err := l.Explain(); err == nil {
+			t.Error("Error test", k, ": expected an error")
+		}
+	}
+}
@@ -0,0 +1,247 @@
+package df
+
+import (
+	"html"
+	"strings"
+	"unicode/utf8"
+)
+
+// HTMLOptions modifies how HTML writes a DataFrame. Class is set as the class
+// attribute of the table and Caption is added as its caption.
+type HTMLOptions struct {
+	FormatOptions
+	Class   string
+	Caption string
+}
+
+// LaTeXOptions modifies how LaTeX writes a DataFrame. Booktabs uses the rules
+// of the booktabs package instead of \hline.
+type LaTeXOptions struct {
+	FormatOptions
+	Booktabs bool
+}
+
+// grid returns the names, types, alignment and rows of the printed table, with
+// the ellipsis column and row in place of the ones left out
+func (t printedTable) grid() (names, types []string, right []bool, rows [][]string) {
+	for j := 0; j <= len(t.cols); j++ {
+		if j == t.colGap {
+			names, types, right = append(names, ellipsis), append(types, ""), append(right, false)
+		}
+		if j < len(t.cols) {
+			col := t.cols[j]
+			names, types, right = append(names, col.name), append(types, col.typ), append(right, col.right)
+		}
+	}
+	for i := 0; i <= len(t.rows); i++ {
+		if i == t.rowGap {
+			row := make([]string, len(names))
+			for j := range row {
+				row[j] = ellipsis
+			}
+			rows = append(rows, row)
+		}
+		if i == len(t.rows) {
+			break
+		}
+		row := make([]string, 0, len(names))
+		for j, col := range t.cols {
+			if j == t.colGap {
+				row = append(row, ellipsis)
+			}
+			row = append(row, col.cells[i])
+		}
+		if t.colGap == len(t.cols) {
+			row = append(row, ellipsis)
+		}
+		rows = append(rows, row)
+	}
+	return names, types, right, rows
+}
+
+var markdownEscaper = strings.NewReplacer("|", `\|`, "<", "&lt;", ">", "&gt;")
+
+// Markdown returns the DataFrame as a GitHub flavored Markdown table, or an
+// empty string if it has no columns. The column types, if shown, are written
+// as the first row of the table.
+func (df DataFrame) Markdown(opts ...FormatOptions) string {
+	var o FormatOptions
+	if len(opts) > 0 {
+		o = opts[0]
+	}
+	t := df.layout(o)
+	names, types, right, rows := t.grid()
+	if len(names) == 0 {
+		return ""
+	}
+	if o.ShowTypes {
+		for j, typ := range types {
+			if typ != "" {
+				types[j] = "`" + typ + "`"
+			}
+		}
+		rows = append([][]string{types}, rows...)
+	}
+
+	escaped := func(row []string) []string {
+		ret := make([]string, len(row))
+		for j, s := range row {
+			ret[j] = markdownEscaper.Replace(s)
+		}
+		return ret
+	}
+	names = escaped(names)
+	for i := range rows {
+		rows[i] = escaped(rows[i])
+	}
+	widths := make([]int, len(names))
+	for j, name := range names {
+		widths[j] = maxInt(len(ellipsis), utf8.RuneCountInString(name))
+		for _, row := range rows {
+			widths[j] = maxInt(widths[j], utf8.RuneCountInString(row[j]))
+		}
+	}
+
+	var b strings.Builder
+	line := func(cells []string) {
+		b.WriteString("|")
+		for j, s := range cells {
+			b.WriteString(" ")
+			pad(&b, s, widths[j], right[j])
+			b.WriteString(" |")
+		}
+		b.WriteString("\n")
+	}
+	line(names)
+	b.WriteString("|")
+	for j, w := range widths {
+		if right[j] {
+			b.WriteString(" " + strings.Repeat("-", w-1) + ": |")
+		} else {
+			b.WriteString(" " + strings.Repeat("-", w) + " |")
+		}
+	}
+	b.WriteString("\n")
+	for _, row := range rows {
+		line(row)
+	}
+	if t.truncated() {
+		b.WriteString("\n" + t.dims() + "\n")
+	}
+	return b.String()
+}
+
+// HTML returns the DataFrame as an HTML table with its names and elements
+// escaped. Numeric columns aligned to the right get a text-align style. As
+// with Markdown, a DataFrame without columns gives an empty string.
+func (df DataFrame) HTML(opts ...HTMLOptions) string {
+	var o HTMLOptions
+	if len(opts) > 0 {
+		o = opts[0]
+	}
+	t := df.layout(o.FormatOptions)
+	names, types, right, rows := t.grid()
+	if len(names) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	line := func(tag string, cells []string) {
+		b.WriteString("    <tr>")
+		for j, s := range cells {
+			b.WriteString("<" + tag)
+			if right[j] {
+				b.WriteString(` style="text-align: right"`)
+			}
+			b.WriteString(">" + html.EscapeString(s) + "</" + tag + ">")
+		}
+		b.WriteString("</tr>\n")
+	}
+	b.WriteString("<table")
+	if o.Class != "" {
+		b.WriteString(` class="` + html.EscapeString(o.Class) + `"`)
+	}
+	b.WriteString(">\n")
+	if o.Caption != "" {
+		b.WriteString("  <caption>" + html.EscapeString(o.Caption) + "</caption>\n")
+	}
+	b.WriteString("  <thead>\n")
+	line("th", names)
+	if o.ShowTypes {
+		line("th", types)
+	}
+	b.WriteString("  </thead>\n  <tbody>\n")
+	for _, row := range rows {
+		line("td", row)
+	}
+	b.WriteString("  </tbody>\n</table>\n")
+	if t.truncated() {
+		b.WriteString("<p>" + t.dims() + "</p>\n")
+	}
+	return b.String()
+}
+
+var latexEscaper = strings.NewReplacer(
+	`\`, `\textbackslash{}`,
+	"&", `\&`,
+	"%", `\%`,
+	"$", `\$`,
+	"#", `\#`,
+	"_", `\_`,
+	"{", `\{`,
+	"}", `\}`,
+	"~", `\textasciitilde{}`,
+	"^", `\textasciicircum{}`,
+)
+
+// LaTeX returns the DataFrame as a LaTeX tabular environment with its names
+// and elements escaped. Numeric columns aligned to the right use the r column
+// specifier. Nothing is written for a DataFrame without columns.
+func (df DataFrame) LaTeX(opts ...LaTeXOptions) string {
+	var o LaTeXOptions
+	if len(opts) > 0 {
+		o = opts[0]
+	}
+	t := df.layout(o.FormatOptions)
+	names, types, right, rows := t.grid()
+	if len(names) == 0 {
+		return ""
+	}
+	top, mid, bottom := `\hline`, `\hline`, `\hline`
+	if o.Booktabs {
+		top, mid, bottom = `\toprule`, `\midrule`, `\bottomrule`
+	}
+
+	var b strings.Builder
+	line := func(cells []string) {
+		for j, s := range cells {
+			if j > 0 {
+				b.WriteString(" & ")
+			}
+			b.WriteString(latexEscaper.Replace(s))
+		}
+		b.WriteString(` \\` + "\n")
+	}
+	b.WriteString(`\begin{tabular}{`)
+	for _, r := range right {
+		if r {
+			b.WriteString("r")
+		} else {
+			b.WriteString("l")
+		}
+	}
+	b.WriteString("}\n" + top + "\n")
+	line(names)
+	if o.ShowTypes {
+		line(types)
+	}
+	b.WriteString(mid + "\n")
+	for _, row := range rows {
+		line(row)
+	}
+	b.WriteString(bottom + "\n" + `\end{tabular}` + "\n")
+	if t.truncated() {
+		b.WriteString(t.dims() + "\n")
+	}
+	return b.String()
+}
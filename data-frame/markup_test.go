@@ -0,0 +1,199 @@
+package df
+
+import (
+	"strings"
+	"testing"
+)
+
+func markupFrame(t *testing.T) *DataFrame {
+	a, err := New(
+		C{"Name", Strings("Alice", "B|o<b>", "Carol & Co", nil)},
+		C{"Age", Ints(32, 5, nil, 41)},
+		C{"Amount_%", Floats(10.5, 1234.567, 3, nil)},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return a
+}
+
+func TestDataFrame_Markdown(t *testing.T) {
+	a := markupFrame(t)
+	table := []struct {
+		opts     []FormatOptions
+		expected []string
+	}{
+		{
+			nil,
+			[]string{
+				"| Name          | Age | Amount_% |",
+				"| ------------- | --- | -------- |",
+				"| Alice         | 32  | 10.5     |",
+				"| B\\|o&lt;b&gt; | 5   | 1234.567 |",
+				"| Carol & Co    | NA  | 3        |",
+				"| NA            | 41  | NA       |",
+			},
+		},
+		{
+			[]FormatOptions{{MaxRows: 2, MaxColumns: 2, AlignNumbers: true, Precision: 1, ShowTypes: true}},
+			[]string{
+				"| Name     | ... | Amount_% |",
+				"| -------- | --- | -------: |",
+				"| `string` |     |  `float` |",
+				"| Alice    | ... |     10.5 |",
+				"| ...      | ... |      ... |",
+				"| NA       | ... |       NA |",
+				"",
+				"[4 rows x 3 columns]",
+			},
+		},
+	}
+	for k, v := range table {
+		expected := strings.Join(v.expected, "\n") + "\n"
+		received := a.Markdown(v.opts...)
+		if expected != received {
+			t.Error(
+				"Test", k, "\n",
+				"Expected:\n",
+				expected, "\n",
+				"Received:\n",
+				received,
+			)
+		}
+	}
+}
+
+func TestDataFrame_HTML(t *testing.T) {
+	a := markupFrame(t)
+	table := []struct {
+		opts     []HTMLOptions
+		expected []string
+	}{
+		{
+			nil,
+			[]string{
+				"<table>",
+				"  <thead>",
+				"    <tr><th>Name</th><th>Age</th><th>Amount_%</th></tr>",
+				"  </thead>",
+				"  <tbody>",
+				"    <tr><td>Alice</td><td>32</td><td>10.5</td></tr>",
+				"    <tr><td>B|o&lt;b&gt;</td><td>5</td><td>1234.567</td></tr>",
+				"    <tr><td>Carol &amp; Co</td><td>NA</td><td>3</td></tr>",
+				"    <tr><td>NA</td><td>41</td><td>NA</td></tr>",
+				"  </tbody>",
+				"</table>",
+			},
+		},
+		{
+			[]HTMLOptions{{
+				FormatOptions: FormatOptions{MaxRows: 1, MaxWidth: 4, AlignNumbers: true, NA: "-"},
+				Class:         "data wide",
+				Caption:       "People <2016>",
+			}},
+			[]string{
+				`<table class="data wide">`,
+				"  <caption>People &lt;2016&gt;</caption>",
+				"  <thead>",
+				`    <tr><th>Name</th><th style="text-align: right">Age</th><th style="text-align: right">A...</th></tr>`,
+				"  </thead>",
+				"  <tbody>",
+				`    <tr><td>A...</td><td style="text-align: right">32</td><td style="text-align: right">10.5</td></tr>`,
+				`    <tr><td>...</td><td style="text-align: right">...</td><td style="text-align: right">...</td></tr>`,
+				"  </tbody>",
+				"</table>",
+				"<p>[4 rows x 3 columns]</p>",
+			},
+		},
+	}
+	for k, v := range table {
+		expected := strings.Join(v.expected, "\n") + "\n"
+		received := a.HTML(v.opts...)
+		if expected != received {
+			t.Error(
+				"Test", k, "\n",
+				"Expected:\n",
+				expected, "\n",
+				"Received:\n",
+				received,
+			)
+		}
+	}
+}
+
+func TestDataFrame_LaTeX(t *testing.T) {
+	a := markupFrame(t)
+	table := []struct {
+		opts     []LaTeXOptions
+		expected []string
+	}{
+		{
+			nil,
+			[]string{
+				`\begin{tabular}{lll}`,
+				`\hline`,
+				`Name & Age & Amount\_\% \\`,
+				`\hline`,
+				`Alice & 32 & 10.5 \\`,
+				`B|o<b> & 5 & 1234.567 \\`,
+				`Carol \& Co & NA & 3 \\`,
+				`NA & 41 & NA \\`,
+				`\hline`,
+				`\end{tabular}`,
+			},
+		},
+		{
+			[]LaTeXOptions{{
+				FormatOptions: FormatOptions{MaxColumns: 2, AlignNumbers: true, ShowTypes: true, Precision: 2},
+				Booktabs:      true,
+			}},
+			[]string{
+				`\begin{tabular}{llr}`,
+				`\toprule`,
+				`Name & ... & Amount\_\% \\`,
+				`string &  & float \\`,
+				`\midrule`,
+				`Alice & ... & 10.50 \\`,
+				`B|o<b> & ... & 1234.57 \\`,
+				`Carol \& Co & ... & 3.00 \\`,
+				`NA & ... & NA \\`,
+				`\bottomrule`,
+				`\end{tabular}`,
+				`[4 rows x 3 columns]`,
+			},
+		},
+	}
+	for k, v := range table {
+		expected := strings.Join(v.expected, "\n") + "\n"
+		received := a.LaTeX(v.opts...)
+		if expected != received {
+			t.Error(
+				"Test", k, "\n",
+				"Expected:\n",
+				expected, "\n",
+				"Received:\n",
+				received,
+			)
+		}
+	}
+}
+
+func TestDataFrame_MarkupNoColumns(t *testing.T) {
+	var a DataFrame
+	received := []string{
+		a.Markdown(FormatOptions{ShowTypes: true}),
+		a.HTML(HTMLOptions{Class: "data", Caption: "Empty"}),
+		a.LaTeX(LaTeXOptions{Booktabs: true}),
+	}
+	for k, v := range received {
+		if v != "" {
+			t.Error(
+				"Test", k, "\n",
+				"Expected:\n",
+				"", "\n",
+				"Received:\n",
+				v,
+			)
+		}
+	}
+}
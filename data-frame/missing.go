@@ -0,0 +1,369 @@
+package df
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+)
+
+// columnsOrAll returns the given columns checking that they exist, or all the
+// columns of the DataFrame if none are given
+func (df DataFrame) columnsOrAll(colnames []string) ([]string, error) {
+	if len(colnames) == 0 {
+		return df.Names(), nil
+	}
+	for _, k := range colnames {
+		if _, err := df.col(k); err != nil {
+			return nil, err
+		}
+	}
+	return colnames, nil
+}
+
+// DropNA returns a new DataFrame without the rows that have NA elements on the
+// given columns, or on any column if cols is empty. If how is "any" the rows
+// with at least one NA element are removed, and if it is "all" only the rows
+// where all the elements are NA. If thresh is greater than zero it takes
+// precedence over how and the rows with less than thresh non NA elements are
+// removed. If all the rows are removed the result is a DataFrame without rows
+// that keeps the columns.
+func (df DataFrame) DropNA(cols []string, how string, thresh int) (*DataFrame, error) {
+	cols, err := df.columnsOrAll(cols)
+	if err != nil {
+		return nil, err
+	}
+	if how != "any" && how != "all" {
+		return nil, errors.New("Unknown DropNA mode: " + how)
+	}
+
+	rows := []int{}
+	for i := 0; i < df.nRows; i++ {
+		valid := 0
+		for _, k := range cols {
+			if !df.Columns[k].cells[i].IsNA() {
+				valid++
+			}
+		}
+		switch {
+		case thresh > 0:
+			if valid >= thresh {
+				rows = append(rows, i)
+			}
+		case how == "any":
+			if valid == len(cols) {
+				rows = append(rows, i)
+			}
+		case valid > 0:
+			rows = append(rows, i)
+		}
+	}
+	newDf, err := df.takeRows(rows)
+	if err != nil {
+		return nil, err
+	}
+	return &newDf, nil
+}
+
+// FillStrategy is the method used by FillNA to replace NA elements
+type FillStrategy int
+
+const (
+	// FillConstant uses the given Value or the Values of each column
+	FillConstant FillStrategy = iota
+	// FillForward uses the last non NA element before the NA one
+	FillForward
+	// FillBackward uses the first non NA element after the NA one
+	FillBackward
+	// FillMean uses the mean of the numeric column
+	FillMean
+	// FillMedian uses the median of the numeric column
+	FillMedian
+	// FillMode uses the most frequent element of the column
+	FillMode
+)
+
+// FillNAOptions configures FillNA. Columns restricts the columns that will be
+// filled, otherwise all columns are. Value and Values are used by the
+// FillConstant strategy: Values gives the value for each column and Value is
+// used for the columns missing from it. Values can be given as Cells, int,
+// int64, uint64, float64 or as a string that will be parsed with the type of
+// the column.
+type FillNAOptions struct {
+	Strategy FillStrategy
+	Columns  []string
+	Value    interface{}
+	Values   map[string]interface{}
+}
+
+// FillNA returns a new DataFrame where the NA elements have been replaced using
+// the given strategy. Elements that can't be filled, like leading NA elements
+// when filling forward, are left as NA.
+func (df DataFrame) FillNA(opts FillNAOptions) (*DataFrame, error) {
+	cols := opts.Columns
+	if len(cols) == 0 && opts.Strategy == FillConstant && opts.Value == nil {
+		for _, k := range df.Names() {
+			if _, ok := opts.Values[k]; ok {
+				cols = append(cols, k)
+			}
+		}
+		if len(cols) == 0 {
+			return nil, errors.New("No values given to fill NA elements")
+		}
+	}
+	cols, err := df.columnsOrAll(cols)
+	if err != nil {
+		return nil, err
+	}
+
+	newcols := make([]*column, len(cols))
+	err = parallel(len(cols), func(i int) error {
+		k := cols[i]
+		col := df.Columns[k]
+		cells, err := fillColumn(col, opts)
+		if err != nil {
+			return fmt.Errorf("column %s: %v", k, err)
+		}
+		if len(cells) == 0 {
+			// Filling keeps the type of the column
+			newcols[i] = &col
+			return nil
+		}
+		if newcols[i], err = newCol(k, cells); err != nil {
+			return fmt.Errorf("column %s: %v", k, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	newDf := df.copy()
+	for i, k := range cols {
+		newDf.Columns[k] = *newcols[i]
+	}
+	return &newDf, nil
+}
+
+// fillColumn returns the elements of the column with the NA elements replaced
+// following the given options
+func fillColumn(col column, opts FillNAOptions) (Cells, error) {
+	cells := make(Cells, len(col.cells))
+	copy(cells, col.cells)
+
+	switch opts.Strategy {
+	case FillForward:
+		for i := 1; i < len(cells); i++ {
+			if cells[i].IsNA() {
+				cells[i] = cells[i-1]
+			}
+		}
+		return cells, nil
+	case FillBackward:
+		for i := len(cells) - 2; i >= 0; i-- {
+			if cells[i].IsNA() {
+				cells[i] = cells[i+1]
+			}
+		}
+		return cells, nil
+	}
+
+	var fill Cell
+	var err error
+	switch opts.Strategy {
+	case FillConstant:
+		v, ok := opts.Values[col.colName]
+		if !ok {
+			v = opts.Value
+		}
+		if v == nil {
+			return nil, errors.New("no value given to fill NA elements")
+		}
+		fill, err = columnValue(v, col)
+	case FillMean, FillMedian:
+		if numericKind(col.empty) == kindNone {
+			return nil, errors.New("can't compute the mean or median of non numeric elements")
+		}
+		var f float64
+		if opts.Strategy == FillMean {
+			f, err = Mean(col.cells)
+		} else {
+			f, err = Median(col.cells)
+		}
+		if err == nil {
+			fill, err = numericColumnValue(f, col)
+		}
+	case FillMode:
+		counts, _ := valueCounts(col.cells, false)
+		best := 0
+		for _, v := range counts {
+			if v.count > best {
+				fill, best = v.value, v.count
+			}
+		}
+		if fill == nil {
+			fill = col.empty
+		}
+	default:
+		return nil, errors.New("unknown fill strategy")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for i, c := range cells {
+		if c.IsNA() {
+			cells[i] = fill.Copy()
+		}
+	}
+	return cells, nil
+}
+
+// columnValue converts the given value into a Cell of the type of the column
+func columnValue(v interface{}, col column) (Cell, error) {
+	if s, ok := v.(string); ok {
+		if e, ok := col.empty.(Categorical); ok {
+			c := encodeCategoricals([]*string{&s}, e.lvl)[0]
+			if c.IsNA() {
+				return nil, errors.New("unknown level: " + s)
+			}
+			return c, nil
+		}
+	}
+	c, err := valueCell(v, col.colType)
+	if err != nil {
+		return nil, err
+	}
+	if numericKind(col.empty) != kindNone && numericKind(c) != kindNone && !c.IsNA() {
+		f, err := c.Float()
+		if err != nil {
+			return nil, err
+		}
+		if numericKind(c) == kindFloat || numericKind(col.empty) == kindDecimal {
+			return numericColumnValue(*f, col)
+		}
+		if c, err = castCell(c, col.colType); err != nil {
+			return nil, err
+		}
+	}
+	if t := reflect.TypeOf(c).String(); t != col.colType {
+		return nil, fmt.Errorf("can't fill a column of type %s with a value of type %s", col.colType, t)
+	}
+	return c, nil
+}
+
+// numericColumnValue converts a float into a Cell of the type of a numeric
+// column. Integer columns get the value rounded and decimal columns get it
+// with their scale.
+func numericColumnValue(f float64, col column) (Cell, error) {
+	if math.IsNaN(f) {
+		return col.empty, nil
+	}
+	switch numericKind(col.empty) {
+	case kindFloat:
+		return Float{&f}, nil
+	case kindDecimal:
+		return ParseDecimal(strconv.FormatFloat(f, 'f', -1, 64), decimalScale(col.empty))
+	}
+	i := int64(math.Round(f))
+	return castCell(Int64{&i}, col.colType)
+}
+
+// Interpolate returns a new DataFrame where the NA elements of the given
+// numeric columns, or of all the numeric columns if none are given, have been
+// replaced by linear interpolation between the closest non NA elements. NA
+// elements at the beginning or the end of a column are not replaced. Integer
+// columns are converted to Float and Decimal columns keep their scale.
+func (df DataFrame) Interpolate(cols ...string) (*DataFrame, error) {
+	if len(cols) == 0 {
+		for _, k := range df.Names() {
+			if numericKind(df.Columns[k].empty) != kindNone {
+				cols = append(cols, k)
+			}
+		}
+	}
+	cols, err := df.columnsOrAll(cols)
+	if err != nil {
+		return nil, err
+	}
+
+	newDf := df.copy()
+	for _, k := range cols {
+		col := df.Columns[k]
+		kind := numericKind(col.empty)
+		if kind == kindNone {
+			return nil, errors.New("Can't interpolate the non numeric column " + k)
+		}
+		if len(col.cells) == 0 {
+			if kind != kindFloat && kind != kindDecimal {
+				newDf.Columns[k] = emptyCol(k, Float{})
+			}
+			continue
+		}
+		cells := make(Cells, len(col.cells))
+		last := -1
+		for i, c := range col.cells {
+			if kind == kindFloat || kind == kindDecimal {
+				cells[i] = c
+			} else if cells[i], err = castCell(c, "df.Float"); err != nil {
+				return nil, err
+			}
+			if c.IsNA() {
+				continue
+			}
+			if last >= 0 && i-last > 1 {
+				from, _ := col.cells[last].Float()
+				to, _ := c.Float()
+				for j := last + 1; j < i; j++ {
+					f := *from + (*to-*from)*float64(j-last)/float64(i-last)
+					if kind == kindDecimal {
+						if cells[j], err = numericColumnValue(f, col); err != nil {
+							return nil, err
+						}
+					} else {
+						cells[j] = Float{&f}
+					}
+				}
+			}
+			last = i
+		}
+		newcol, err := newCol(k, cells)
+		if err != nil {
+			return nil, err
+		}
+		newDf.Columns[k] = *newcol
+	}
+	return &newDf, nil
+}
+
+// NACounts returns a DataFrame with the number of NA elements of each column
+// on the column NA and their ratio over the number of rows on the column
+// Ratio.
+func (df DataFrame) NACounts() (*DataFrame, error) {
+	names := df.Names()
+	if len(names) == 0 {
+		return nil, errors.New("Empty DataFrame")
+	}
+	counts := make([]int, 0, len(names))
+	ratios := make([]float64, 0, len(names))
+	for _, k := range names {
+		n := 0
+		for _, na := range df.Columns[k].NA() {
+			if na {
+				n++
+			}
+		}
+		counts = append(counts, n)
+		if df.nRows == 0 {
+			ratios = append(ratios, 0)
+		} else {
+			ratios = append(ratios, float64(n)/float64(df.nRows))
+		}
+	}
+	return New(
+		C{"Column", Strings(names)},
+		C{"NA", Ints(counts)},
+		C{"Ratio", Floats(ratios)},
+	)
+}
@@ -0,0 +1,200 @@
+package df
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestDataFrame_DropNA(t *testing.T) {
+	d, _ := New(
+		C{"A", Strings("a", nil, "c", nil)},
+		C{"B", Ints(1, nil, nil, 4)},
+		C{"C", Floats(1.5, nil, 3.5, nil)},
+	)
+	var tests = []struct {
+		cols     []string
+		how      string
+		thresh   int
+		expected string
+	}{
+		{nil, "any", 0, "[[A B C] [a 1 1.5]]"},
+		{nil, "all", 0, "[[A B C] [a 1 1.5] [c NA 3.5] [NA 4 NA]]"},
+		{[]string{"A", "C"}, "any", 0, "[[A B C] [a 1 1.5] [c NA 3.5]]"},
+		{[]string{"B"}, "all", 0, "[[A B C] [a 1 1.5] [NA 4 NA]]"},
+		{nil, "any", 2, "[[A B C] [a 1 1.5] [c NA 3.5]]"},
+	}
+	for k, v := range tests {
+		dd, err := d.DropNA(v.cols, v.how, v.thresh)
+		if err != nil {
+			t.Error("Test", k, ":", err)
+			continue
+		}
+		received := fmt.Sprint(dd.SaveRecords())
+		if v.expected != received {
+			t.Error(
+				"Test", k, "\n",
+				"Expected:\n",
+				v.expected, "\n",
+				"Received:\n",
+				received,
+			)
+		}
+	}
+
+	// Dropping every row keeps the columns and their types
+	e, _ := New(C{"A", Floats(nil, nil)}, C{"B", Ints(1, nil)})
+	dd, err := e.DropNA(nil, "any", 0)
+	if err != nil {
+		t.Error(err)
+	} else if dd.NRows() != 0 || fmt.Sprint(dd.Names(), dd.Types()) != "[A B] [df.Float df.Int]" {
+		t.Error("Expected an empty DataFrame with the columns [A B] [df.Float df.Int], received", dd.NRows(), "rows", dd.Names(), dd.Types())
+	}
+	if _, err := d.DropNA(nil, "some", 0); err == nil {
+		t.Error("Unknown modes should throw an error")
+	}
+	if _, err := d.DropNA([]string{"D"}, "any", 0); err == nil {
+		t.Error("Unknown columns should throw an error")
+	}
+}
+
+func TestDataFrame_FillNA(t *testing.T) {
+	levels, _ := CategoricalsWithLevels([]string{"x", "y"}, false, "x", nil, "y", "y", nil)
+	d, _ := New(
+		C{"A", Strings(nil, "b", nil, "b", "c")},
+		C{"B", Ints(1, nil, 2, nil, 4)},
+		C{"C", Decimals(2, "1.10", nil, "2.25", "2.25", nil)},
+		C{"D", levels},
+	)
+	var tests = []struct {
+		opts     FillNAOptions
+		expected string
+	}{
+		{
+			FillNAOptions{Value: "0", Columns: []string{"B", "C"}},
+			"[[A B C D] [NA 1 1.10 x] [b 0 0.00 NA] [NA 2 2.25 y] [b 0 2.25 y] [c 4 0.00 NA]]",
+		},
+		{
+			FillNAOptions{Values: map[string]interface{}{"A": "z", "B": 2.6, "D": "x"}},
+			"[[A B C D] [z 1 1.10 x] [b 3 NA x] [z 2 2.25 y] [b 3 2.25 y] [c 4 NA x]]",
+		},
+		{
+			FillNAOptions{Strategy: FillForward},
+			"[[A B C D] [NA 1 1.10 x] [b 1 1.10 x] [b 2 2.25 y] [b 2 2.25 y] [c 4 2.25 y]]",
+		},
+		{
+			FillNAOptions{Strategy: FillBackward},
+			"[[A B C D] [b 1 1.10 x] [b 2 2.25 y] [b 2 2.25 y] [b 4 2.25 y] [c 4 NA NA]]",
+		},
+		{
+			FillNAOptions{Strategy: FillMean, Columns: []string{"B", "C"}},
+			"[[A B C D] [NA 1 1.10 x] [b 2 1.87 NA] [NA 2 2.25 y] [b 2 2.25 y] [c 4 1.87 NA]]",
+		},
+		{
+			FillNAOptions{Strategy: FillMedian, Columns: []string{"C"}},
+			"[[A B C D] [NA 1 1.10 x] [b NA 2.25 NA] [NA 2 2.25 y] [b NA 2.25 y] [c 4 2.25 NA]]",
+		},
+		{
+			FillNAOptions{Strategy: FillMode},
+			"[[A B C D] [b 1 1.10 x] [b 1 2.25 y] [b 2 2.25 y] [b 1 2.25 y] [c 4 2.25 y]]",
+		},
+	}
+	for k, v := range tests {
+		dd, err := d.FillNA(v.opts)
+		if err != nil {
+			t.Error("Test", k, ":", err)
+			continue
+		}
+		received := fmt.Sprint(dd.SaveRecords())
+		if v.expected != received {
+			t.Error(
+				"Test", k, "\n",
+				"Expected:\n",
+				v.expected, "\n",
+				"Received:\n",
+				received,
+			)
+		}
+	}
+	if received := fmt.Sprint(d.Columns["B"].cells); received != "[1 NA 2 NA 4]" {
+		t.Error("Original DataFrame was modified:", received)
+	}
+
+	// Without rows the columns keep their types
+	for k, v := range tests {
+		dd, err := d.Empty().FillNA(v.opts)
+		if err != nil {
+			t.Error("Test", k, "without rows:", err)
+			continue
+		}
+		if fmt.Sprint(dd.Types()) != fmt.Sprint(d.Types()) {
+			t.Error("Test", k, "without rows: expected the types", d.Types(), "received", dd.Types())
+		}
+		if _, err := Rbind(*dd, *d); err != nil {
+			t.Error("Test", k, "without rows:", err)
+		}
+	}
+
+	var fails = []FillNAOptions{
+		{},
+		{Strategy: FillMean},
+		{Value: "z", Columns: []string{"D"}},
+		{Value: true, Columns: []string{"B"}},
+		{Value: 1, Columns: []string{"E"}},
+	}
+	for k, v := range fails {
+		if _, err := d.FillNA(v); err == nil {
+			t.Error("Test", k, "should throw an error")
+		}
+	}
+}
+
+func TestDataFrame_Interpolate(t *testing.T) {
+	d, _ := New(
+		C{"A", Ints(nil, 1, nil, nil, 4, nil)},
+		C{"B", Decimals(2, "1", nil, "2", nil, nil, "3")},
+		C{"C", Strings("a", "b", "c", "d", "e", "f")},
+	)
+	dd, err := d.Interpolate()
+	if err != nil {
+		t.Error(err)
+	}
+	expected := "[[A B C] [NA 1.00 a] [1 1.50 b] [2 2.00 c] [3 2.33 d] [4 2.67 e] [NA 3.00 f]]"
+	received := fmt.Sprint(dd.SaveRecords())
+	if expected != received || dd.Columns["A"].colType != "df.Float" {
+		t.Error(
+			"Expected:\n",
+			expected, "\n",
+			"Received:\n",
+			received, dd.Columns["A"].colType,
+		)
+	}
+	if _, err := d.Interpolate("C"); err == nil {
+		t.Error("Interpolating non numeric columns should throw an error")
+	}
+
+	// Without rows the columns get the same types as with them
+	de, err := d.Empty().Interpolate()
+	if err != nil {
+		t.Error(err)
+	} else if fmt.Sprint(de.Types()) != fmt.Sprint(dd.Types()) {
+		t.Error("Expected the types", dd.Types(), "received", de.Types())
+	} else if _, err := Rbind(*de, *dd); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestDataFrame_NACounts(t *testing.T) {
+	d, _ := New(
+		C{"A", Strings("a", nil, "c", nil)},
+		C{"B", Ints(1, 2, 3, nil)},
+	)
+	dd, err := d.NACounts()
+	if err != nil {
+		t.Error(err)
+	}
+	expected := "[[Column NA Ratio] [A 2 0.5] [B 1 0.25]]"
+	received := fmt.Sprint(dd.SaveRecords())
+	if expected != received {
+		t.Error("Expected:", expected, "Received:", received)
+	}
+}
@@ -0,0 +1,839 @@
+package df
+
+import (
+	"crypto/md5"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// errDivisionByZero is returned by the arithmetic operations when dividing by
+// zero
+var errDivisionByZero = errors.New("Division by zero")
+
+// flattenArgs expands the slices contained on args so that the typed
+// constructors can process the elements one by one
+func flattenArgs(args ...interface{}) []interface{} {
+	ret := make([]interface{}, 0, len(args))
+	for _, v := range args {
+		switch v.(type) {
+		case nil, string, []byte:
+			ret = append(ret, v)
+			continue
+		}
+		s := reflect.ValueOf(v)
+		if s.Kind() == reflect.Slice {
+			for i := 0; i < s.Len(); i++ {
+				ret = append(ret, s.Index(i).Interface())
+			}
+			continue
+		}
+		ret = append(ret, v)
+	}
+	return ret
+}
+
+// Int64 is a wrapper for int64 to be able to implement custom methods
+type Int64 struct {
+	i *int64
+}
+
+// Copy returns a copy of a given Cell
+func (i Int64) Copy() Cell {
+	if i.i == nil {
+		return Int64{nil}
+	}
+	j := *i.i
+	return Int64{&j}
+}
+
+// Int returns the integer value of Int64
+func (i Int64) Int() (*int, error) {
+	if i.i == nil || int64(int(*i.i)) != *i.i {
+		return nil, errors.New("Could't convert to int")
+	}
+	j := int(*i.i)
+	return &j, nil
+}
+
+// Float returns the float value of Int64
+func (i Int64) Float() (*float64, error) {
+	if i.i == nil {
+		return nil, errors.New("Could't convert to float")
+	}
+	f := float64(*i.i)
+	return &f, nil
+}
+
+// Bool returns the bool value of Int64
+func (i Int64) Bool() (*bool, error) {
+	if i.i == nil || (*i.i != 0 && *i.i != 1) {
+		return nil, errors.New("Can't convert to Bool")
+	}
+	b := *i.i == 1
+	return &b, nil
+}
+
+func (i Int64) String() string {
+	if i.i == nil {
+		return "NA"
+	}
+	return strconv.FormatInt(*i.i, 10)
+}
+
+// Checksum generates a pseudo-unique 16 byte array
+func (i Int64) Checksum() [16]byte {
+	return md5.Sum([]byte(i.String() + "Int64"))
+}
+
+// NA returns the empty element for this type
+func (i Int64) NA() Cell {
+	return Int64{nil}
+}
+
+// IsNA returns true if the element is empty and viceversa
+func (i Int64) IsNA() bool {
+	return i.i == nil
+}
+
+// Add returns the sum of two Int64. An error is returned on overflow.
+func (i Int64) Add(j Int64) (Int64, error) {
+	if i.i == nil || j.i == nil {
+		return Int64{nil}, nil
+	}
+	r := *i.i + *j.i
+	if (r > *i.i) != (*j.i > 0) {
+		return Int64{nil}, errors.New("Int64 overflow")
+	}
+	return Int64{&r}, nil
+}
+
+// Sub returns the difference of two Int64. An error is returned on overflow.
+func (i Int64) Sub(j Int64) (Int64, error) {
+	if i.i == nil || j.i == nil {
+		return Int64{nil}, nil
+	}
+	r := *i.i - *j.i
+	if (r < *i.i) != (*j.i > 0) {
+		return Int64{nil}, errors.New("Int64 overflow")
+	}
+	return Int64{&r}, nil
+}
+
+// Mul returns the product of two Int64. An error is returned on overflow.
+func (i Int64) Mul(j Int64) (Int64, error) {
+	if i.i == nil || j.i == nil {
+		return Int64{nil}, nil
+	}
+	r := *i.i * *j.i
+	if *i.i != 0 && (r/(*i.i) != *j.i || (*i.i == -1 && *j.i == math.MinInt64)) {
+		return Int64{nil}, errors.New("Int64 overflow")
+	}
+	return Int64{&r}, nil
+}
+
+// Div returns the integer quotient of two Int64
+func (i Int64) Div(j Int64) (Int64, error) {
+	if i.i == nil || j.i == nil {
+		return Int64{nil}, nil
+	}
+	if *j.i == 0 {
+		return Int64{nil}, errDivisionByZero
+	}
+	if *i.i == math.MinInt64 && *j.i == -1 {
+		return Int64{nil}, errors.New("Int64 overflow")
+	}
+	r := *i.i / *j.i
+	return Int64{&r}, nil
+}
+
+// Mod returns the remainder of the division of two Int64
+func (i Int64) Mod(j Int64) (Int64, error) {
+	if i.i == nil || j.i == nil {
+		return Int64{nil}, nil
+	}
+	if *j.i == 0 {
+		return Int64{nil}, errDivisionByZero
+	}
+	if *j.i == -1 {
+		r := int64(0)
+		return Int64{&r}, nil
+	}
+	r := *i.i % *j.i
+	return Int64{&r}, nil
+}
+
+// Cmp compares two non NA Int64 returning -1, 0 or 1
+func (i Int64) Cmp(j Int64) int {
+	switch {
+	case *i.i < *j.i:
+		return -1
+	case *i.i > *j.i:
+		return 1
+	}
+	return 0
+}
+
+// Int64s is a constructor for an Int64 array
+func Int64s(args ...interface{}) Cells {
+	elems := flattenArgs(args...)
+	ret := make(Cells, 0, len(elems))
+	for _, v := range elems {
+		var i int64
+		var err error
+		switch e := v.(type) {
+		case nil:
+			err = errors.New("NA")
+		case int:
+			i = int64(e)
+		case int64:
+			i = e
+		case int32:
+			i = int64(e)
+		case uint64:
+			if e > math.MaxInt64 {
+				err = errors.New("Int64 overflow")
+			}
+			i = int64(e)
+		case float64:
+			if math.IsNaN(e) || e >= math.MaxInt64 || e < math.MinInt64 {
+				err = errors.New("Int64 overflow")
+			}
+			i = int64(e)
+		case bool:
+			if e {
+				i = 1
+			}
+		case string:
+			i, err = strconv.ParseInt(e, 10, 64)
+		case Cell:
+			i, err = cellInt64(e)
+		default:
+			err = errors.New("Can't convert to Int64")
+		}
+		if err != nil {
+			ret = append(ret, Int64{nil})
+		} else {
+			j := i
+			ret = append(ret, Int64{&j})
+		}
+	}
+	return ret
+}
+
+// cellInt64 converts a Cell to int64
+func cellInt64(c Cell) (int64, error) {
+	if c.IsNA() {
+		return 0, errors.New("NA")
+	}
+	switch e := c.(type) {
+	case Int64:
+		return *e.i, nil
+	case Uint64:
+		if *e.u > math.MaxInt64 {
+			return 0, errors.New("Int64 overflow")
+		}
+		return int64(*e.u), nil
+	case Decimal:
+		q := new(big.Int).Quo(e.v, pow10(e.scale))
+		if !q.IsInt64() {
+			return 0, errors.New("Int64 overflow")
+		}
+		return q.Int64(), nil
+	case Float:
+		return int64(*e.f), nil
+	}
+	if i, err := strconv.ParseInt(c.String(), 10, 64); err == nil {
+		return i, nil
+	}
+	i, err := c.Int()
+	if err != nil {
+		return 0, err
+	}
+	return int64(*i), nil
+}
+
+// Uint64 is a wrapper for uint64 to be able to implement custom methods
+type Uint64 struct {
+	u *uint64
+}
+
+// Copy returns a copy of a given Cell
+func (u Uint64) Copy() Cell {
+	if u.u == nil {
+		return Uint64{nil}
+	}
+	j := *u.u
+	return Uint64{&j}
+}
+
+// Int returns the integer value of Uint64
+func (u Uint64) Int() (*int, error) {
+	if u.u == nil {
+		return nil, errors.New("Could't convert to int")
+	}
+	j := int(*u.u)
+	if j < 0 || uint64(j) != *u.u {
+		return nil, errors.New("Could't convert to int")
+	}
+	return &j, nil
+}
+
+// Float returns the float value of Uint64
+func (u Uint64) Float() (*float64, error) {
+	if u.u == nil {
+		return nil, errors.New("Could't convert to float")
+	}
+	f := float64(*u.u)
+	return &f, nil
+}
+
+// Bool returns the bool value of Uint64
+func (u Uint64) Bool() (*bool, error) {
+	if u.u == nil || *u.u > 1 {
+		return nil, errors.New("Can't convert to Bool")
+	}
+	b := *u.u == 1
+	return &b, nil
+}
+
+func (u Uint64) String() string {
+	if u.u == nil {
+		return "NA"
+	}
+	return strconv.FormatUint(*u.u, 10)
+}
+
+// Checksum generates a pseudo-unique 16 byte array
+func (u Uint64) Checksum() [16]byte {
+	return md5.Sum([]byte(u.String() + "Uint64"))
+}
+
+// NA returns the empty element for this type
+func (u Uint64) NA() Cell {
+	return Uint64{nil}
+}
+
+// IsNA returns true if the element is empty and viceversa
+func (u Uint64) IsNA() bool {
+	return u.u == nil
+}
+
+// Add returns the sum of two Uint64. An error is returned on overflow.
+func (u Uint64) Add(v Uint64) (Uint64, error) {
+	if u.u == nil || v.u == nil {
+		return Uint64{nil}, nil
+	}
+	r := *u.u + *v.u
+	if r < *u.u {
+		return Uint64{nil}, errors.New("Uint64 overflow")
+	}
+	return Uint64{&r}, nil
+}
+
+// Sub returns the difference of two Uint64. An error is returned if the
+// result would be negative.
+func (u Uint64) Sub(v Uint64) (Uint64, error) {
+	if u.u == nil || v.u == nil {
+		return Uint64{nil}, nil
+	}
+	if *v.u > *u.u {
+		return Uint64{nil}, errors.New("Uint64 overflow")
+	}
+	r := *u.u - *v.u
+	return Uint64{&r}, nil
+}
+
+// Mul returns the product of two Uint64. An error is returned on overflow.
+func (u Uint64) Mul(v Uint64) (Uint64, error) {
+	if u.u == nil || v.u == nil {
+		return Uint64{nil}, nil
+	}
+	r := *u.u * *v.u
+	if *u.u != 0 && r/(*u.u) != *v.u {
+		return Uint64{nil}, errors.New("Uint64 overflow")
+	}
+	return Uint64{&r}, nil
+}
+
+// Div returns the integer quotient of two Uint64
+func (u Uint64) Div(v Uint64) (Uint64, error) {
+	if u.u == nil || v.u == nil {
+		return Uint64{nil}, nil
+	}
+	if *v.u == 0 {
+		return Uint64{nil}, errDivisionByZero
+	}
+	r := *u.u / *v.u
+	return Uint64{&r}, nil
+}
+
+// Mod returns the remainder of the division of two Uint64
+func (u Uint64) Mod(v Uint64) (Uint64, error) {
+	if u.u == nil || v.u == nil {
+		return Uint64{nil}, nil
+	}
+	if *v.u == 0 {
+		return Uint64{nil}, errDivisionByZero
+	}
+	r := *u.u % *v.u
+	return Uint64{&r}, nil
+}
+
+// Cmp compares two non NA Uint64 returning -1, 0 or 1
+func (u Uint64) Cmp(v Uint64) int {
+	switch {
+	case *u.u < *v.u:
+		return -1
+	case *u.u > *v.u:
+		return 1
+	}
+	return 0
+}
+
+// Uint64s is a constructor for an Uint64 array
+func Uint64s(args ...interface{}) Cells {
+	elems := flattenArgs(args...)
+	ret := make(Cells, 0, len(elems))
+	for _, v := range elems {
+		var u uint64
+		var err error
+		switch e := v.(type) {
+		case nil:
+			err = errors.New("NA")
+		case int:
+			if e < 0 {
+				err = errors.New("Negative value")
+			}
+			u = uint64(e)
+		case int64:
+			if e < 0 {
+				err = errors.New("Negative value")
+			}
+			u = uint64(e)
+		case uint64:
+			u = e
+		case uint:
+			u = uint64(e)
+		case float64:
+			if math.IsNaN(e) || e < 0 || e >= math.MaxUint64 {
+				err = errors.New("Uint64 overflow")
+			}
+			u = uint64(e)
+		case bool:
+			if e {
+				u = 1
+			}
+		case string:
+			u, err = strconv.ParseUint(e, 10, 64)
+		case Cell:
+			if e.IsNA() {
+				err = errors.New("NA")
+			} else if c, ok := e.(Uint64); ok {
+				u = *c.u
+			} else if p, perr := strconv.ParseUint(e.String(), 10, 64); perr == nil {
+				u = p
+			} else {
+				var i int64
+				i, err = cellInt64(e)
+				if err == nil && i < 0 {
+					err = errors.New("Negative value")
+				}
+				u = uint64(i)
+			}
+		default:
+			err = errors.New("Can't convert to Uint64")
+		}
+		if err != nil {
+			ret = append(ret, Uint64{nil})
+		} else {
+			j := u
+			ret = append(ret, Uint64{&j})
+		}
+	}
+	return ret
+}
+
+// Decimal is a fixed point number. It stores an unscaled integer value v and
+// the number of decimal digits (scale) so that the value represented is
+// v / 10^scale. All the cells of a Decimal column share the same scale.
+type Decimal struct {
+	v     *big.Int
+	scale int
+}
+
+// ParseDecimal parses the string representation of a decimal number with the
+// given scale. Digits beyond the scale are rounded half away from zero. If
+// scale is negative it will be inferred from the number of decimal digits of
+// the string.
+func ParseDecimal(s string, scale int) (Decimal, error) {
+	str := strings.TrimSpace(s)
+	neg := false
+	if strings.HasPrefix(str, "-") || strings.HasPrefix(str, "+") {
+		neg = str[0] == '-'
+		str = str[1:]
+	}
+	intPart, fracPart := str, ""
+	if idx := strings.Index(str, "."); idx >= 0 {
+		intPart, fracPart = str[:idx], str[idx+1:]
+	}
+	if intPart == "" && fracPart == "" {
+		return Decimal{}, errors.New("Invalid decimal: " + s)
+	}
+	for _, r := range intPart + fracPart {
+		if r < '0' || r > '9' {
+			return Decimal{}, errors.New("Invalid decimal: " + s)
+		}
+	}
+	if scale < 0 {
+		scale = len(fracPart)
+	}
+
+	round := false
+	if len(fracPart) > scale {
+		round = fracPart[scale] >= '5'
+		fracPart = fracPart[:scale]
+	} else {
+		fracPart += strings.Repeat("0", scale-len(fracPart))
+	}
+	v, ok := new(big.Int).SetString("0"+intPart+fracPart, 10)
+	if !ok {
+		return Decimal{}, errors.New("Invalid decimal: " + s)
+	}
+	if round {
+		v.Add(v, big.NewInt(1))
+	}
+	if neg {
+		v.Neg(v)
+	}
+	return Decimal{v, scale}, nil
+}
+
+// pow10 returns 10^n as a big.Int
+func pow10(n int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
+
+// divRound divides a by b rounding half away from zero
+func divRound(a, b *big.Int) *big.Int {
+	q, r := new(big.Int).QuoRem(a, b, new(big.Int))
+	r.Abs(r).Mul(r, big.NewInt(2))
+	if r.Cmp(new(big.Int).Abs(b)) >= 0 {
+		if a.Sign()*b.Sign() < 0 {
+			q.Sub(q, big.NewInt(1))
+		} else {
+			q.Add(q, big.NewInt(1))
+		}
+	}
+	return q
+}
+
+// Scale returns the number of decimal digits of the Decimal
+func (d Decimal) Scale() int {
+	return d.scale
+}
+
+// Rescale returns the Decimal with the given scale, rounding half away from
+// zero if digits have to be dropped.
+func (d Decimal) Rescale(scale int) Decimal {
+	if d.v == nil {
+		return Decimal{nil, scale}
+	}
+	switch {
+	case scale > d.scale:
+		v := new(big.Int).Mul(d.v, pow10(scale-d.scale))
+		return Decimal{v, scale}
+	case scale < d.scale:
+		return Decimal{divRound(d.v, pow10(d.scale-scale)), scale}
+	}
+	return Decimal{new(big.Int).Set(d.v), scale}
+}
+
+// Copy returns a copy of a given Cell
+func (d Decimal) Copy() Cell {
+	if d.v == nil {
+		return Decimal{nil, d.scale}
+	}
+	return Decimal{new(big.Int).Set(d.v), d.scale}
+}
+
+// Int returns the integer part of the Decimal
+func (d Decimal) Int() (*int, error) {
+	if d.v == nil {
+		return nil, errors.New("Could't convert to int")
+	}
+	q := new(big.Int).Quo(d.v, pow10(d.scale))
+	if !q.IsInt64() || int64(int(q.Int64())) != q.Int64() {
+		return nil, errors.New("Could't convert to int")
+	}
+	i := int(q.Int64())
+	return &i, nil
+}
+
+// Float returns the float value of Decimal
+func (d Decimal) Float() (*float64, error) {
+	if d.v == nil {
+		return nil, errors.New("Could't convert to float64")
+	}
+	f, err := strconv.ParseFloat(d.String(), 64)
+	if err != nil {
+		return nil, err
+	}
+	return &f, nil
+}
+
+// Bool returns the bool value of Decimal
+func (d Decimal) Bool() (*bool, error) {
+	if d.v == nil {
+		return nil, errors.New("Can't convert to Bool")
+	}
+	if d.v.Sign() == 0 {
+		f := false
+		return &f, nil
+	}
+	if d.v.Cmp(pow10(d.scale)) == 0 {
+		t := true
+		return &t, nil
+	}
+	return nil, errors.New("Can't convert to Bool")
+}
+
+func (d Decimal) String() string {
+	if d.v == nil {
+		return "NA"
+	}
+	s := new(big.Int).Abs(d.v).String()
+	if d.scale > 0 {
+		if len(s) <= d.scale {
+			s = strings.Repeat("0", d.scale-len(s)+1) + s
+		}
+		s = s[:len(s)-d.scale] + "." + s[len(s)-d.scale:]
+	}
+	if d.v.Sign() < 0 {
+		s = "-" + s
+	}
+	return s
+}
+
+// Checksum generates a pseudo-unique 16 byte array. Decimals that represent
+// the same number with a different scale share the same checksum.
+func (d Decimal) Checksum() [16]byte {
+	s := d.String()
+	if d.v != nil && strings.Contains(s, ".") {
+		s = strings.TrimRight(strings.TrimRight(s, "0"), ".")
+	}
+	return md5.Sum([]byte(s + "Decimal"))
+}
+
+// NA returns the empty element for this type
+func (d Decimal) NA() Cell {
+	return Decimal{nil, d.scale}
+}
+
+// IsNA returns true if the element is empty and viceversa
+func (d Decimal) IsNA() bool {
+	return d.v == nil
+}
+
+// align returns both Decimals with the same scale
+func (d Decimal) align(e Decimal) (*big.Int, *big.Int, int) {
+	scale := d.scale
+	if e.scale > scale {
+		scale = e.scale
+	}
+	return d.Rescale(scale).v, e.Rescale(scale).v, scale
+}
+
+// Add returns the sum of two Decimals with the biggest of their scales
+func (d Decimal) Add(e Decimal) (Decimal, error) {
+	if d.v == nil || e.v == nil {
+		return Decimal{nil, maxInt(d.scale, e.scale)}, nil
+	}
+	a, b, scale := d.align(e)
+	return Decimal{a.Add(a, b), scale}, nil
+}
+
+// Sub returns the difference of two Decimals with the biggest of their scales
+func (d Decimal) Sub(e Decimal) (Decimal, error) {
+	if d.v == nil || e.v == nil {
+		return Decimal{nil, maxInt(d.scale, e.scale)}, nil
+	}
+	a, b, scale := d.align(e)
+	return Decimal{a.Sub(a, b), scale}, nil
+}
+
+// Mul returns the product of two Decimals rounded to the biggest of their
+// scales
+func (d Decimal) Mul(e Decimal) (Decimal, error) {
+	scale := maxInt(d.scale, e.scale)
+	if d.v == nil || e.v == nil {
+		return Decimal{nil, scale}, nil
+	}
+	v := new(big.Int).Mul(d.v, e.v)
+	return Decimal{divRound(v, pow10(d.scale+e.scale-scale)), scale}, nil
+}
+
+// Div returns the quotient of two Decimals rounded to the biggest of their
+// scales
+func (d Decimal) Div(e Decimal) (Decimal, error) {
+	scale := maxInt(d.scale, e.scale)
+	if d.v == nil || e.v == nil {
+		return Decimal{nil, scale}, nil
+	}
+	if e.v.Sign() == 0 {
+		return Decimal{nil, scale}, errDivisionByZero
+	}
+	v := new(big.Int).Mul(d.v, pow10(scale+e.scale-d.scale))
+	return Decimal{divRound(v, e.v), scale}, nil
+}
+
+// Mod returns the remainder of the truncated division of two Decimals
+func (d Decimal) Mod(e Decimal) (Decimal, error) {
+	if d.v == nil || e.v == nil {
+		return Decimal{nil, maxInt(d.scale, e.scale)}, nil
+	}
+	if e.v.Sign() == 0 {
+		return Decimal{nil, maxInt(d.scale, e.scale)}, errDivisionByZero
+	}
+	a, b, scale := d.align(e)
+	return Decimal{a.Rem(a, b), scale}, nil
+}
+
+// Cmp compares two non NA Decimals returning -1, 0 or 1
+func (d Decimal) Cmp(e Decimal) int {
+	a, b, _ := d.align(e)
+	return a.Cmp(b)
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+// Decimals is a constructor for a Decimal array with the given scale. If
+// scale is negative it will be inferred as the biggest number of decimal
+// digits of the given elements.
+func Decimals(scale int, args ...interface{}) Cells {
+	elems := flattenArgs(args...)
+	strs := make([]*string, 0, len(elems))
+	inferred := 0
+	for _, v := range elems {
+		var s string
+		switch e := v.(type) {
+		case nil:
+			strs = append(strs, nil)
+			continue
+		case string:
+			s = e
+		case float64:
+			s = strconv.FormatFloat(e, 'f', -1, 64)
+		case bool:
+			s = "0"
+			if e {
+				s = "1"
+			}
+		case Cell:
+			if e.IsNA() {
+				strs = append(strs, nil)
+				continue
+			}
+			s = e.String()
+		default:
+			s = fmt.Sprint(e)
+		}
+		if d, err := ParseDecimal(s, -1); err == nil && d.scale > inferred {
+			inferred = d.scale
+		}
+		strs = append(strs, &s)
+	}
+	if scale < 0 {
+		scale = inferred
+	}
+
+	ret := make(Cells, 0, len(strs))
+	for _, s := range strs {
+		if s == nil {
+			ret = append(ret, Decimal{nil, scale})
+			continue
+		}
+		d, err := ParseDecimal(*s, scale)
+		if err != nil {
+			ret = append(ret, Decimal{nil, scale})
+			continue
+		}
+		ret = append(ret, d)
+	}
+	return ret
+}
+
+// decimalType returns the CellType for Decimals with the given scale. A negative
+// scale means that the scale is inferred from the data.
+func decimalType(scale int) CellType {
+	return CellType{
+		Name:  "decimal",
+		Empty: Decimal{},
+		Parse: func(s string) (Cell, error) {
+			return ParseDecimal(s, scale)
+		},
+		Compare: func(a, b Cell) int {
+			return a.(Decimal).Cmp(b.(Decimal))
+		},
+		convert: func(cells Cells) Cells { return Decimals(scale, cells) },
+	}
+}
+
+func init() {
+	builtins := []CellType{
+		{
+			Name:  "int64",
+			Empty: Int64{},
+			Parse: func(s string) (Cell, error) {
+				i, err := strconv.ParseInt(s, 10, 64)
+				if err != nil {
+					return nil, err
+				}
+				return Int64{&i}, nil
+			},
+			Compare: func(a, b Cell) int {
+				return a.(Int64).Cmp(b.(Int64))
+			},
+			convert: func(cells Cells) Cells { return Int64s(cells) },
+		},
+		{
+			Name:  "uint64",
+			Empty: Uint64{},
+			Parse: func(s string) (Cell, error) {
+				u, err := strconv.ParseUint(s, 10, 64)
+				if err != nil {
+					return nil, err
+				}
+				return Uint64{&u}, nil
+			},
+			Compare: func(a, b Cell) int {
+				return a.(Uint64).Cmp(b.(Uint64))
+			},
+			convert: func(cells Cells) Cells { return Uint64s(cells) },
+		},
+		decimalType(-1),
+	}
+	for _, t := range builtins {
+		if err := registry.register(t); err != nil {
+			panic(err)
+		}
+	}
+
+	// Decimals with a fixed scale can be requested as decimal(scale)
+	registry.registerParametric("decimal", func(arg string) (CellType, error) {
+		scale, err := strconv.Atoi(arg)
+		if err != nil || scale < 0 {
+			return CellType{}, errors.New("Invalid decimal scale: " + arg)
+		}
+		return decimalType(scale), nil
+	})
+}
@@ -0,0 +1,174 @@
+package df
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+func TestInt64s(t *testing.T) {
+	a := Int64s("9223372036854775807", 1, []int64{2, 3}, nil, "A", uint64(math.MaxUint64))
+	expected := "[9223372036854775807 1 2 3 NA NA NA]"
+	received := fmt.Sprint(a)
+	if expected != received {
+		t.Error(
+			"Int64 elements not being propery inserted\n",
+			"Expected:\n",
+			expected, "\n",
+			"Received:\n",
+			received,
+		)
+	}
+
+	max := a[0].(Int64)
+	one := a[1].(Int64)
+	if _, err := max.Add(one); err == nil {
+		t.Error("Int64 overflow not detected on Add")
+	}
+	if _, err := max.Mul(a[2].(Int64)); err == nil {
+		t.Error("Int64 overflow not detected on Mul")
+	}
+	if _, err := one.Div(Int64s(0)[0].(Int64)); err == nil {
+		t.Error("Division by zero not detected")
+	}
+	r, err := a[3].(Int64).Mod(a[2].(Int64))
+	if err != nil || r.String() != "1" {
+		t.Error("Expected 3 % 2 == 1, received:", r, err)
+	}
+	r, _ = one.Add(a[4].(Int64))
+	if !r.IsNA() {
+		t.Error("NA not being propagated:", r)
+	}
+}
+
+func TestUint64s(t *testing.T) {
+	a := Uint64s("18446744073709551615", -1, 2, Ints(3, nil))
+	expected := "[18446744073709551615 NA 2 3 NA]"
+	received := fmt.Sprint(a)
+	if expected != received {
+		t.Error(
+			"Uint64 elements not being propery inserted\n",
+			"Expected:\n",
+			expected, "\n",
+			"Received:\n",
+			received,
+		)
+	}
+	if _, err := a[0].(Uint64).Add(a[2].(Uint64)); err == nil {
+		t.Error("Uint64 overflow not detected on Add")
+	}
+	if _, err := a[2].(Uint64).Sub(a[3].(Uint64)); err == nil {
+		t.Error("Negative Uint64 not detected on Sub")
+	}
+}
+
+func TestParseDecimal(t *testing.T) {
+	var tests = []struct {
+		str      string
+		scale    int
+		expected string
+		err      bool
+	}{
+		{"018.20", 2, "18.20", false},
+		{"1.005", 2, "1.01", false},
+		{"-1.005", 2, "-1.01", false},
+		{"-0.5", 0, "-1", false},
+		{".5", -1, "0.5", false},
+		{"12", 3, "12.000", false},
+		{"1e3", 2, "", true},
+		{"", 2, "", true},
+	}
+	for k, v := range tests {
+		d, err := ParseDecimal(v.str, v.scale)
+		if (err != nil) != v.err {
+			t.Error("Test", k, "unexpected error:", err)
+			continue
+		}
+		if !v.err && d.String() != v.expected {
+			t.Error("Test", k, "Expected:", v.expected, "Received:", d.String())
+		}
+	}
+}
+
+func TestDecimal_Arithmetic(t *testing.T) {
+	a, _ := ParseDecimal("321.31", 2)
+	b, _ := ParseDecimal("3", 0)
+	var tests = []struct {
+		f        func(Decimal) (Decimal, error)
+		expected string
+	}{
+		{a.Add, "324.31"},
+		{a.Sub, "318.31"},
+		{a.Mul, "963.93"},
+		{a.Div, "107.10"},
+		{a.Mod, "0.31"},
+	}
+	for k, v := range tests {
+		r, err := v.f(b)
+		if err != nil {
+			t.Error("Test", k, ":", err)
+		}
+		if r.String() != v.expected {
+			t.Error("Test", k, "Expected:", v.expected, "Received:", r.String())
+		}
+	}
+	if _, err := a.Div(Decimal{}.NA().(Decimal)); err != nil {
+		t.Error("Division by NA should return NA without error")
+	}
+	zero, _ := ParseDecimal("0.00", 2)
+	if _, err := a.Div(zero); err == nil {
+		t.Error("Division by zero not detected")
+	}
+
+	c, _ := ParseDecimal("321.3100", 4)
+	if a.Cmp(c) != 0 || a.Checksum() != c.Checksum() {
+		t.Error("Same decimals with different scales should be equal")
+	}
+}
+
+func TestDataFrame_ParseNumericTypes(t *testing.T) {
+	data := [][]string{
+		[]string{"Id", "Amount", "Count"},
+		[]string{"01234", "112.10", "18446744073709551615"},
+		[]string{"54320", "321.315", "1"},
+		[]string{"", "018.2", "-1"},
+	}
+	d := DataFrame{}
+	err := d.LoadAndParse(data, T{"Id": "int64", "Amount": "decimal(2)", "Count": "uint64"})
+	if err != nil {
+		t.Error(err)
+	}
+	expected := "[[Id Amount Count] [1234 112.10 18446744073709551615] [54320 321.32 1] [NA 18.20 NA]]"
+	received := fmt.Sprint(d.SaveRecords())
+	if expected != received {
+		t.Error(
+			"Numeric types not being parsed properly\n",
+			"Expected:\n",
+			expected, "\n",
+			"Received:\n",
+			received,
+		)
+	}
+
+	// The scale of the decimals is inferred if not given
+	d.LoadAndParse(data, T{"Amount": "decimal"})
+	expected = "[112.100 321.315 18.200]"
+	received = fmt.Sprint(d.Columns["Amount"].cells)
+	if expected != received {
+		t.Error("Expected:", expected, "Received:", received)
+	}
+
+	// Decimals and 64 bit integers can be used on conditions
+	d.LoadAndParse(data, T{"Id": "int64", "Amount": "decimal(2)"})
+	dc, err := d.ConditionRows(NewCondition([]string{"Amount > 112.1", "Id >= 54320"}))
+	if err != nil {
+		t.Error(err)
+	}
+	if dc.NRows() != 1 {
+		t.Error("Expected 1 row, received:", dc.NRows())
+	}
+
+	if err := d.Parse(T{"Amount": "decimal(x)"}); err == nil {
+		t.Error("Invalid decimal scale should throw an error")
+	}
+}
@@ -0,0 +1,316 @@
+package df
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// resampleRule divides the time in consecutive buckets. bucket returns the
+// label of the bucket that contains t and next the label of the following
+// bucket.
+type resampleRule struct {
+	bucket func(t time.Time) time.Time
+	next   func(label time.Time) time.Time
+}
+
+var ruleRegexp = regexp.MustCompile(`^([0-9]*)(s|min|h|D|W|MS|M|YS|Y)$`)
+
+// unixEpoch is the origin of the buckets of fixed duration
+var unixEpoch = time.Unix(0, 0).UTC()
+
+// parseRule parses a resampling rule. Supported rules are:
+//   - "s", "min" and "h", optionally preceded by a multiple, like "15min".
+//     Buckets are aligned to the Unix epoch.
+//   - "D" for calendar days. Multiples of days, like "3D", are buckets of
+//     fixed duration aligned to the Unix epoch.
+//   - "W" for weeks starting on Monday, labelled by their Monday.
+//   - "MS" and "M" for calendar months, labelled by their first or last day.
+//   - "YS" and "Y" for calendar years, labelled by their first or last day.
+func parseRule(rule string) (resampleRule, error) {
+	m := ruleRegexp.FindStringSubmatch(rule)
+	if m == nil {
+		return resampleRule{}, errors.New("Unknown resampling rule: " + rule)
+	}
+	n := 1
+	if m[1] != "" {
+		var err error
+		if n, err = strconv.Atoi(m[1]); err != nil || n <= 0 {
+			return resampleRule{}, errors.New("Invalid resampling rule: " + rule)
+		}
+	}
+
+	var d time.Duration
+	switch m[2] {
+	case "s":
+		d = time.Second
+	case "min":
+		d = time.Minute
+	case "h":
+		d = time.Hour
+	case "D":
+		if n != 1 {
+			d = 24 * time.Hour
+		}
+	}
+	if d != 0 {
+		d *= time.Duration(n)
+		return resampleRule{
+			bucket: func(t time.Time) time.Time {
+				// Time.Truncate would align the buckets to the zero time
+				offset := t.Sub(unixEpoch)
+				start := offset - offset%d
+				if start > offset {
+					start -= d
+				}
+				return unixEpoch.Add(start).In(t.Location())
+			},
+			next: func(t time.Time) time.Time { return t.Add(d) },
+		}, nil
+	}
+	if n != 1 {
+		return resampleRule{}, errors.New("Calendar rules don't accept multiples: " + rule)
+	}
+
+	monthStart := func(t time.Time) time.Time {
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+	}
+	yearStart := func(t time.Time) time.Time {
+		return time.Date(t.Year(), 1, 1, 0, 0, 0, 0, t.Location())
+	}
+	switch m[2] {
+	case "D":
+		return resampleRule{
+			bucket: truncateDay,
+			next:   func(t time.Time) time.Time { return t.AddDate(0, 0, 1) },
+		}, nil
+	case "W":
+		return resampleRule{
+			bucket: func(t time.Time) time.Time {
+				days := (int(t.Weekday()) + 6) % 7
+				return truncateDay(t).AddDate(0, 0, -days)
+			},
+			next: func(t time.Time) time.Time { return t.AddDate(0, 0, 7) },
+		}, nil
+	case "MS":
+		return resampleRule{
+			bucket: monthStart,
+			next:   func(t time.Time) time.Time { return t.AddDate(0, 1, 0) },
+		}, nil
+	case "M":
+		return resampleRule{
+			bucket: func(t time.Time) time.Time { return monthStart(t).AddDate(0, 1, -1) },
+			next:   func(t time.Time) time.Time { return monthStart(t).AddDate(0, 2, -1) },
+		}, nil
+	case "YS":
+		return resampleRule{
+			bucket: yearStart,
+			next:   func(t time.Time) time.Time { return t.AddDate(1, 0, 0) },
+		}, nil
+	}
+	return resampleRule{
+		bucket: func(t time.Time) time.Time { return yearStart(t).AddDate(1, 0, -1) },
+		next:   func(t time.Time) time.Time { return yearStart(t).AddDate(2, 0, -1) },
+	}, nil
+}
+
+// DateRange returns a DataFrame with a single Time column colname containing
+// the labels of the buckets of the given rule between start and end, both
+// included. It can be used as a calendar to join with other DataFrames.
+func DateRange(colname string, start, end time.Time, rule string) (*DataFrame, error) {
+	r, err := parseRule(rule)
+	if err != nil {
+		return nil, err
+	}
+	if end.Before(start) {
+		return nil, errors.New("The end of the range is before its start")
+	}
+	t := r.bucket(start)
+	if t.Before(start) {
+		t = r.next(t)
+	}
+	times := []time.Time{}
+	for ; !t.After(end); t = r.next(t) {
+		times = append(times, t)
+	}
+	if len(times) == 0 {
+		return nil, errors.New("Empty date range")
+	}
+	return New(C{colname, Times(times)})
+}
+
+// Aggregate describes the aggregation of a column. The result is stored on
+// the column Name, by default the name of the column followed by the name of
+// the aggregation.
+type Aggregate struct {
+	Column string
+	Agg    Aggregation
+	Name   string
+}
+
+// name returns the column where the result of the aggregation is stored
+func (a Aggregate) name() string {
+	if a.Name != "" {
+		return a.Name
+	}
+	return a.Column + "_" + a.Agg.String()
+}
+
+// apply aggregates the given elements of a column. First and Last keep the
+// type of the column, Count returns an Int and the rest of aggregations a
+// Float computed over the non NA numeric elements.
+func (a Aggregate) apply(col column, rows []int) (Cell, error) {
+	cells := make(Cells, 0, len(rows))
+	for _, i := range rows {
+		if !col.cells[i].IsNA() {
+			cells = append(cells, col.cells[i])
+		}
+	}
+	switch a.Agg {
+	case AggFirst, AggLast:
+		if len(cells) == 0 {
+			return col.empty.NA(), nil
+		}
+		if a.Agg == AggFirst {
+			return cells[0], nil
+		}
+		return cells[len(cells)-1], nil
+	case AggCount:
+		n := len(cells)
+		return Int{&n}, nil
+	}
+	values, err := numericValues(cells)
+	if err != nil {
+		return nil, fmt.Errorf("column %s: %v", a.Column, err)
+	}
+	f := a.Agg.aggregate(values)
+	if len(values) == 0 || math.IsNaN(f) {
+		return Float{nil}, nil
+	}
+	return Float{&f}, nil
+}
+
+// timeColumn returns the elements of a Time column sorted in time, together
+// with the indexes of their rows. NA elements are ignored.
+func (df DataFrame) timeColumn(colname string) ([]time.Time, []int, error) {
+	col, err := df.col(colname)
+	if err != nil {
+		return nil, nil, err
+	}
+	if _, ok := col.empty.(Time); !ok {
+		return nil, nil, errors.New("Not a Time column: " + colname)
+	}
+	rows := []int{}
+	for i, c := range col.cells {
+		if !c.IsNA() {
+			rows = append(rows, i)
+		}
+	}
+	if len(rows) == 0 {
+		return nil, nil, errors.New("No times on column " + colname)
+	}
+	sort.SliceStable(rows, func(i, j int) bool {
+		return col.cells[rows[i]].(Time).t.Before(*col.cells[rows[j]].(Time).t)
+	})
+	times := make([]time.Time, len(rows))
+	for k, i := range rows {
+		times[k] = *col.cells[i].(Time).t
+	}
+	return times, rows, nil
+}
+
+// Resample groups the rows of the DataFrame in buckets of the Time column
+// timeCol following the given rule and returns a DataFrame with one row per
+// bucket, from the first to the last one, with the label of the bucket on the
+// column timeCol and the given aggregations. Buckets without rows get NA
+// elements, or zero if counting. See DateRange for the supported rules.
+func (df DataFrame) Resample(timeCol, rule string, aggs ...Aggregate) (*DataFrame, error) {
+	r, err := parseRule(rule)
+	if err != nil {
+		return nil, err
+	}
+	if len(aggs) == 0 {
+		return nil, errors.New("No aggregations given")
+	}
+	times, rows, err := df.timeColumn(timeCol)
+	if err != nil {
+		return nil, err
+	}
+	cols := make([]column, 0, len(aggs))
+	for _, a := range aggs {
+		col, err := df.col(a.Column)
+		if err != nil {
+			return nil, err
+		}
+		cols = append(cols, col)
+	}
+
+	labels := Cells{}
+	results := make([]Cells, len(aggs))
+	k := 0
+	last := r.bucket(times[len(times)-1])
+	for b := r.bucket(times[0]); !b.After(last); b = r.next(b) {
+		bucket := []int{}
+		for ; k < len(times) && r.bucket(times[k]).Equal(b); k++ {
+			bucket = append(bucket, rows[k])
+		}
+		labels = append(labels, Times(b)...)
+		for j, a := range aggs {
+			c, err := a.apply(cols[j], bucket)
+			if err != nil {
+				return nil, err
+			}
+			results[j] = append(results[j], c)
+		}
+	}
+
+	columns := []C{{timeCol, labels}}
+	for j, a := range aggs {
+		columns = append(columns, C{a.name(), results[j]})
+	}
+	return New(columns...)
+}
+
+// Upsample returns a DataFrame with one row for each bucket of the given rule
+// between the first and the last element of the Time column timeCol. Each row
+// contains the label of the bucket on the column timeCol and the elements of
+// the last row of the DataFrame at or before that time on the rest of the
+// columns (forward fill). See DateRange for the supported rules.
+func (df DataFrame) Upsample(timeCol, rule string) (*DataFrame, error) {
+	r, err := parseRule(rule)
+	if err != nil {
+		return nil, err
+	}
+	times, rows, err := df.timeColumn(timeCol)
+	if err != nil {
+		return nil, err
+	}
+
+	labels := []time.Time{}
+	subset := []int{}
+	k := -1
+	start := r.bucket(times[0])
+	if start.Before(times[0]) {
+		start = r.next(start)
+	}
+	for t := start; !t.After(times[len(times)-1]); t = r.next(t) {
+		for k+1 < len(times) && !times[k+1].After(t) {
+			k++
+		}
+		labels = append(labels, t)
+		subset = append(subset, rows[k])
+	}
+	if len(subset) == 0 {
+		return nil, errors.New("No buckets between the first and last times")
+	}
+
+	newDf, err := df.SubsetRows(subset)
+	if err != nil {
+		return nil, err
+	}
+	return newDf.withColumn(timeCol, Times(labels))
+}
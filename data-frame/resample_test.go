@@ -0,0 +1,181 @@
+package df
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestTimes(t *testing.T) {
+	a := Times("2016-02-29", "2016-02-29 10:30", "2016-03-01T10:30:00+02:00", "bad", nil,
+		time.Date(2016, 1, 1, 0, 0, 0, 0, time.UTC))
+	expected := "[2016-02-29 2016-02-29T10:30:00Z 2016-03-01T10:30:00+02:00 NA NA 2016-01-01]"
+	received := fmt.Sprint(a)
+	if expected != received {
+		t.Error(
+			"Time elements not being propery inserted\n",
+			"Expected:\n",
+			expected, "\n",
+			"Received:\n",
+			received,
+		)
+	}
+
+	// Equal instants have the same checksum regardless of their time zone
+	b := Times("2016-03-01T08:30:00Z")
+	if a[2].Checksum() != b[0].Checksum() {
+		t.Error("Equal times should have the same checksum")
+	}
+
+	data := [][]string{
+		[]string{"Date", "Stamp", "Custom"},
+		[]string{"2016-02-01 12:00", "2016-02-01 12:00", "01/02/2016"},
+		[]string{"2016-01-31", "2016-01-31", "31/01/2016"},
+	}
+	d := DataFrame{}
+	err := d.LoadAndParse(data, T{"Date": "date", "Stamp": "time", "Custom": "time(02/01/2006)"})
+	if err != nil {
+		t.Error(err)
+	}
+	expected = "[[Date Stamp Custom] [2016-02-01 2016-02-01T12:00:00Z 2016-02-01] [2016-01-31 2016-01-31 2016-01-31]]"
+	received = fmt.Sprint(d.SaveRecords())
+	if expected != received {
+		t.Error("Expected:", expected, "Received:", received)
+	}
+	dc, err := d.ConditionRows(NewCondition([]string{"Stamp > 2016-02-01"}))
+	if err != nil || dc.NRows() != 1 {
+		t.Error("Time conditions not working:", dc, err)
+	}
+}
+
+func TestDateRange(t *testing.T) {
+	start := time.Date(2016, 1, 15, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2016, 4, 30, 0, 0, 0, 0, time.UTC)
+	var tests = []struct {
+		start, end time.Time
+		rule       string
+		expected   string
+	}{
+		{start, end, "M", "[2016-01-31 2016-02-29 2016-03-31 2016-04-30]"},
+		{start, end, "MS", "[2016-02-01 2016-03-01 2016-04-01]"},
+		{start, start.AddDate(0, 0, 14), "W", "[2016-01-18 2016-01-25]"},
+		{start, start.AddDate(0, 0, 2), "D", "[2016-01-15 2016-01-16 2016-01-17]"},
+		{start, start.Add(time.Hour), "30min", "[2016-01-15 2016-01-15T00:30:00Z 2016-01-15T01:00:00Z]"},
+		{start, end.AddDate(1, 0, 0), "Y", "[2016-12-31]"},
+		{start, start.AddDate(0, 0, 7), "3D", "[2016-01-15 2016-01-18 2016-01-21]"},
+		{start, start.AddDate(0, 0, 14), "7D", "[2016-01-21 2016-01-28]"},
+		{time.Date(1969, 12, 30, 0, 0, 0, 0, time.UTC), time.Date(1970, 1, 5, 0, 0, 0, 0, time.UTC), "3D", "[1970-01-01 1970-01-04]"},
+	}
+	for k, v := range tests {
+		d, err := DateRange("Date", v.start, v.end, v.rule)
+		if err != nil {
+			t.Error("Test", k, ":", err)
+			continue
+		}
+		received := fmt.Sprint(d.Columns["Date"].cells)
+		if v.expected != received {
+			t.Error(
+				"Test", k, "\n",
+				"Expected:\n",
+				v.expected, "\n",
+				"Received:\n",
+				received,
+			)
+		}
+	}
+	for _, rule := range []string{"2W", "x", "0h", ""} {
+		if _, err := DateRange("Date", start, end, rule); err == nil {
+			t.Error("Rule", rule, "should throw an error")
+		}
+	}
+}
+
+func TestDataFrame_Resample(t *testing.T) {
+	d, _ := New(
+		C{"Time", Times(
+			"2016-01-04 10:00", "2016-01-04 09:00", "2016-01-05 08:00",
+			"2016-01-07 11:00", nil, "2016-01-12 10:00",
+		)},
+		C{"Amount", Floats(1, 2, 4, 8, 16, 32)},
+		C{"Name", Strings("a", "b", "c", "d", "e", "f")},
+	)
+	dd, err := d.Resample("Time", "D",
+		Aggregate{Column: "Amount", Agg: AggSum},
+		Aggregate{Column: "Amount", Agg: AggCount, Name: "N"},
+		Aggregate{Column: "Name", Agg: AggLast},
+	)
+	if err != nil {
+		t.Error(err)
+	}
+	expected := "[[Time Amount_sum N Name_last] [2016-01-04 3 2 a] [2016-01-05 4 1 c] [2016-01-06 NA 0 NA] " +
+		"[2016-01-07 8 1 d] [2016-01-08 NA 0 NA] [2016-01-09 NA 0 NA] [2016-01-10 NA 0 NA] " +
+		"[2016-01-11 NA 0 NA] [2016-01-12 32 1 f]]"
+	received := fmt.Sprint(dd.SaveRecords())
+	if expected != received {
+		t.Error(
+			"Expected:\n",
+			expected, "\n",
+			"Received:\n",
+			received,
+		)
+	}
+
+	dd, err = d.Resample("Time", "W", Aggregate{Column: "Amount", Agg: AggMean})
+	if err != nil {
+		t.Error(err)
+	}
+	expected = "[[Time Amount_mean] [2016-01-04 3.75] [2016-01-11 32]]"
+	received = fmt.Sprint(dd.SaveRecords())
+	if expected != received {
+		t.Error("Expected:", expected, "Received:", received)
+	}
+
+	// Buckets of several days are aligned to the Unix epoch, a Thursday
+	dd, err = d.Resample("Time", "7D", Aggregate{Column: "Amount", Agg: AggSum})
+	if err != nil {
+		t.Error(err)
+	}
+	expected = "[[Time Amount_sum] [2015-12-31 7] [2016-01-07 40]]"
+	received = fmt.Sprint(dd.SaveRecords())
+	if expected != received {
+		t.Error("Expected:", expected, "Received:", received)
+	}
+
+	dd, err = d.Resample("Time", "M", Aggregate{Column: "Amount", Agg: AggMax})
+	if err != nil {
+		t.Error(err)
+	}
+	expected = "[[Time Amount_max] [2016-01-31 32]]"
+	received = fmt.Sprint(dd.SaveRecords())
+	if expected != received {
+		t.Error("Expected:", expected, "Received:", received)
+	}
+
+	if _, err := d.Resample("Name", "D", Aggregate{Column: "Amount"}); err == nil {
+		t.Error("Resampling by a non Time column should throw an error")
+	}
+	if _, err := d.Resample("Time", "D", Aggregate{Column: "Name", Agg: AggSum}); err == nil {
+		t.Error("Summing non numeric columns should throw an error")
+	}
+}
+
+func TestDataFrame_Upsample(t *testing.T) {
+	d, _ := New(
+		C{"Time", Times("2016-01-01 01:30", "2016-01-01 00:00", "2016-01-01 03:00")},
+		C{"Amount", Ints(2, 1, 3)},
+	)
+	dd, err := d.Upsample("Time", "h")
+	if err != nil {
+		t.Error(err)
+	}
+	expected := "[[Time Amount] [2016-01-01 1] [2016-01-01T01:00:00Z 1] [2016-01-01T02:00:00Z 2] [2016-01-01T03:00:00Z 3]]"
+	received := fmt.Sprint(dd.SaveRecords())
+	if expected != received {
+		t.Error(
+			"Expected:\n",
+			expected, "\n",
+			"Received:\n",
+			received,
+		)
+	}
+}
@@ -0,0 +1,135 @@
+package df
+
+import "errors"
+
+// sameSchema checks that two DataFrames have the same column names with the
+// same types and returns the names in the order of the first one
+func sameSchema(a, b DataFrame) ([]string, error) {
+	names := a.Names()
+	if len(names) != len(b.Columns) {
+		return nil, errors.New("Mismatching column names")
+	}
+	for _, k := range names {
+		col, ok := b.Columns[k]
+		if !ok {
+			return nil, errors.New("Mismatching column names")
+		}
+		if a.Columns[k].colType != col.colType {
+			return nil, errors.New("Mismatching column types")
+		}
+	}
+	return names, nil
+}
+
+// setRow identifies a row of one of the DataFrames of a set operation
+type setRow struct {
+	df  *DataFrame
+	row int
+}
+
+// setResult builds a DataFrame with the columns of the first DataFrame of a
+// set operation and the given rows, which may come from either DataFrame. The
+// result can have no rows.
+func setResult(a DataFrame, names []string, rows []setRow) *DataFrame {
+	newDf := a.copy()
+	newDf.nRows = len(rows)
+	for _, k := range names {
+		col := a.Columns[k]
+		cells := make(Cells, 0, len(rows))
+		for _, r := range rows {
+			cells = append(cells, r.df.Columns[k].cells[r.row])
+		}
+		col.cells = cells
+		col.unifyLevels()
+		newDf.Columns[k] = col
+	}
+	return &newDf
+}
+
+// setOperation returns the rows of a and b selected by keep, which receives
+// the checksum of each row and whether it belongs to a. Rows are visited in
+// order, first the ones of a and then the ones of b.
+func setOperation(a, b DataFrame, keep func(key string, fromA bool) bool) (*DataFrame, error) {
+	names, err := sameSchema(a, b)
+	if err != nil {
+		return nil, err
+	}
+	rows := []setRow{}
+	for _, v := range []struct {
+		df    *DataFrame
+		fromA bool
+	}{{&a, true}, {&b, false}} {
+		for i := 0; i < v.df.nRows; i++ {
+			if keep(rowChecksum(*v.df, names, i), v.fromA) {
+				rows = append(rows, setRow{v.df, i})
+			}
+		}
+	}
+	return setResult(a, names, rows), nil
+}
+
+// rowSet returns the set of checksums of the rows of a DataFrame over the
+// given columns
+func rowSet(df DataFrame, names []string) map[string]bool {
+	set := make(map[string]bool, df.nRows)
+	for i := 0; i < df.nRows; i++ {
+		set[rowChecksum(df, names, i)] = true
+	}
+	return set
+}
+
+// UnionAll returns the rows of a followed by the rows of b. Both DataFrames
+// must have the same column names and types, and the result keeps the column
+// order of a.
+func UnionAll(a, b DataFrame) (*DataFrame, error) {
+	return setOperation(a, b, func(string, bool) bool { return true })
+}
+
+// Union returns the distinct rows of a and b, in order of first appearance on
+// a followed by b. Both DataFrames must have the same column names and types.
+func Union(a, b DataFrame) (*DataFrame, error) {
+	seen := map[string]bool{}
+	return setOperation(a, b, func(key string, fromA bool) bool {
+		if seen[key] {
+			return false
+		}
+		seen[key] = true
+		return true
+	})
+}
+
+// Intersect returns the distinct rows of a that also appear on b, in their
+// order on a. Both DataFrames must have the same column names and types.
+func Intersect(a, b DataFrame) (*DataFrame, error) {
+	names, err := sameSchema(a, b)
+	if err != nil {
+		return nil, err
+	}
+	inB := rowSet(b, names)
+	seen := map[string]bool{}
+	return setOperation(a, b, func(key string, fromA bool) bool {
+		if !fromA || seen[key] || !inB[key] {
+			return false
+		}
+		seen[key] = true
+		return true
+	})
+}
+
+// Except returns the distinct rows of a that don't appear on b, in their
+// order on a. Both DataFrames must have the same column names and types.
+func Except(a, b DataFrame) (*DataFrame, error) {
+	names, err := sameSchema(a, b)
+	if err != nil {
+		return nil, err
+	}
+	inB := rowSet(b, names)
+	seen := map[string]bool{}
+	return setOperation(a, b, func(key string, fromA bool) bool {
+		if !fromA || seen[key] || inB[key] {
+			return false
+		}
+		seen[key] = true
+		return true
+	})
+}
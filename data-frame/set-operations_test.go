@@ -0,0 +1,71 @@
+package df
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestSetOperations(t *testing.T) {
+	a, _ := New(
+		C{"A", Strings("a", "b", "a", "c", nil)},
+		C{"B", Ints(1, 2, 1, 3, 4)},
+	)
+	// Same schema with the columns in a different order
+	b, _ := New(
+		C{"B", Ints(3, 5, 4, 3)},
+		C{"A", Strings("c", "d", nil, "c")},
+	)
+	var tests = []struct {
+		f        func(a, b DataFrame) (*DataFrame, error)
+		expected string
+	}{
+		{UnionAll, "[[A B] [a 1] [b 2] [a 1] [c 3] [NA 4] [c 3] [d 5] [NA 4] [c 3]]"},
+		{Union, "[[A B] [a 1] [b 2] [c 3] [NA 4] [d 5]]"},
+		{Intersect, "[[A B] [c 3] [NA 4]]"},
+		{Except, "[[A B] [a 1] [b 2]]"},
+	}
+	for k, v := range tests {
+		dd, err := v.f(*a, *b)
+		if err != nil {
+			t.Error("Test", k, ":", err)
+			continue
+		}
+		received := fmt.Sprint(dd.SaveRecords())
+		if v.expected != received || dd.NRows() != len(dd.SaveRecords())-1 {
+			t.Error(
+				"Test", k, "\n",
+				"Expected:\n",
+				v.expected, "\n",
+				"Received:\n",
+				received,
+			)
+		}
+	}
+
+	// Empty results keep the schema
+	dd, err := Except(*a, *a)
+	if err != nil {
+		t.Error(err)
+	}
+	if dd.NRows() != 0 || fmt.Sprint(dd.Names()) != "[A B]" || dd.Columns["B"].colType != "df.Int" {
+		t.Error("Expected an empty DataFrame with the schema of the input, received:", dd)
+	}
+	if fmt.Sprint(a.Columns["A"].cells) != "[a b a c NA]" {
+		t.Error("Original DataFrame was modified:", a.Columns["A"].cells)
+	}
+
+	c, _ := New(
+		C{"A", Strings("a")},
+		C{"B", Floats(1)},
+	)
+	if _, err := Union(*a, *c); err == nil {
+		t.Error("Mismatching column types should throw an error")
+	}
+	c, _ = New(
+		C{"A", Strings("a")},
+		C{"C", Ints(1)},
+	)
+	if _, err := Intersect(*a, *c); err == nil {
+		t.Error("Mismatching column names should throw an error")
+	}
+}
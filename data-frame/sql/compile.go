@@ -0,0 +1,919 @@
+package sql
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/kniren/gota/data-frame"
+)
+
+// source is a table of a query. Its columns are renamed to alias.column, so
+// the columns of different tables never collide on joins.
+type source struct {
+	alias string
+	names []string
+	frame df.DataFrame
+}
+
+// internalName returns the name of a column of a table inside the query
+func internalName(alias, colname string) string {
+	return alias + "." + colname
+}
+
+// hiddenCol is a column computed before grouping the rows: the value of a
+// GROUP BY expression or the argument of an aggregate function
+type hiddenCol struct {
+	name string
+	expr string
+	e    expr
+}
+
+// aggCall is an aggregate function of a grouped query
+type aggCall struct {
+	agg  df.Aggregate
+	call callExpr
+}
+
+// output is a column of the result: its name, the expression that computes
+// it and where it comes from on the query
+type output struct {
+	name string
+	expr string
+	item selectItem
+}
+
+// compiler translates a parsed query into operations over DataFrames.
+// Expressions are translated into the syntax of Mutate, with the columns
+// referred by their internal names. Names starting with # are used for the
+// intermediate columns and can't collide with internal names, which always
+// contain a dot.
+type compiler struct {
+	tables  map[string]df.DataFrame
+	stmt    *selectStmt
+	sources []source
+
+	// Grouped queries: the translation of the GROUP BY expressions mapped to
+	// the columns that hold their values, the aggregate functions by their
+	// translation and the columns that must be computed before grouping
+	grouped  bool
+	groups   map[string]string
+	keys     []string
+	aggs     []aggCall
+	aggIndex map[string]string
+	hidden   []hiddenCol
+}
+
+// aggregations are the supported aggregate functions
+var aggregations = map[string]df.Aggregation{
+	"count":       df.AggCount,
+	"sum":         df.AggSum,
+	"avg":         df.AggMean,
+	"min":         df.AggMin,
+	"max":         df.AggMax,
+	"stddev":      df.AggStd,
+	"stddev_samp": df.AggStd,
+}
+
+// functions maps the supported scalar functions to the ones of expressions
+var functions = map[string]string{
+	"abs":      "abs",
+	"round":    "round",
+	"ln":       "log",
+	"coalesce": "coalesce",
+	"ifnull":   "coalesce",
+}
+
+func isAggregate(e expr) bool {
+	call, ok := e.(callExpr)
+	if !ok {
+		return false
+	}
+	_, ok = aggregations[strings.ToLower(call.name)]
+	return ok
+}
+
+// children returns the subexpressions of e
+func children(e expr) []expr {
+	switch e := e.(type) {
+	case unaryExpr:
+		return []expr{e.x}
+	case binaryExpr:
+		return []expr{e.l, e.r}
+	case isNullExpr:
+		return []expr{e.x}
+	case callExpr:
+		return e.args
+	case caseExpr:
+		ret := []expr{}
+		for _, w := range e.whens {
+			ret = append(ret, w.cond, w.val)
+		}
+		if e.els != nil {
+			ret = append(ret, e.els)
+		}
+		return ret
+	}
+	return nil
+}
+
+func containsAggregate(e expr) bool {
+	if isAggregate(e) {
+		return true
+	}
+	for _, c := range children(e) {
+		if containsAggregate(c) {
+			return true
+		}
+	}
+	return false
+}
+
+// start returns the position where an expression starts
+func start(e expr) position {
+	switch e := e.(type) {
+	case binaryExpr:
+		return start(e.l)
+	case isNullExpr:
+		return start(e.x)
+	}
+	return e.position()
+}
+
+func quoteColumn(name string) string {
+	r := strings.NewReplacer("\\", "\\\\", "`", "\\`")
+	return "`" + r.Replace(name) + "`"
+}
+
+func quoteString(s string) string {
+	r := strings.NewReplacer("\\", "\\\\", "\"", "\\\"")
+	return "\"" + r.Replace(s) + "\""
+}
+
+// exprMessage returns the message of an error compiling or evaluating an
+// expression without the position, which refers to the translated expression,
+// nor the row of the intermediate DataFrame where it was found
+func exprMessage(err error) string {
+	msg := err.Error()
+	if strings.HasPrefix(msg, "row ") {
+		if i := strings.Index(msg, ": "); i >= 0 {
+			msg = msg[i+2:]
+		}
+	}
+	if strings.HasPrefix(msg, "expression error at position ") {
+		if i := strings.Index(msg, ": "); i >= 0 {
+			msg = msg[i+2:]
+		}
+	}
+	if strings.HasPrefix(msg, "Can't infer the type") {
+		msg = "can't infer the type of the expression"
+	}
+	return msg
+}
+
+// typeOf returns the type of a column of a DataFrame
+func typeOf(d df.DataFrame, colname string) string {
+	types := d.Types()
+	for i, k := range d.Names() {
+		if k == colname {
+			return types[i]
+		}
+	}
+	return ""
+}
+
+func (c *compiler) addSource(ref tableRef) error {
+	d, ok := c.tables[ref.name]
+	if !ok {
+		return errorf(ref.pos, "unknown table %s", ref.name)
+	}
+	for _, s := range c.sources {
+		if s.alias == ref.alias {
+			return errorf(ref.pos, "table name %s is used twice, give it an alias", ref.alias)
+		}
+	}
+	names := d.Names()
+	internal := make([]string, 0, len(names))
+	for _, k := range names {
+		internal = append(internal, internalName(ref.alias, k))
+	}
+	if err := d.SetNames(internal); err != nil {
+		return errorf(ref.pos, "%v", err)
+	}
+	c.sources = append(c.sources, source{ref.alias, names, d})
+	return nil
+}
+
+// resolve returns the internal name of a column and the index of its table,
+// looking for it on the first n tables
+func (c *compiler) resolve(ref colRef, n int) (string, int, error) {
+	if ref.table != "" {
+		for i, s := range c.sources[:n] {
+			if s.alias != ref.table {
+				continue
+			}
+			for _, k := range s.names {
+				if k == ref.name {
+					return internalName(s.alias, k), i, nil
+				}
+			}
+			return "", 0, errorf(ref.pos, "unknown column %s on table %s", ref.name, ref.table)
+		}
+		return "", 0, errorf(ref.pos, "unknown table %s", ref.table)
+	}
+	found := []int{}
+	for i, s := range c.sources[:n] {
+		for _, k := range s.names {
+			if k == ref.name {
+				found = append(found, i)
+			}
+		}
+	}
+	switch len(found) {
+	case 0:
+		return "", 0, errorf(ref.pos, "unknown column %s", ref.name)
+	case 1:
+		return internalName(c.sources[found[0]].alias, ref.name), found[0], nil
+	}
+	return "", 0, errorf(ref.pos, "column %s is ambiguous, it is on tables %s and %s",
+		ref.name, c.sources[found[0]].alias, c.sources[found[1]].alias)
+}
+
+// joinKeys checks the ON condition of the join with the table n and adds to
+// both sides of the join a column with the values of each of the compared
+// columns. Joins are done by these columns, so all the columns of both
+// tables are kept as in SQL.
+func (c *compiler) joinKeys(n int, j joinClause) ([]string, error) {
+	conds := []expr{}
+	var flatten func(e expr)
+	flatten = func(e expr) {
+		if b, ok := e.(binaryExpr); ok && b.op == "AND" {
+			flatten(b.l)
+			flatten(b.r)
+			return
+		}
+		conds = append(conds, e)
+	}
+	flatten(j.on)
+
+	keys := []string{}
+	for k, e := range conds {
+		b, ok := e.(binaryExpr)
+		l, lok := b.l.(colRef)
+		r, rok := b.r.(colRef)
+		if !ok || b.op != "=" || !lok || !rok {
+			return nil, errorf(start(e), "JOIN conditions must be equalities between columns joined with AND")
+		}
+		ln, ls, err := c.resolve(l, n+1)
+		if err != nil {
+			return nil, err
+		}
+		rn, rs, err := c.resolve(r, n+1)
+		if err != nil {
+			return nil, err
+		}
+		if ls == n {
+			ln, ls, rn, rs = rn, rs, ln, ls
+			l, r = r, l
+		}
+		if rs != n || ls == n {
+			return nil, errorf(b.pos, "JOIN condition must compare a column of %s with a column of a previous table", j.table.alias)
+		}
+		lt, rt := typeOf(c.sources[ls].frame, ln), typeOf(c.sources[rs].frame, rn)
+		if lt != rt {
+			return nil, errorf(b.pos, "can't join %s of type %s with %s of type %s", format(l), lt, format(r), rt)
+		}
+		key := fmt.Sprintf("#j%d_%d", n, k)
+		for _, side := range []struct {
+			i    int
+			name string
+		}{{ls, ln}, {rs, rn}} {
+			d, err := c.sources[side.i].frame.Mutate(key, quoteColumn(side.name))
+			if err != nil {
+				return nil, errorf(b.pos, "%v", err)
+			}
+			c.sources[side.i].frame = *d
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// plan returns the lazy plan that joins the tables by the given keys. Probe
+// plans read no rows and are used to check the types of the expressions.
+func (c *compiler) plan(keys [][]string, probe bool) *df.LazyFrame {
+	scan := func(s source) *df.LazyFrame {
+		if probe {
+			return s.frame.Empty().Lazy()
+		}
+		return s.frame.Lazy()
+	}
+	l := scan(c.sources[0])
+	for i, j := range c.stmt.joins {
+		l = l.Join(scan(c.sources[i+1]), j.how, keys[i]...)
+	}
+	return l
+}
+
+// translate returns the expression of Mutate equivalent to e. On grouped
+// queries e must be a GROUP BY expression, or columns must be used inside
+// aggregate functions. clause names the part of the query where e appears.
+func (c *compiler) translate(e expr, clause string, grouped bool) (string, error) {
+	if grouped {
+		if call, ok := e.(callExpr); ok && isAggregate(call) {
+			return c.aggregate(call)
+		}
+		if !containsAggregate(e) {
+			s, err := c.translate(e, clause, false)
+			if err != nil {
+				return "", err
+			}
+			if g, ok := c.groups[s]; ok {
+				return quoteColumn(g), nil
+			}
+			if ref, ok := e.(colRef); ok {
+				return "", errorf(ref.pos, "column %s must appear in the GROUP BY clause or be used in an aggregate function", format(ref))
+			}
+		}
+	}
+	tr := func(x expr) (string, error) {
+		return c.translate(x, clause, grouped)
+	}
+
+	switch e := e.(type) {
+	case literal:
+		switch e.kind {
+		case litString:
+			return quoteString(e.text), nil
+		case litBool:
+			return strings.ToLower(e.text), nil
+		case litNull:
+			return "NA", nil
+		}
+		return e.text, nil
+	case colRef:
+		name, _, err := c.resolve(e, len(c.sources))
+		return quoteColumn(name), err
+	case unaryExpr:
+		x, err := tr(e.x)
+		if err != nil {
+			return "", err
+		}
+		if e.op == "NOT" {
+			return "(!" + x + ")", nil
+		}
+		return "(-" + x + ")", nil
+	case binaryExpr:
+		l, err := tr(e.l)
+		if err != nil {
+			return "", err
+		}
+		r, err := tr(e.r)
+		if err != nil {
+			return "", err
+		}
+		op := e.op
+		switch op {
+		case "=":
+			op = "=="
+		case "<>":
+			op = "!="
+		case "AND":
+			op = "&&"
+		case "OR":
+			op = "||"
+		}
+		return "(" + l + " " + op + " " + r + ")", nil
+	case isNullExpr:
+		x, err := tr(e.x)
+		if err != nil {
+			return "", err
+		}
+		if e.not {
+			return "(!isna(" + x + "))", nil
+		}
+		return "isna(" + x + ")", nil
+	case callExpr:
+		if isAggregate(e) {
+			return "", errorf(e.pos, "aggregate functions are not allowed in %s", clause)
+		}
+		f, ok := functions[strings.ToLower(e.name)]
+		if !ok {
+			return "", errorf(e.pos, "unknown function %s", e.name)
+		}
+		args := make([]string, 0, len(e.args))
+		for _, a := range e.args {
+			s, err := tr(a)
+			if err != nil {
+				return "", err
+			}
+			args = append(args, s)
+		}
+		return f + "(" + strings.Join(args, ", ") + ")", nil
+	case caseExpr:
+		// Conditions that are NULL take the next branch, as in SQL
+		s := "NA"
+		if e.els != nil {
+			var err error
+			if s, err = tr(e.els); err != nil {
+				return "", err
+			}
+		}
+		for i := len(e.whens) - 1; i >= 0; i-- {
+			cond, err := tr(e.whens[i].cond)
+			if err != nil {
+				return "", err
+			}
+			val, err := tr(e.whens[i].val)
+			if err != nil {
+				return "", err
+			}
+			s = "if(coalesce(" + cond + ", false), " + val + ", " + s + ")"
+		}
+		return s, nil
+	}
+	return "", errorf(e.position(), "unsupported expression")
+}
+
+// aggregate registers an aggregate function of a grouped query and returns
+// the column that will hold its result
+func (c *compiler) aggregate(call callExpr) (string, error) {
+	name := strings.ToLower(call.name)
+	agg := df.Aggregate{Agg: aggregations[name]}
+	key := name + "(*)"
+	if !call.star {
+		if len(call.args) != 1 {
+			return "", errorf(call.pos, "%s expects one argument but received %d", call.name, len(call.args))
+		}
+		arg := call.args[0]
+		if containsAggregate(arg) {
+			return "", errorf(start(arg), "aggregate functions can't be nested")
+		}
+		s, err := c.translate(arg, call.name, false)
+		if err != nil {
+			return "", err
+		}
+		key = name + "(" + s + ")"
+		if col, ok := c.aggIndex[key]; ok {
+			return quoteColumn(col), nil
+		}
+		if ref, ok := arg.(colRef); ok {
+			agg.Column, _, _ = c.resolve(ref, len(c.sources))
+		} else {
+			agg.Column = fmt.Sprintf("#x%d", len(c.hidden))
+			c.hidden = append(c.hidden, hiddenCol{agg.Column, s, arg})
+		}
+	} else {
+		if col, ok := c.aggIndex[key]; ok {
+			return quoteColumn(col), nil
+		}
+		// COUNT(*) counts the elements of a column without NA elements
+		agg.Column = "#one"
+		found := false
+		for _, h := range c.hidden {
+			found = found || h.name == agg.Column
+		}
+		if !found {
+			c.hidden = append(c.hidden, hiddenCol{agg.Column, "1", nil})
+		}
+	}
+	agg.Name = fmt.Sprintf("#a%d", len(c.aggs))
+	c.aggs = append(c.aggs, aggCall{agg, call})
+	c.aggIndex[key] = agg.Name
+	return quoteColumn(agg.Name), nil
+}
+
+// check returns the error of compiling the given expression against the
+// columns of probe, which has no rows
+func check(probe df.DataFrame, s string) error {
+	_, err := probe.Mutate("#check", s)
+	return err
+}
+
+// locate checks that the translation of e compiles against the columns of
+// probe. On failure the error is reported at the smallest subexpression that
+// fails, so its position on the query is as precise as possible.
+func (c *compiler) locate(e expr, clause string, grouped bool, probe df.DataFrame) error {
+	s, err := c.translate(e, clause, grouped)
+	if err != nil {
+		return err
+	}
+	err = check(probe, s)
+	if err == nil {
+		return nil
+	}
+	if !(grouped && isAggregate(e)) {
+		for _, ch := range children(e) {
+			// Literals are valid by themselves, NULL only in context
+			if _, ok := ch.(literal); ok {
+				continue
+			}
+			if err := c.locate(ch, clause, grouped, probe); err != nil {
+				return err
+			}
+		}
+	}
+	return errorf(e.position(), "%s", exprMessage(err))
+}
+
+// condition checks that e is a boolean expression valid on probe
+func (c *compiler) condition(e expr, clause string, grouped bool, probe df.DataFrame) (string, error) {
+	if err := c.locate(e, clause, grouped, probe); err != nil {
+		return "", err
+	}
+	s, _ := c.translate(e, clause, grouped)
+	if _, err := probe.Lazy().Filter(s).Collect(); err != nil {
+		return "", errorf(start(e), "%s condition must be boolean", clause)
+	}
+	return s, nil
+}
+
+// expandItems replaces * and table.* on the select list by the columns they
+// stand for and returns the items with the names of the result columns
+func (c *compiler) expandItems() ([]output, error) {
+	outputs := []output{}
+	starred := map[int]source{}
+	for _, item := range c.stmt.items {
+		if !item.star {
+			name := item.alias
+			if name == "" {
+				name = format(item.e)
+				if ref, ok := item.e.(colRef); ok {
+					name = ref.name
+				}
+			}
+			outputs = append(outputs, output{name: name, item: item})
+			continue
+		}
+		found := false
+		for _, s := range c.sources {
+			if item.table != "" && item.table != s.alias {
+				continue
+			}
+			found = true
+			for _, k := range s.names {
+				starred[len(outputs)] = s
+				outputs = append(outputs, output{name: k, item: selectItem{
+					pos: item.pos,
+					e:   colRef{item.pos, s.alias, k},
+				}})
+			}
+		}
+		if !found {
+			return nil, errorf(item.pos, "unknown table %s", item.table)
+		}
+	}
+
+	// Columns of * with the same name are qualified with their table
+	counts := map[string]int{}
+	for _, o := range outputs {
+		counts[o.name]++
+	}
+	for i, s := range starred {
+		if counts[outputs[i].name] > 1 {
+			outputs[i].name = internalName(s.alias, outputs[i].name)
+		}
+	}
+	seen := map[string]bool{}
+	for _, o := range outputs {
+		if seen[o.name] {
+			return nil, errorf(o.item.pos, "duplicate column name %s on the select list, use AS to rename it", o.name)
+		}
+		seen[o.name] = true
+	}
+	return outputs, nil
+}
+
+// selectedExpr returns the expression of the select list that e refers to by
+// position or by name, if any
+func selectedExpr(e expr, outputs []output, clause string, names bool) (int, error) {
+	switch e := e.(type) {
+	case literal:
+		if e.kind != litNumber {
+			return -1, nil
+		}
+		n, err := strconv.Atoi(e.text)
+		if err != nil || n < 1 || n > len(outputs) {
+			return -1, errorf(e.pos, "%s position %s is not on the select list", clause, e.text)
+		}
+		return n - 1, nil
+	case colRef:
+		if !names || e.table != "" {
+			return -1, nil
+		}
+		for i, o := range outputs {
+			if o.name == e.name && (o.item.alias != "" || clause == "ORDER BY") {
+				return i, nil
+			}
+		}
+	}
+	return -1, nil
+}
+
+// groupKeys sets the columns that group the rows. GROUP BY expressions can
+// also be positions or aliases of the select list.
+func (c *compiler) groupKeys(outputs []output) error {
+	c.groups = map[string]string{}
+	c.aggIndex = map[string]string{}
+	for _, g := range c.stmt.groupBy {
+		e := g
+		i, err := selectedExpr(g, outputs, "GROUP BY", false)
+		if err != nil {
+			return err
+		}
+		if ref, ok := g.(colRef); ok && ref.table == "" {
+			// Aliases are only used if there is no column with that name
+			if _, _, err := c.resolve(ref, len(c.sources)); err != nil {
+				if i, _ = selectedExpr(g, outputs, "GROUP BY", true); i < 0 {
+					return err
+				}
+			}
+		}
+		if i >= 0 {
+			e = outputs[i].item.e
+		}
+		if containsAggregate(e) {
+			return errorf(start(g), "aggregate functions are not allowed in GROUP BY")
+		}
+		s, err := c.translate(e, "GROUP BY", false)
+		if err != nil {
+			return err
+		}
+		if _, ok := c.groups[s]; ok {
+			continue
+		}
+		name := fmt.Sprintf("#g%d", len(c.keys))
+		if ref, ok := e.(colRef); ok {
+			name, _, _ = c.resolve(ref, len(c.sources))
+		} else {
+			c.hidden = append(c.hidden, hiddenCol{name, s, e})
+		}
+		c.groups[s] = name
+		c.keys = append(c.keys, name)
+	}
+	return nil
+}
+
+// group computes the hidden columns and groups the rows of frame and probe
+func (c *compiler) group(frame, probe df.DataFrame) (df.DataFrame, df.DataFrame, error) {
+	if len(c.aggs) == 0 {
+		// Without aggregate functions the groups are found counting rows
+		c.hidden = append(c.hidden, hiddenCol{"#one", "1", nil})
+	}
+	for _, h := range c.hidden {
+		if h.e != nil {
+			if err := c.locate(h.e, "GROUP BY", false, probe); err != nil {
+				return frame, probe, err
+			}
+		}
+		p, err := probe.Mutate(h.name, h.expr)
+		if err != nil {
+			return frame, probe, err
+		}
+		f, err := frame.Mutate(h.name, h.expr)
+		if err != nil && h.e != nil {
+			return frame, probe, errorf(h.e.position(), "%v", err)
+		}
+		if err != nil {
+			return frame, probe, err
+		}
+		probe, frame = *p, *f
+	}
+	aggs := make([]df.Aggregate, 0, len(c.aggs))
+	for _, a := range c.aggs {
+		if _, err := probe.GroupBy(nil, a.agg); err != nil {
+			return frame, probe, errorf(a.call.pos, "can't compute %s over elements of type %s",
+				strings.ToUpper(a.call.name), typeOf(probe, a.agg.Column))
+		}
+		aggs = append(aggs, a.agg)
+	}
+	if len(aggs) == 0 {
+		aggs = append(aggs, df.Aggregate{Column: "#one", Agg: df.AggCount, Name: "#n"})
+	}
+	p, err := probe.GroupBy(c.keys, aggs...)
+	if err != nil {
+		return frame, probe, err
+	}
+	f, err := frame.GroupBy(c.keys, aggs...)
+	if err != nil {
+		return frame, probe, err
+	}
+	return *f, *p, nil
+}
+
+// run executes the query
+func (c *compiler) run() (*df.DataFrame, error) {
+	stmt := c.stmt
+	if err := c.addSource(stmt.from); err != nil {
+		return nil, err
+	}
+	joins := make([][]string, len(stmt.joins))
+	for i, j := range stmt.joins {
+		if err := c.addSource(j.table); err != nil {
+			return nil, err
+		}
+		if j.how == df.JoinCross {
+			continue
+		}
+		var err error
+		if joins[i], err = c.joinKeys(i+1, j); err != nil {
+			return nil, err
+		}
+	}
+
+	probeFrame, err := c.plan(joins, true).Collect()
+	if err != nil {
+		return nil, err
+	}
+	probe := *probeFrame
+	l := c.plan(joins, false)
+	if stmt.where != nil {
+		w, err := c.condition(stmt.where, "WHERE", false, probe)
+		if err != nil {
+			return nil, err
+		}
+		l = l.Filter(w)
+	}
+	d, err := l.Collect()
+	if err != nil {
+		if stmt.where != nil {
+			return nil, errorf(start(stmt.where), "%s", exprMessage(err))
+		}
+		return nil, err
+	}
+	frame := *d
+
+	outputs, err := c.expandItems()
+	if err != nil {
+		return nil, err
+	}
+	c.grouped = len(stmt.groupBy) > 0 || stmt.having != nil
+	for _, o := range outputs {
+		c.grouped = c.grouped || containsAggregate(o.item.e)
+	}
+	for _, o := range stmt.orderBy {
+		c.grouped = c.grouped || containsAggregate(o.e)
+	}
+	if c.grouped {
+		for _, item := range stmt.items {
+			if item.star {
+				return nil, errorf(item.pos, "* can't be used with GROUP BY or aggregate functions")
+			}
+		}
+		if err := c.groupKeys(outputs); err != nil {
+			return nil, err
+		}
+	}
+
+	// Translate everything before grouping, so all the aggregate functions
+	// are known
+	for i, o := range outputs {
+		if outputs[i].expr, err = c.translate(o.item.e, "SELECT", c.grouped); err != nil {
+			return nil, err
+		}
+	}
+	var having string
+	if stmt.having != nil {
+		if having, err = c.translate(stmt.having, "HAVING", true); err != nil {
+			return nil, err
+		}
+	}
+	sortKeys, orderExprs, err := c.order(outputs)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.grouped {
+		if frame, probe, err = c.group(frame, probe); err != nil {
+			return nil, err
+		}
+		if stmt.having != nil {
+			if _, err := c.condition(stmt.having, "HAVING", true, probe); err != nil {
+				return nil, err
+			}
+			d, err := frame.Lazy().Filter(having).Collect()
+			if err != nil {
+				return nil, errorf(start(stmt.having), "%s", exprMessage(err))
+			}
+			frame = *d
+		}
+	}
+	return c.project(frame, probe, outputs, sortKeys, orderExprs)
+}
+
+// order returns the sorting keys of the result. Expressions of ORDER BY that
+// aren't on the select list are computed in columns named #o0, #o1...
+func (c *compiler) order(outputs []output) ([]df.SortKey, []output, error) {
+	keys := []df.SortKey{}
+	exprs := []output{}
+	for _, o := range c.stmt.orderBy {
+		i, err := selectedExpr(o.e, outputs, "ORDER BY", true)
+		if err != nil {
+			return nil, nil, err
+		}
+		if i < 0 {
+			s, err := c.translate(o.e, "ORDER BY", c.grouped)
+			if err != nil {
+				return nil, nil, err
+			}
+			for j, out := range outputs {
+				if out.expr == s {
+					i = j
+					break
+				}
+			}
+			if i < 0 {
+				if c.stmt.distinct {
+					return nil, nil, errorf(start(o.e), "ORDER BY expressions must appear on the select list of a SELECT DISTINCT")
+				}
+				name := fmt.Sprintf("#o%d", len(exprs))
+				exprs = append(exprs, output{name, s, selectItem{pos: start(o.e), e: o.e}})
+				keys = append(keys, df.SortKey{Column: name, Desc: o.desc, NAFirst: o.naFirst})
+				continue
+			}
+		}
+		keys = append(keys, df.SortKey{Column: fmt.Sprintf("#s%d", i), Desc: o.desc, NAFirst: o.naFirst})
+	}
+	return keys, exprs, nil
+}
+
+// project computes the columns of the result, sorts its rows and applies
+// DISTINCT, LIMIT and OFFSET
+func (c *compiler) project(frame, probe df.DataFrame, outputs []output, sortKeys []df.SortKey, orderExprs []output) (*df.DataFrame, error) {
+	stmt := c.stmt
+	cols := make([]string, 0, len(outputs))
+	names := make([]string, 0, len(outputs))
+	for i, o := range outputs {
+		cols = append(cols, fmt.Sprintf("#s%d", i))
+		names = append(names, o.name)
+	}
+	columns := append([]output{}, outputs...)
+	for i := range columns {
+		columns[i].name = cols[i]
+	}
+	columns = append(columns, orderExprs...)
+
+	for _, o := range columns {
+		if err := c.locate(o.item.e, "SELECT", c.grouped, probe); err != nil {
+			return nil, err
+		}
+		d, err := frame.Mutate(o.name, o.expr)
+		if err != nil {
+			return nil, errorf(o.item.pos, "%s", exprMessage(err))
+		}
+		frame = *d
+	}
+
+	if stmt.distinct {
+		d, err := frame.SubsetColumns(cols)
+		if err != nil {
+			return nil, err
+		}
+		if d.NRows() > 0 {
+			if d, err = d.RemoveDuplicated(df.DuplicatesOptions{KeepOrder: true}); err != nil {
+				return nil, err
+			}
+		}
+		frame = *d
+	}
+	if len(sortKeys) > 0 {
+		d, err := frame.Arrange(sortKeys...)
+		if err != nil {
+			return nil, err
+		}
+		frame = *d
+	}
+	if stmt.limit >= 0 || stmt.offset > 0 {
+		n := frame.NRows()
+		from, to := stmt.offset, n
+		if from > n || from < 0 {
+			from = n
+		}
+		if stmt.limit >= 0 && from+stmt.limit < to {
+			to = from + stmt.limit
+		}
+		var d *df.DataFrame
+		var err error
+		switch {
+		case from == to:
+			d = frame.Empty()
+		case to-from < n:
+			d, err = frame.SubsetRows(df.R{From: from, To: to})
+		default:
+			d = &frame
+		}
+		if err != nil {
+			return nil, err
+		}
+		frame = *d
+	}
+
+	d, err := frame.SubsetColumns(cols)
+	if err != nil {
+		return nil, err
+	}
+	if err := d.SetNames(names); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
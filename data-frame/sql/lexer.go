@@ -0,0 +1,178 @@
+package sql
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Error is an error on a query, located by the line and column of the query
+// where it was found. Both are counted from one, in characters.
+type Error struct {
+	Line, Column int
+	Msg          string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("sql error at line %d, column %d: %s", e.Line, e.Column, e.Msg)
+}
+
+// position is the location of a token on the query
+type position struct {
+	line, col int
+}
+
+// errorf returns an Error for the given position of the query
+func errorf(p position, format string, args ...interface{}) error {
+	return &Error{Line: p.line, Column: p.col, Msg: fmt.Sprintf(format, args...)}
+}
+
+// tokenKind identifies the kind of the tokens of a query
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokKeyword
+	tokIdent
+	tokNumber
+	tokString
+	tokOp
+)
+
+// token is a token of a query. Keywords are stored in upper case and quoted
+// identifiers keep their case.
+type token struct {
+	kind tokenKind
+	text string
+	pos  position
+}
+
+// describe returns the token as written on error messages
+func (t token) describe() string {
+	switch t.kind {
+	case tokEOF:
+		return "end of query"
+	case tokString:
+		return fmt.Sprintf("string '%s'", t.text)
+	case tokKeyword:
+		return t.text
+	}
+	return fmt.Sprintf("%q", t.text)
+}
+
+// keywords are the reserved words of the supported SQL dialect, plus the
+// ones of unsupported features so they are reported as such and not taken as
+// column names
+var keywords = map[string]bool{
+	"SELECT": true, "DISTINCT": true, "ALL": true, "FROM": true, "AS": true,
+	"JOIN": true, "INNER": true, "LEFT": true, "RIGHT": true, "FULL": true,
+	"OUTER": true, "CROSS": true, "NATURAL": true, "ON": true, "USING": true,
+	"WHERE": true, "GROUP": true, "BY": true, "HAVING": true, "ORDER": true,
+	"ASC": true, "DESC": true, "NULLS": true, "FIRST": true, "LAST": true,
+	"LIMIT": true, "OFFSET": true, "AND": true, "OR": true, "NOT": true,
+	"IS": true, "NULL": true, "IN": true, "BETWEEN": true, "LIKE": true,
+	"TRUE": true, "FALSE": true, "CASE": true, "WHEN": true, "THEN": true,
+	"ELSE": true, "END": true, "UNION": true, "INTERSECT": true,
+	"EXCEPT": true, "WITH": true, "OVER": true, "EXISTS": true,
+}
+
+// tokenize splits a query into tokens. Identifiers can be quoted with double
+// quotes or backquotes, and strings are written between single quotes, with
+// two single quotes standing for one. Comments start with -- and end with the
+// line.
+func tokenize(query string) ([]token, error) {
+	toks := []token{}
+	rs := []rune(query)
+	line, lineStart := 1, 0
+	for i := 0; i < len(rs); {
+		r := rs[i]
+		pos := position{line, i - lineStart + 1}
+		switch {
+		case r == '\n':
+			i++
+			line, lineStart = line+1, i
+		case unicode.IsSpace(r):
+			i++
+		case r == '-' && i+1 < len(rs) && rs[i+1] == '-':
+			for i < len(rs) && rs[i] != '\n' {
+				i++
+			}
+		case unicode.IsDigit(r) || (r == '.' && i+1 < len(rs) && unicode.IsDigit(rs[i+1])):
+			j := i
+			for j < len(rs) && (unicode.IsDigit(rs[j]) || rs[j] == '.') {
+				j++
+			}
+			if j < len(rs) && (rs[j] == 'e' || rs[j] == 'E') {
+				k := j + 1
+				if k < len(rs) && (rs[k] == '+' || rs[k] == '-') {
+					k++
+				}
+				if k < len(rs) && unicode.IsDigit(rs[k]) {
+					for j = k; j < len(rs) && unicode.IsDigit(rs[j]); j++ {
+					}
+				}
+			}
+			if j < len(rs) && (unicode.IsLetter(rs[j]) || rs[j] == '_') {
+				return nil, errorf(pos, "invalid number %q", string(rs[i:j+1]))
+			}
+			toks = append(toks, token{tokNumber, string(rs[i:j]), pos})
+			i = j
+		case r == '\'' || r == '"' || r == '`':
+			var sb strings.Builder
+			j := i + 1
+			for ; j < len(rs); j++ {
+				if rs[j] == r {
+					// A doubled quote stands for the quote itself
+					if j+1 < len(rs) && rs[j+1] == r {
+						j++
+					} else {
+						break
+					}
+				}
+				if rs[j] == '\n' {
+					line, lineStart = line+1, j+1
+				}
+				sb.WriteRune(rs[j])
+			}
+			if j >= len(rs) {
+				return nil, errorf(pos, "unterminated %c", r)
+			}
+			kind := tokIdent
+			if r == '\'' {
+				kind = tokString
+			}
+			toks = append(toks, token{kind, sb.String(), pos})
+			i = j + 1
+		case unicode.IsLetter(r) || r == '_':
+			j := i
+			for j < len(rs) && (unicode.IsLetter(rs[j]) || unicode.IsDigit(rs[j]) || rs[j] == '_') {
+				j++
+			}
+			word := string(rs[i:j])
+			if upper := strings.ToUpper(word); keywords[upper] {
+				toks = append(toks, token{tokKeyword, upper, pos})
+			} else {
+				toks = append(toks, token{tokIdent, word, pos})
+			}
+			i = j
+		default:
+			op := ""
+			if i+1 < len(rs) {
+				switch two := string(rs[i : i+2]); two {
+				case "<>", "!=", "<=", ">=", "||":
+					op = two
+				}
+			}
+			if op == "" {
+				if !strings.ContainsRune("+-*/%(),.;=<>", r) {
+					return nil, errorf(pos, "unexpected character %q", r)
+				}
+				op = string(r)
+			}
+			toks = append(toks, token{tokOp, op, pos})
+			i += len([]rune(op))
+		}
+	}
+	toks = append(toks, token{tokEOF, "", position{line, len(rs) - lineStart + 1}})
+	return toks, nil
+}
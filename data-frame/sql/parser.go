@@ -0,0 +1,822 @@
+package sql
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/kniren/gota/data-frame"
+)
+
+// expr is a node of the syntax tree of an expression
+type expr interface {
+	position() position
+}
+
+type literalKind int
+
+const (
+	litNumber literalKind = iota
+	litString
+	litBool
+	litNull
+)
+
+type literal struct {
+	pos  position
+	kind literalKind
+	text string
+}
+
+// colRef is a reference to a column, optionally qualified with the name of
+// its table
+type colRef struct {
+	pos   position
+	table string
+	name  string
+}
+
+type unaryExpr struct {
+	pos position
+	op  string
+	x   expr
+}
+
+type binaryExpr struct {
+	pos  position
+	op   string
+	l, r expr
+}
+
+type isNullExpr struct {
+	pos position
+	x   expr
+	not bool
+}
+
+// callExpr is a function call. star is set for COUNT(*).
+type callExpr struct {
+	pos  position
+	name string
+	args []expr
+	star bool
+}
+
+type whenClause struct {
+	cond, val expr
+}
+
+type caseExpr struct {
+	pos   position
+	whens []whenClause
+	els   expr
+}
+
+func (e literal) position() position    { return e.pos }
+func (e colRef) position() position     { return e.pos }
+func (e unaryExpr) position() position  { return e.pos }
+func (e binaryExpr) position() position { return e.pos }
+func (e isNullExpr) position() position { return e.pos }
+func (e callExpr) position() position   { return e.pos }
+func (e caseExpr) position() position   { return e.pos }
+
+// selectItem is an element of the select list. star is set for * and
+// table.*, with table holding the name of the table.
+type selectItem struct {
+	pos   position
+	e     expr
+	alias string
+	star  bool
+	table string
+}
+
+type tableRef struct {
+	pos   position
+	name  string
+	alias string
+}
+
+type joinClause struct {
+	pos   position
+	how   df.JoinType
+	table tableRef
+	on    expr
+}
+
+type orderItem struct {
+	e       expr
+	desc    bool
+	naFirst bool
+}
+
+// selectStmt is a parsed SELECT query. limit and offset are -1 when absent.
+type selectStmt struct {
+	distinct bool
+	items    []selectItem
+	from     tableRef
+	joins    []joinClause
+	where    expr
+	groupBy  []expr
+	having   expr
+	orderBy  []orderItem
+	limit    int
+	offset   int
+}
+
+// parser builds the syntax tree of a query from its tokens
+type parser struct {
+	toks []token
+	pos  int
+}
+
+func (p *parser) peek() token {
+	return p.toks[p.pos]
+}
+
+// peekAt returns the token n positions after the next one
+func (p *parser) peekAt(n int) token {
+	if p.pos+n >= len(p.toks) {
+		return p.toks[len(p.toks)-1]
+	}
+	return p.toks[p.pos+n]
+}
+
+func (p *parser) next() token {
+	t := p.toks[p.pos]
+	if t.kind != tokEOF {
+		p.pos++
+	}
+	return t
+}
+
+// accept consumes the next token if it is one of the given keywords or
+// operators
+func (p *parser) accept(words ...string) (token, bool) {
+	t := p.peek()
+	if t.kind != tokKeyword && t.kind != tokOp {
+		return t, false
+	}
+	for _, w := range words {
+		if t.text == w {
+			p.pos++
+			return t, true
+		}
+	}
+	return t, false
+}
+
+func (p *parser) expect(word string) (token, error) {
+	t, ok := p.accept(word)
+	if !ok {
+		return t, errorf(t.pos, "expected %s but found %s", word, t.describe())
+	}
+	return t, nil
+}
+
+func (p *parser) ident(what string) (token, error) {
+	t := p.next()
+	if t.kind != tokIdent {
+		return t, errorf(t.pos, "expected %s but found %s", what, t.describe())
+	}
+	return t, nil
+}
+
+// parse parses a SELECT query
+func parse(query string) (*selectStmt, error) {
+	toks, err := tokenize(query)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{toks: toks}
+	stmt, err := p.parseSelect()
+	if err != nil {
+		return nil, err
+	}
+	p.accept(";")
+	if t := p.peek(); t.kind != tokEOF {
+		switch t.text {
+		case "UNION", "INTERSECT", "EXCEPT":
+			return nil, errorf(t.pos, "%s is not supported", t.text)
+		}
+		return nil, errorf(t.pos, "unexpected %s", t.describe())
+	}
+	return stmt, nil
+}
+
+func (p *parser) parseSelect() (*selectStmt, error) {
+	stmt := &selectStmt{limit: -1, offset: -1}
+	if t := p.peek(); t.kind == tokKeyword && t.text == "WITH" {
+		return nil, errorf(t.pos, "WITH is not supported")
+	}
+	if _, err := p.expect("SELECT"); err != nil {
+		return nil, err
+	}
+	if _, ok := p.accept("DISTINCT"); ok {
+		stmt.distinct = true
+	} else {
+		p.accept("ALL")
+	}
+	for {
+		item, err := p.parseSelectItem()
+		if err != nil {
+			return nil, err
+		}
+		stmt.items = append(stmt.items, item)
+		if _, ok := p.accept(","); !ok {
+			break
+		}
+	}
+
+	if _, err := p.expect("FROM"); err != nil {
+		return nil, err
+	}
+	from, err := p.parseTableRef()
+	if err != nil {
+		return nil, err
+	}
+	stmt.from = from
+	for {
+		j, ok, err := p.parseJoin()
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			break
+		}
+		stmt.joins = append(stmt.joins, j)
+	}
+
+	if _, ok := p.accept("WHERE"); ok {
+		if stmt.where, err = p.parseExpr(); err != nil {
+			return nil, err
+		}
+	}
+	if _, ok := p.accept("GROUP"); ok {
+		if _, err := p.expect("BY"); err != nil {
+			return nil, err
+		}
+		for {
+			e, err := p.parseExpr()
+			if err != nil {
+				return nil, err
+			}
+			stmt.groupBy = append(stmt.groupBy, e)
+			if _, ok := p.accept(","); !ok {
+				break
+			}
+		}
+	}
+	if _, ok := p.accept("HAVING"); ok {
+		if stmt.having, err = p.parseExpr(); err != nil {
+			return nil, err
+		}
+	}
+	if _, ok := p.accept("ORDER"); ok {
+		if _, err := p.expect("BY"); err != nil {
+			return nil, err
+		}
+		for {
+			item, err := p.parseOrderItem()
+			if err != nil {
+				return nil, err
+			}
+			stmt.orderBy = append(stmt.orderBy, item)
+			if _, ok := p.accept(","); !ok {
+				break
+			}
+		}
+	}
+	if _, ok := p.accept("LIMIT"); ok {
+		if stmt.limit, err = p.parseCount("LIMIT"); err != nil {
+			return nil, err
+		}
+	}
+	if _, ok := p.accept("OFFSET"); ok {
+		if stmt.offset, err = p.parseCount("OFFSET"); err != nil {
+			return nil, err
+		}
+	}
+	return stmt, nil
+}
+
+func (p *parser) parseSelectItem() (selectItem, error) {
+	t := p.peek()
+	if _, ok := p.accept("*"); ok {
+		return selectItem{pos: t.pos, star: true}, nil
+	}
+	if t.kind == tokIdent && p.peekAt(1).text == "." && p.peekAt(2).text == "*" && p.peekAt(2).kind == tokOp {
+		p.pos += 3
+		return selectItem{pos: t.pos, star: true, table: t.text}, nil
+	}
+	e, err := p.parseExpr()
+	if err != nil {
+		return selectItem{}, err
+	}
+	item := selectItem{pos: t.pos, e: e}
+	_, as := p.accept("AS")
+	if a := p.peek(); as || a.kind == tokIdent {
+		a, err := p.ident("column alias")
+		if err != nil {
+			return selectItem{}, err
+		}
+		item.alias = a.text
+	}
+	return item, nil
+}
+
+func (p *parser) parseTableRef() (tableRef, error) {
+	t := p.peek()
+	if t.kind == tokOp && t.text == "(" {
+		return tableRef{}, errorf(t.pos, "subqueries are not supported")
+	}
+	name, err := p.ident("table name")
+	if err != nil {
+		return tableRef{}, err
+	}
+	ref := tableRef{pos: name.pos, name: name.text, alias: name.text}
+	_, as := p.accept("AS")
+	if a := p.peek(); as || a.kind == tokIdent {
+		a, err := p.ident("table alias")
+		if err != nil {
+			return tableRef{}, err
+		}
+		ref.alias = a.text
+	}
+	return ref, nil
+}
+
+// parseJoin parses a join with the next table, if any. Tables separated by
+// commas are cross joined.
+func (p *parser) parseJoin() (joinClause, bool, error) {
+	t := p.peek()
+	j := joinClause{pos: t.pos, how: df.JoinInner}
+	switch {
+	case t.kind == tokOp && t.text == ",":
+		p.next()
+		j.how = df.JoinCross
+	case t.kind != tokKeyword:
+		return j, false, nil
+	case t.text == "JOIN":
+		p.next()
+	case t.text == "INNER" || t.text == "CROSS" || t.text == "LEFT" || t.text == "RIGHT":
+		p.next()
+		switch t.text {
+		case "CROSS":
+			j.how = df.JoinCross
+		case "LEFT":
+			j.how = df.JoinLeft
+		case "RIGHT":
+			j.how = df.JoinRight
+		}
+		if t.text == "LEFT" || t.text == "RIGHT" {
+			p.accept("OUTER")
+		}
+		if _, err := p.expect("JOIN"); err != nil {
+			return j, false, err
+		}
+	case t.text == "FULL" || t.text == "NATURAL":
+		return j, false, errorf(t.pos, "%s joins are not supported", t.text)
+	default:
+		return j, false, nil
+	}
+
+	table, err := p.parseTableRef()
+	if err != nil {
+		return j, false, err
+	}
+	j.table = table
+	if j.how == df.JoinCross {
+		return j, true, nil
+	}
+	if t, ok := p.accept("USING"); ok {
+		return j, false, errorf(t.pos, "USING is not supported, use ON")
+	}
+	if _, err := p.expect("ON"); err != nil {
+		return j, false, err
+	}
+	if j.on, err = p.parseExpr(); err != nil {
+		return j, false, err
+	}
+	return j, true, nil
+}
+
+func (p *parser) parseOrderItem() (orderItem, error) {
+	e, err := p.parseExpr()
+	if err != nil {
+		return orderItem{}, err
+	}
+	item := orderItem{e: e}
+	if t, ok := p.accept("ASC", "DESC"); ok {
+		item.desc = t.text == "DESC"
+	}
+	if _, ok := p.accept("NULLS"); ok {
+		t, ok := p.accept("FIRST", "LAST")
+		if !ok {
+			return orderItem{}, errorf(t.pos, "expected FIRST or LAST but found %s", t.describe())
+		}
+		item.naFirst = t.text == "FIRST"
+	}
+	return item, nil
+}
+
+// parseCount parses the non negative integer of LIMIT and OFFSET
+func (p *parser) parseCount(clause string) (int, error) {
+	t := p.next()
+	n, err := strconv.Atoi(t.text)
+	if t.kind != tokNumber || err != nil || n < 0 {
+		return 0, errorf(t.pos, "%s expects a non negative integer but found %s", clause, t.describe())
+	}
+	return n, nil
+}
+
+func (p *parser) parseExpr() (expr, error) {
+	return p.parseOr()
+}
+
+func (p *parser) parseOr() (expr, error) {
+	l, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		t, ok := p.accept("OR")
+		if !ok {
+			return l, nil
+		}
+		r, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		l = binaryExpr{t.pos, "OR", l, r}
+	}
+}
+
+func (p *parser) parseAnd() (expr, error) {
+	l, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		t, ok := p.accept("AND")
+		if !ok {
+			return l, nil
+		}
+		r, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		l = binaryExpr{t.pos, "AND", l, r}
+	}
+}
+
+func (p *parser) parseNot() (expr, error) {
+	if t, ok := p.accept("NOT"); ok {
+		x, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return unaryExpr{t.pos, "NOT", x}, nil
+	}
+	return p.parsePredicate()
+}
+
+// parsePredicate parses comparisons, IS NULL, IN and BETWEEN. IN and BETWEEN
+// are rewritten as comparisons.
+func (p *parser) parsePredicate() (expr, error) {
+	l, err := p.parseAdditive()
+	if err != nil {
+		return nil, err
+	}
+	if t, ok := p.accept("IS"); ok {
+		_, not := p.accept("NOT")
+		if _, err := p.expect("NULL"); err != nil {
+			return nil, err
+		}
+		return isNullExpr{t.pos, l, not}, nil
+	}
+	if t, ok := p.accept("=", "<>", "!=", "<", "<=", ">", ">="); ok {
+		r, err := p.parseAdditive()
+		if err != nil {
+			return nil, err
+		}
+		op := t.text
+		if op == "!=" {
+			op = "<>"
+		}
+		return binaryExpr{t.pos, op, l, r}, nil
+	}
+
+	not, ok := p.accept("NOT")
+	t := p.peek()
+	if t.kind != tokKeyword {
+		if ok {
+			return nil, errorf(t.pos, "expected IN, BETWEEN or LIKE but found %s", t.describe())
+		}
+		return l, nil
+	}
+	var e expr
+	switch t.text {
+	case "IN":
+		p.next()
+		if _, err := p.expect("("); err != nil {
+			return nil, err
+		}
+		if s := p.peek(); s.kind == tokKeyword && s.text == "SELECT" {
+			return nil, errorf(s.pos, "subqueries are not supported")
+		}
+		for {
+			v, err := p.parseAdditive()
+			if err != nil {
+				return nil, err
+			}
+			eq := binaryExpr{t.pos, "=", l, v}
+			if e == nil {
+				e = eq
+			} else {
+				e = binaryExpr{t.pos, "OR", e, eq}
+			}
+			if _, ok := p.accept(","); !ok {
+				break
+			}
+		}
+		if _, err := p.expect(")"); err != nil {
+			return nil, err
+		}
+	case "BETWEEN":
+		p.next()
+		lo, err := p.parseAdditive()
+		if err != nil {
+			return nil, err
+		}
+		if _, err := p.expect("AND"); err != nil {
+			return nil, err
+		}
+		hi, err := p.parseAdditive()
+		if err != nil {
+			return nil, err
+		}
+		e = binaryExpr{t.pos, "AND", binaryExpr{t.pos, ">=", l, lo}, binaryExpr{t.pos, "<=", l, hi}}
+	case "LIKE":
+		return nil, errorf(t.pos, "LIKE is not supported")
+	default:
+		if ok {
+			return nil, errorf(t.pos, "expected IN, BETWEEN or LIKE but found %s", t.describe())
+		}
+		return l, nil
+	}
+	if ok {
+		e = unaryExpr{not.pos, "NOT", e}
+	}
+	return e, nil
+}
+
+func (p *parser) parseAdditive() (expr, error) {
+	l, err := p.parseMultiplicative()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		t, ok := p.accept("+", "-", "||")
+		if !ok {
+			return l, nil
+		}
+		if t.text == "||" {
+			return nil, errorf(t.pos, "string concatenation is not supported")
+		}
+		r, err := p.parseMultiplicative()
+		if err != nil {
+			return nil, err
+		}
+		l = binaryExpr{t.pos, t.text, l, r}
+	}
+}
+
+func (p *parser) parseMultiplicative() (expr, error) {
+	l, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		t, ok := p.accept("*", "/", "%")
+		if !ok {
+			return l, nil
+		}
+		r, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		l = binaryExpr{t.pos, t.text, l, r}
+	}
+}
+
+func (p *parser) parseUnary() (expr, error) {
+	if t, ok := p.accept("-", "+"); ok {
+		x, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		if t.text == "+" {
+			return x, nil
+		}
+		return unaryExpr{t.pos, "-", x}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (expr, error) {
+	t := p.next()
+	switch t.kind {
+	case tokNumber:
+		return literal{t.pos, litNumber, t.text}, nil
+	case tokString:
+		return literal{t.pos, litString, t.text}, nil
+	case tokIdent:
+		if n := p.peek(); n.kind == tokOp && n.text == "(" {
+			return p.parseCall(t)
+		}
+		if n := p.peek(); n.kind == tokOp && n.text == "." {
+			p.next()
+			name, err := p.ident("column name")
+			if err != nil {
+				return nil, err
+			}
+			return colRef{t.pos, t.text, name.text}, nil
+		}
+		return colRef{t.pos, "", t.text}, nil
+	case tokKeyword:
+		switch t.text {
+		case "TRUE", "FALSE":
+			return literal{t.pos, litBool, t.text}, nil
+		case "NULL":
+			return literal{t.pos, litNull, t.text}, nil
+		case "CASE":
+			return p.parseCase(t)
+		case "EXISTS":
+			return nil, errorf(t.pos, "subqueries are not supported")
+		}
+	case tokOp:
+		if t.text == "(" {
+			if s := p.peek(); s.kind == tokKeyword && s.text == "SELECT" {
+				return nil, errorf(s.pos, "subqueries are not supported")
+			}
+			e, err := p.parseExpr()
+			if err != nil {
+				return nil, err
+			}
+			if _, err := p.expect(")"); err != nil {
+				return nil, err
+			}
+			return e, nil
+		}
+	case tokEOF:
+		return nil, errorf(t.pos, "unexpected end of query")
+	}
+	return nil, errorf(t.pos, "unexpected %s", t.describe())
+}
+
+func (p *parser) parseCall(name token) (expr, error) {
+	p.next()
+	call := callExpr{pos: name.pos, name: name.text}
+	if t, ok := p.accept("DISTINCT"); ok {
+		return nil, errorf(t.pos, "DISTINCT aggregates are not supported")
+	}
+	if _, ok := p.accept("*"); ok {
+		if !strings.EqualFold(name.text, "count") {
+			return nil, errorf(name.pos, "only COUNT accepts * as argument")
+		}
+		call.star = true
+		if _, err := p.expect(")"); err != nil {
+			return nil, err
+		}
+	} else if _, ok := p.accept(")"); !ok {
+		for {
+			a, err := p.parseExpr()
+			if err != nil {
+				return nil, err
+			}
+			call.args = append(call.args, a)
+			if _, ok := p.accept(","); ok {
+				continue
+			}
+			if _, err := p.expect(")"); err != nil {
+				return nil, err
+			}
+			break
+		}
+	}
+	if t, ok := p.accept("OVER"); ok {
+		return nil, errorf(t.pos, "window functions are not supported")
+	}
+	return call, nil
+}
+
+func (p *parser) parseCase(t token) (expr, error) {
+	c := caseExpr{pos: t.pos}
+	if s := p.peek(); s.kind != tokKeyword || s.text != "WHEN" {
+		return nil, errorf(s.pos, "only searched CASE expressions (CASE WHEN condition THEN ...) are supported")
+	}
+	for {
+		if _, ok := p.accept("WHEN"); !ok {
+			break
+		}
+		cond, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		if _, err := p.expect("THEN"); err != nil {
+			return nil, err
+		}
+		val, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		c.whens = append(c.whens, whenClause{cond, val})
+	}
+	if _, ok := p.accept("ELSE"); ok {
+		els, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		c.els = els
+	}
+	if _, err := p.expect("END"); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// precedence of the binary operators, used to format expressions
+var precedence = map[string]int{
+	"OR": 1, "AND": 2,
+	"=": 4, "<>": 4, "<": 4, "<=": 4, ">": 4, ">=": 4,
+	"+": 5, "-": 5, "*": 6, "/": 6, "%": 6,
+}
+
+// format returns an expression as SQL, used to name the columns of the
+// select list without an alias
+func format(e expr) string {
+	switch e := e.(type) {
+	case literal:
+		if e.kind == litString {
+			return "'" + strings.Replace(e.text, "'", "''", -1) + "'"
+		}
+		return e.text
+	case colRef:
+		if e.table != "" {
+			return e.table + "." + e.name
+		}
+		return e.name
+	case unaryExpr:
+		if e.op == "NOT" {
+			return "NOT " + formatOperand(e.x, 3, false)
+		}
+		return "-" + formatOperand(e.x, 7, false)
+	case binaryExpr:
+		prec := precedence[e.op]
+		return formatOperand(e.l, prec, false) + " " + e.op + " " + formatOperand(e.r, prec, true)
+	case isNullExpr:
+		if e.not {
+			return formatOperand(e.x, 4, false) + " IS NOT NULL"
+		}
+		return formatOperand(e.x, 4, false) + " IS NULL"
+	case callExpr:
+		if e.star {
+			return e.name + "(*)"
+		}
+		args := make([]string, 0, len(e.args))
+		for _, a := range e.args {
+			args = append(args, format(a))
+		}
+		return e.name + "(" + strings.Join(args, ", ") + ")"
+	case caseExpr:
+		s := "CASE"
+		for _, w := range e.whens {
+			s += " WHEN " + format(w.cond) + " THEN " + format(w.val)
+		}
+		if e.els != nil {
+			s += " ELSE " + format(e.els)
+		}
+		return s + " END"
+	}
+	return ""
+}
+
+// formatOperand formats an operand of an operator with the given precedence,
+// adding parentheses when needed
+func formatOperand(e expr, prec int, right bool) string {
+	s := format(e)
+	p := 8
+	switch e := e.(type) {
+	case binaryExpr:
+		p = precedence[e.op]
+	case unaryExpr:
+		if e.op == "NOT" {
+			p = 3
+		}
+	case isNullExpr:
+		p = 4
+	}
+	if p < prec || (right && p == prec) {
+		return "(" + s + ")"
+	}
+	return s
+}
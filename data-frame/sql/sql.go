@@ -0,0 +1,109 @@
+// Package sql runs SQL queries over DataFrames registered as tables.
+//
+// Queries are compiled onto the operations of the df package: FROM and JOIN
+// build a lazy query plan, so WHERE conditions are pushed down to the tables
+// when possible, GROUP BY is computed with GroupBy, expressions are evaluated
+// with Mutate and ORDER BY uses Arrange. The supported dialect is
+//
+//	SELECT [DISTINCT] * | table.* | expr [[AS] alias], ...
+//	FROM table [[AS] alias]
+//	    [[INNER | LEFT [OUTER] | RIGHT [OUTER]] JOIN table [[AS] alias] ON a.x = b.y [AND ...]]
+//	    [CROSS JOIN table | , table]
+//	[WHERE condition]
+//	[GROUP BY expr, ...]
+//	[HAVING condition]
+//	[ORDER BY expr [ASC | DESC] [NULLS FIRST | LAST], ...]
+//	[LIMIT n] [OFFSET n]
+//
+// Expressions support arithmetic, comparisons, AND, OR, NOT, IS [NOT] NULL,
+// [NOT] IN (...), [NOT] BETWEEN, searched CASE expressions, the functions
+// ABS, ROUND, LN, COALESCE and IFNULL, and the aggregate functions COUNT,
+// SUM, AVG, MIN, MAX and STDDEV. Aggregations other than COUNT return
+// floats. NULL stands for NA elements and comparisons with NA are never
+// true.
+//
+// Table and column names are case sensitive and can be quoted with double
+// quotes or backquotes. Errors on a query are returned as an *Error with the
+// line and column where they were found.
+package sql
+
+import (
+	"errors"
+	"sort"
+	"sync"
+
+	"github.com/kniren/gota/data-frame"
+)
+
+// DB is a set of DataFrames registered as tables. It can be queried and
+// modified by several goroutines at the same time.
+type DB struct {
+	mu     sync.RWMutex
+	tables map[string]df.DataFrame
+}
+
+// NewDB returns a DB without tables
+func NewDB() *DB {
+	return &DB{tables: map[string]df.DataFrame{}}
+}
+
+// Register adds a DataFrame as a table with the given name, replacing the
+// previous table with that name if any. Queries see the DataFrame as it was
+// when it was registered.
+func (db *DB) Register(name string, d df.DataFrame) error {
+	if name == "" {
+		return errors.New("Empty table name")
+	}
+	// A snapshot owns its column maps, so later changes to d made through
+	// pointer methods don't affect the table
+	snapshot := df.NewShared(d).Load()
+	db.mu.Lock()
+	db.tables[name] = snapshot
+	db.mu.Unlock()
+	return nil
+}
+
+// Drop removes the table with the given name
+func (db *DB) Drop(name string) {
+	db.mu.Lock()
+	delete(db.tables, name)
+	db.mu.Unlock()
+}
+
+// Tables returns the names of the registered tables, sorted
+func (db *DB) Tables() []string {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+	names := make([]string, 0, len(db.tables))
+	for k := range db.tables {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Table returns the DataFrame registered with the given name
+func (db *DB) Table(name string) (df.DataFrame, bool) {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+	d, ok := db.tables[name]
+	return d, ok
+}
+
+// Query runs a SELECT query and returns its result. Queries that select no
+// rows return a DataFrame with the selected columns and no rows.
+func (db *DB) Query(query string) (*df.DataFrame, error) {
+	stmt, err := parse(query)
+	if err != nil {
+		return nil, err
+	}
+	// The query reads the tables as they are when it starts
+	db.mu.RLock()
+	tables := make(map[string]df.DataFrame, len(db.tables))
+	for k, v := range db.tables {
+		tables[k] = v
+	}
+	db.mu.RUnlock()
+	c := &compiler{tables: tables, stmt: stmt}
+	return c.run()
+}
@@ -0,0 +1,356 @@
+package sql
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/kniren/gota/data-frame"
+)
+
+func testDB(t *testing.T) *DB {
+	customers, err := df.New(
+		df.C{"ID", df.Ints(1, 2, 3, 4)},
+		df.C{"Name", df.Strings("Alice", "Bob", "Carol", "Dan")},
+		df.C{"City", df.Strings("Oslo", "Rome", "Oslo", nil)},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	orders, err := df.New(
+		df.C{"OrderID", df.Ints(10, 11, 12, 13, 14)},
+		df.C{"CustomerID", df.Ints(1, 1, 2, 3, 5)},
+		df.C{"Amount", df.Floats(5.5, 4.5, 20, nil, 7)},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	db := NewDB()
+	db.Register("customers", *customers)
+	db.Register("orders", *orders)
+	return db
+}
+
+func TestDB_Query(t *testing.T) {
+	db := testDB(t)
+	var tests = []struct {
+		query    string
+		expected string
+		types    string
+	}{
+		{
+			"SELECT * FROM customers",
+			"[[ID Name City] [1 Alice Oslo] [2 Bob Rome] [3 Carol Oslo] [4 Dan NA]]",
+			"[df.Int df.String df.String]",
+		},
+		{
+			"SELECT Name, City FROM customers WHERE City = 'Oslo' OR City IS NULL ORDER BY Name DESC",
+			"[[Name City] [Dan NA] [Carol Oslo] [Alice Oslo]]",
+			"[df.String df.String]",
+		},
+		{
+			"SELECT c.Name, o.Amount FROM customers c JOIN orders o ON c.ID = o.CustomerID WHERE o.Amount > 5",
+			"[[Name Amount] [Alice 5.5] [Bob 20]]",
+			"[df.String df.Float]",
+		},
+		{
+			"SELECT c.Name, o.OrderID FROM customers AS c LEFT OUTER JOIN orders AS o ON o.CustomerID = c.ID",
+			"[[Name OrderID] [Alice 10] [Alice 11] [Bob 12] [Carol 13] [Dan NA]]",
+			"[df.String df.Int]",
+		},
+		{
+			"SELECT c.ID, o.CustomerID FROM customers c RIGHT JOIN orders o ON o.CustomerID = c.ID",
+			"[[ID CustomerID] [1 1] [1 1] [2 2] [3 3] [NA 5]]",
+			"[df.Int df.Int]",
+		},
+		{
+			`SELECT City, COUNT(*) AS n, SUM(Amount) total, avg(o.Amount)
+			FROM customers c
+			JOIN orders o ON c.ID = o.CustomerID
+			GROUP BY City
+			HAVING COUNT(*) > 0
+			ORDER BY total DESC`,
+			"[[City n total avg(o.Amount)] [Rome 1 20 20] [Oslo 3 10 5]]",
+			"[df.String df.Int df.Float df.Float]",
+		},
+		{
+			"SELECT City, SUM(Amount) / COUNT(Amount) AS mean FROM customers JOIN orders ON ID = CustomerID GROUP BY City HAVING MAX(Amount) < 10",
+			"[[City mean] [Oslo 5]]",
+			"[df.String df.Float]",
+		},
+		{
+			"SELECT COUNT(*), MAX(Amount) FROM orders WHERE Amount > 100",
+			"[[COUNT(*) MAX(Amount)] [0 NA]]",
+			"[df.Int df.Float]",
+		},
+		{
+			"SELECT City FROM customers GROUP BY City",
+			"[[City] [Oslo] [Rome] [NA]]",
+			"[df.String]",
+		},
+		{
+			"SELECT ID % 2 AS parity, count(*) FROM customers GROUP BY parity ORDER BY 1",
+			"[[parity count(*)] [0 2] [1 2]]",
+			"[df.Int df.Int]",
+		},
+		{
+			"SELECT DISTINCT City FROM customers ORDER BY City NULLS FIRST",
+			"[[City] [NA] [Oslo] [Rome]]",
+			"[df.String]",
+		},
+		{
+			"SELECT *, c.ID * 2 AS double FROM customers c, orders o WHERE c.ID = o.CustomerID LIMIT 2 OFFSET 1",
+			"[[ID Name City OrderID CustomerID Amount double] [1 Alice Oslo 11 1 4.5 2] [2 Bob Rome 12 2 20 4]]",
+			"[df.Int df.String df.String df.Int df.Int df.Float df.Int]",
+		},
+		{
+			"SELECT o.*, ID FROM orders o JOIN customers c ON c.ID = o.CustomerID AND c.ID = o.CustomerID ORDER BY Amount NULLS FIRST, OrderID DESC",
+			"[[OrderID CustomerID Amount ID] [13 3 NA 3] [11 1 4.5 1] [10 1 5.5 1] [12 2 20 2]]",
+			"[df.Int df.Int df.Float df.Int]",
+		},
+		{
+			"SELECT Name, CASE WHEN City = 'Oslo' THEN 1 WHEN City = 'Rome' THEN 2 ELSE 0 END AS code FROM customers WHERE ID IN (1, 2, 4)",
+			"[[Name code] [Alice 1] [Bob 2] [Dan 0]]",
+			"[df.String df.Int]",
+		},
+		{
+			"SELECT Name, ID + 1 FROM customers WHERE NOT ID BETWEEN 2 AND 3 ORDER BY ID + 1 DESC -- comment",
+			"[[Name ID + 1] [Dan 5] [Alice 2]]",
+			"[df.String df.Int]",
+		},
+		{
+			"SELECT \"Name\" AS `the name` FROM customers WHERE ID NOT IN (1, 2) AND coalesce(City, 'none') <> 'Oslo';",
+			"[[the name] [Dan]]",
+			"[df.String]",
+		},
+		{
+			"SELECT Name FROM customers ORDER BY ID LIMIT 0",
+			"[[Name]]",
+			"[df.String]",
+		},
+		{
+			"SELECT Name FROM customers WHERE Name = 'Nobody'",
+			"[[Name]]",
+			"[df.String]",
+		},
+		{
+			"SELECT City, COUNT(*) FROM customers WHERE Name = 'Nobody' GROUP BY City",
+			"[[City COUNT(*)]]",
+			"[df.String df.Int]",
+		},
+	}
+	for k, v := range tests {
+		d, err := db.Query(v.query)
+		if err != nil {
+			t.Error("Test", k, ":", err)
+			continue
+		}
+		received := fmt.Sprint(d.SaveRecords())
+		types := fmt.Sprint(d.Types())
+		if received != v.expected || types != v.types {
+			t.Error(
+				"Test", k, "\n",
+				"Expected:\n",
+				v.expected, v.types, "\n",
+				"Received:\n",
+				received, types,
+			)
+		}
+	}
+}
+
+func TestDB_QueryCategorical(t *testing.T) {
+	size, _ := df.CategoricalsWithLevels([]string{"S", "M", "L"}, true, "M", "S", "L", nil)
+	items, err := df.New(
+		df.C{"ID", df.Ints(1, 2, 3, 4)},
+		df.C{"Color", df.Categoricals("red", "blue", "red", nil)},
+		df.C{"Size", size},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	db := NewDB()
+	db.Register("items", *items)
+	var tests = []struct {
+		query    string
+		expected string
+	}{
+		{"SELECT ID FROM items WHERE Color = 'red'", "[[ID] [1] [3]]"},
+		{"SELECT ID FROM items WHERE Color <> 'red'", "[[ID] [2]]"},
+		{"SELECT ID FROM items WHERE Color = 'green'", "[[ID]]"},
+		{"SELECT ID FROM items WHERE Size >= 'M' AND Color = 'red'", "[[ID] [1] [3]]"},
+	}
+	for k, v := range tests {
+		d, err := db.Query(v.query)
+		if err != nil {
+			t.Error("Test", k, ":", err)
+			continue
+		}
+		received := fmt.Sprint(d.SaveRecords())
+		if received != v.expected {
+			t.Error(
+				"Test", k, "\n",
+				"Expected:\n",
+				v.expected, "\n",
+				"Received:\n",
+				received,
+			)
+		}
+	}
+
+	// Labels out of the levels are only found while evaluating the rows
+	errors := []struct {
+		query    string
+		expected string
+	}{
+		{"SELECT ID FROM items WHERE Size > 'XL'",
+			"sql error at line 1, column 28: can't compare M with \"XL\""},
+		{"SELECT ID, Size > 'XL' FROM items",
+			"sql error at line 1, column 12: can't compare M with \"XL\""},
+		{"SELECT Size FROM items GROUP BY Size HAVING Size > 'XL'",
+			"sql error at line 1, column 45: can't compare M with \"XL\""},
+	}
+	for k, v := range errors {
+		_, err := db.Query(v.query)
+		if err == nil {
+			t.Error("Test", k, ": expected an error on", v.query)
+			continue
+		}
+		if received := err.Error(); received != v.expected {
+			t.Error(
+				"Test", k, "\n",
+				"Expected:\n",
+				v.expected, "\n",
+				"Received:\n",
+				received,
+			)
+		}
+		if _, ok := err.(*Error); !ok {
+			t.Errorf("Test %d: expected an *Error, received %T", k, err)
+		}
+	}
+}
+
+func TestDB_QueryErrors(t *testing.T) {
+	db := testDB(t)
+	var tests = []struct {
+		query    string
+		expected string
+	}{
+		{"SELECT Name FROM customers WHERE Name > 1",
+			"sql error at line 1, column 39: can't compare types df.String and df.Int"},
+		{"SELECT Name FROM customers WHERE ID = 'abc'",
+			"sql error at line 1, column 37: can't compare df.Int with \"abc\""},
+		{"SELECT Name FROM customers WHERE ID + Name > 1",
+			"sql error at line 1, column 37: arithmetic operation + over non numeric types df.Int and df.String"},
+		{"SELECT Name\nFROM customers\nWHERE ID = 1 AND Name",
+			"sql error at line 3, column 14: logical operation over non boolean type df.String"},
+		{"SELECT Name FROM customers WHERE ID",
+			"sql error at line 1, column 34: WHERE condition must be boolean"},
+		{"SELECT Nam FROM customers",
+			"sql error at line 1, column 8: unknown column Nam"},
+		{"SELECT x.Name FROM customers",
+			"sql error at line 1, column 8: unknown table x"},
+		{"SELECT Name FROM customer",
+			"sql error at line 1, column 18: unknown table customer"},
+		{"SELECT ID FROM customers c JOIN customers d ON c.ID = d.ID",
+			"sql error at line 1, column 8: column ID is ambiguous, it is on tables c and d"},
+		{"SELECT c.ID FROM customers c JOIN customers c ON c.ID = c.ID",
+			"sql error at line 1, column 35: table name c is used twice, give it an alias"},
+		{"SELECT Name, COUNT(*) FROM customers GROUP BY City",
+			"sql error at line 1, column 8: column Name must appear in the GROUP BY clause or be used in an aggregate function"},
+		{"SELECT City FROM customers GROUP BY City HAVING ID > 1",
+			"sql error at line 1, column 49: column ID must appear in the GROUP BY clause or be used in an aggregate function"},
+		{"SELECT SUM(Name) FROM customers",
+			"sql error at line 1, column 8: can't compute SUM over elements of type df.String"},
+		{"SELECT SUM(SUM(ID)) FROM customers",
+			"sql error at line 1, column 12: aggregate functions can't be nested"},
+		{"SELECT * FROM customers GROUP BY City",
+			"sql error at line 1, column 8: * can't be used with GROUP BY or aggregate functions"},
+		{"SELECT Name FROM customers WHERE COUNT(*) > 1",
+			"sql error at line 1, column 34: aggregate functions are not allowed in WHERE"},
+		{"SELECT Name FROM customers WHERE Name LIKE 'A%'",
+			"sql error at line 1, column 39: LIKE is not supported"},
+		{"SELECT Name FROM customers c FULL JOIN orders o ON c.ID = o.CustomerID",
+			"sql error at line 1, column 30: FULL joins are not supported"},
+		{"SELECT Name FROM customers c JOIN orders o USING (ID)",
+			"sql error at line 1, column 44: USING is not supported, use ON"},
+		{"SELECT Name FROM customers c JOIN orders o ON c.ID > o.CustomerID",
+			"sql error at line 1, column 47: JOIN conditions must be equalities between columns joined with AND"},
+		{"SELECT Name FROM customers c JOIN orders o ON c.ID = c.ID",
+			"sql error at line 1, column 52: JOIN condition must compare a column of o with a column of a previous table"},
+		{"SELECT Name FROM customers c JOIN orders o ON c.Name = o.CustomerID",
+			"sql error at line 1, column 54: can't join c.Name of type df.String with o.CustomerID of type df.Int"},
+		{"SELECT Name FROM customers ORDER BY 3",
+			"sql error at line 1, column 37: ORDER BY position 3 is not on the select list"},
+		{"SELECT DISTINCT Name FROM customers ORDER BY ID",
+			"sql error at line 1, column 46: ORDER BY expressions must appear on the select list of a SELECT DISTINCT"},
+		{"SELECT Name, Name FROM customers",
+			"sql error at line 1, column 14: duplicate column name Name on the select list, use AS to rename it"},
+		{"SELECT Name FROM customers LIMIT -1",
+			"sql error at line 1, column 34: LIMIT expects a non negative integer but found \"-\""},
+		{"SELECT Name FROM (SELECT * FROM customers)",
+			"sql error at line 1, column 18: subqueries are not supported"},
+		{"SELECT Name FROM customers WHERE ID IN (SELECT CustomerID FROM orders)",
+			"sql error at line 1, column 41: subqueries are not supported"},
+		{"SELECT foo(Name) FROM customers",
+			"sql error at line 1, column 8: unknown function foo"},
+		{"SELECT NULL FROM customers",
+			"sql error at line 1, column 8: can't infer the type of the expression"},
+		{"SELECT 'abc FROM customers",
+			"sql error at line 1, column 8: unterminated '"},
+		{"SELECT Name FROM customers UNION SELECT Name FROM customers",
+			"sql error at line 1, column 28: UNION is not supported"},
+		{"SELECT Name, FROM customers",
+			"sql error at line 1, column 14: unexpected FROM"},
+		{"SELECT Name customers",
+			"sql error at line 1, column 22: expected FROM but found end of query"},
+		{"SELECT Name FROM customers WHERE ID = 1 ORDER Name",
+			"sql error at line 1, column 47: expected BY but found \"Name\""},
+		{"SELECT ROW_NUMBER() OVER () FROM customers",
+			"sql error at line 1, column 21: window functions are not supported"},
+		{"SELECT Name FROM customers WHERE ID ? 1",
+			"sql error at line 1, column 37: unexpected character '?'"},
+	}
+	for k, v := range tests {
+		_, err := db.Query(v.query)
+		if err == nil {
+			t.Error("Test", k, ": expected an error on", v.query)
+			continue
+		}
+		if received := err.Error(); received != v.expected {
+			t.Error(
+				"Test", k, "\n",
+				"Expected:\n",
+				v.expected, "\n",
+				"Received:\n",
+				received,
+			)
+		}
+		if _, ok := err.(*Error); !ok {
+			t.Errorf("Test %d: expected an *Error, received %T", k, err)
+		}
+	}
+}
+
+func TestDB_Tables(t *testing.T) {
+	db := testDB(t)
+	if received := fmt.Sprint(db.Tables()); received != "[customers orders]" {
+		t.Error("Unexpected tables:", received)
+	}
+
+	// Tables are snapshots of the registered DataFrames
+	d, _ := df.New(df.C{"A", df.Ints(1, 2)})
+	db.Register("t", *d)
+	d.SetNames([]string{"B"})
+	if _, err := db.Query("SELECT A FROM t"); err != nil {
+		t.Error(err)
+	}
+
+	db.Drop("t")
+	if _, ok := db.Table("t"); ok {
+		t.Error("Table not dropped")
+	}
+	if err := db.Register("", *d); err == nil {
+		t.Error("Expected an error registering a table without name")
+	}
+}
@@ -0,0 +1,241 @@
+package df
+
+import (
+	"errors"
+	"math"
+	"sort"
+)
+
+// numericValues returns the values of the non NA elements of a set of numeric
+// cells
+func numericValues(cells Cells) ([]float64, error) {
+	values := make([]float64, 0, len(cells))
+	for _, c := range cells {
+		if c.IsNA() {
+			continue
+		}
+		if numericKind(c) == kindNone {
+			return nil, errors.New("Can't compute statistics over non numeric elements")
+		}
+		f, err := c.Float()
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, *f)
+	}
+	return values, nil
+}
+
+// Sum returns the sum of the non NA elements of a set of numeric cells
+func Sum(cells Cells) (float64, error) {
+	values, err := numericValues(cells)
+	if err != nil {
+		return 0, err
+	}
+	sum := 0.0
+	for _, v := range values {
+		sum += v
+	}
+	return sum, nil
+}
+
+// Mean returns the arithmetic mean of the non NA elements of a set of numeric
+// cells. If all the elements are NA the result is NaN.
+func Mean(cells Cells) (float64, error) {
+	values, err := numericValues(cells)
+	if err != nil {
+		return 0, err
+	}
+	return mean(values), nil
+}
+
+func mean(values []float64) float64 {
+	if len(values) == 0 {
+		return math.NaN()
+	}
+	sum := 0.0
+	for _, v := range values {
+		sum += v
+	}
+	return sum / float64(len(values))
+}
+
+// Var returns the sample variance of the non NA elements of a set of numeric
+// cells. If there are less than two non NA elements the result is NaN.
+func Var(cells Cells) (float64, error) {
+	values, err := numericValues(cells)
+	if err != nil {
+		return 0, err
+	}
+	return variance(values), nil
+}
+
+func variance(values []float64) float64 {
+	if len(values) < 2 {
+		return math.NaN()
+	}
+	m := mean(values)
+	ss := 0.0
+	for _, v := range values {
+		ss += (v - m) * (v - m)
+	}
+	return ss / float64(len(values)-1)
+}
+
+// Median returns the median of the non NA elements of a set of numeric cells.
+// If all the elements are NA the result is NaN.
+func Median(cells Cells) (float64, error) {
+	return Quantile(cells, 0.5)
+}
+
+// Quantile returns the q-th quantile of the non NA elements of a set of
+// numeric cells, interpolating linearly between the closest elements. q must
+// be between 0 and 1. If all the elements are NA the result is NaN.
+func Quantile(cells Cells, q float64) (float64, error) {
+	if q < 0 || q > 1 || math.IsNaN(q) {
+		return 0, errors.New("Quantile must be between 0 and 1")
+	}
+	values, err := numericValues(cells)
+	if err != nil {
+		return 0, err
+	}
+	sort.Float64s(values)
+	return quantile(values, q), nil
+}
+
+// quantile returns the q-th quantile of a sorted set of values
+func quantile(sorted []float64, q float64) float64 {
+	if len(sorted) == 0 {
+		return math.NaN()
+	}
+	pos := q * float64(len(sorted)-1)
+	lo := int(math.Floor(pos))
+	hi := int(math.Ceil(pos))
+	return sorted[lo] + (pos-float64(lo))*(sorted[hi]-sorted[lo])
+}
+
+// describeNames are the columns of the DataFrame returned by Describe
+var describeNames = []string{
+	"Column", "Type", "Count", "NA", "Mean", "Std", "Min", "25%", "50%",
+	"75%", "Max", "Unique", "Top", "Freq", "True", "False",
+}
+
+// Describe returns a DataFrame with a summary of each column of the
+// DataFrame, one row per column. All columns get the number of non NA
+// elements and the number of NA elements. Numeric columns get their mean,
+// standard deviation, minimum, quartiles and maximum. String and Categorical
+// columns get the number of unique elements and the most frequent element
+// with its frequency. Bool columns get the ratio of true and false elements.
+// Statistics that don't apply to a column are NA.
+func (df DataFrame) Describe() (*DataFrame, error) {
+	if df.NCols() == 0 {
+		return nil, errors.New("Empty DataFrame")
+	}
+	stats := make(map[string]Cells, len(describeNames))
+	add := func(name string, c Cell) {
+		stats[name] = append(stats[name], c)
+	}
+	naFloat := Float{nil}
+	naInt := Int{nil}
+	float := func(f float64) Cell {
+		if math.IsNaN(f) {
+			return naFloat
+		}
+		return Float{&f}
+	}
+
+	for _, k := range df.Names() {
+		col := df.Columns[k]
+		typeName := col.colType
+		if t, ok := typeOf(col.colType); ok {
+			typeName = t.Name
+		}
+		na := 0
+		for _, c := range col.cells {
+			if c.IsNA() {
+				na++
+			}
+		}
+		add("Column", Strings(k)[0])
+		add("Type", Strings(typeName)[0])
+		add("Count", Ints(len(col.cells) - na)[0])
+		add("NA", Ints(na)[0])
+
+		numeric := []string{"Mean", "Std", "Min", "25%", "50%", "75%", "Max"}
+		values, err := numericValues(col.cells)
+		if err == nil && numericKind(col.empty) != kindNone {
+			sort.Float64s(values)
+			add("Mean", float(mean(values)))
+			add("Std", float(math.Sqrt(variance(values))))
+			add("Min", float(quantile(values, 0)))
+			add("25%", float(quantile(values, 0.25)))
+			add("50%", float(quantile(values, 0.5)))
+			add("75%", float(quantile(values, 0.75)))
+			add("Max", float(quantile(values, 1)))
+		} else {
+			for _, s := range numeric {
+				add(s, naFloat)
+			}
+		}
+
+		switch col.empty.(type) {
+		case String, Categorical:
+			unique, top, freq := frequencies(col.cells)
+			add("Unique", Ints(unique)[0])
+			if freq == 0 {
+				add("Top", String{nil})
+				add("Freq", naInt)
+			} else {
+				add("Top", Strings(top)[0])
+				add("Freq", Ints(freq)[0])
+			}
+		default:
+			add("Unique", naInt)
+			add("Top", String{nil})
+			add("Freq", naInt)
+		}
+
+		if _, ok := col.empty.(Bool); ok && len(col.cells) > na {
+			t := 0
+			for _, c := range col.cells {
+				if b, _ := cellBool(c); b != nil && *b {
+					t++
+				}
+			}
+			n := float64(len(col.cells) - na)
+			add("True", float(float64(t)/n))
+			add("False", float(float64(len(col.cells)-na-t)/n))
+		} else {
+			add("True", naFloat)
+			add("False", naFloat)
+		}
+	}
+
+	columns := make([]C, 0, len(describeNames))
+	for _, k := range describeNames {
+		columns = append(columns, C{k, stats[k]})
+	}
+	return New(columns...)
+}
+
+// frequencies returns the number of unique non NA elements of a set of cells
+// and the most frequent one with its frequency. Ties are resolved in favour of
+// the element that appears first.
+func frequencies(cells Cells) (int, string, int) {
+	counts := map[[16]byte]int{}
+	for _, c := range cells {
+		if !c.IsNA() {
+			counts[c.Checksum()]++
+		}
+	}
+	top, freq := "", 0
+	for _, c := range cells {
+		if c.IsNA() {
+			continue
+		}
+		if n := counts[c.Checksum()]; n > freq {
+			top, freq = c.String(), n
+		}
+	}
+	return len(counts), top, freq
+}
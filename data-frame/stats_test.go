@@ -0,0 +1,78 @@
+package df
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+func TestStatistics(t *testing.T) {
+	cells := Cells{}
+	cells = append(cells, Ints(4, nil, 1)...)
+	cells = append(cells, Floats(2.5, nil)...)
+	cells = append(cells, Decimals(2, "2.50")...)
+
+	var tests = []struct {
+		f        func(Cells) (float64, error)
+		expected float64
+	}{
+		{Sum, 10},
+		{Mean, 2.5},
+		{Median, 2.5},
+		{Var, 1.5},
+		{func(c Cells) (float64, error) { return Quantile(c, 0) }, 1},
+		{func(c Cells) (float64, error) { return Quantile(c, 0.25) }, 2.125},
+		{func(c Cells) (float64, error) { return Quantile(c, 1) }, 4},
+	}
+	for k, v := range tests {
+		received, err := v.f(cells)
+		if err != nil {
+			t.Error("Test", k, ":", err)
+			continue
+		}
+		if math.Abs(received-v.expected) > 1e-9 {
+			t.Error("Test", k, "Expected:", v.expected, "Received:", received)
+		}
+	}
+
+	if m, err := Mean(Floats(nil, nil)); err != nil || !math.IsNaN(m) {
+		t.Error("Mean of NA elements should be NaN, received:", m, err)
+	}
+	if _, err := Sum(Strings("1", "2")); err == nil {
+		t.Error("Non numeric elements should throw an error")
+	}
+	if _, err := Quantile(cells, 1.5); err == nil {
+		t.Error("Invalid quantiles should throw an error")
+	}
+}
+
+func TestDataFrame_Describe(t *testing.T) {
+	d, _ := New(
+		C{"Name", Strings("b", "a", "a", "b", nil)},
+		C{"Age", Ints(10, 20, nil, 30, 40)},
+		C{"Adult", Bools(false, true, true, true, nil)},
+		C{"Level", Categoricals("x", "y", "y", nil, nil)},
+	)
+	dd, err := d.Describe()
+	if err != nil {
+		t.Error(err)
+	}
+	expected := "[[Column Type Count NA Mean Std Min 25% 50% 75% Max Unique Top Freq True False] " +
+		"[Name string 4 1 NA NA NA NA NA NA NA 2 b 2 NA NA] " +
+		"[Age int 4 1 25 12.909944487358056 10 17.5 25 32.5 40 NA NA NA NA NA] " +
+		"[Adult bool 4 1 NA NA NA NA NA NA NA NA NA NA 0.75 0.25] " +
+		"[Level category 3 2 NA NA NA NA NA NA NA 2 y 2 NA NA]]"
+	received := fmt.Sprint(dd.SaveRecords())
+	if expected != received {
+		t.Error(
+			"Expected:\n",
+			expected, "\n",
+			"Received:\n",
+			received,
+		)
+	}
+
+	if _, err := (DataFrame{}).Describe(); err == nil {
+		t.Error("Describing an empty DataFrame should throw an error")
+	}
+}
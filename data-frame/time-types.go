@@ -0,0 +1,193 @@
+package df
+
+import (
+	"crypto/md5"
+	"errors"
+	"strings"
+	"time"
+)
+
+// timeLayouts are the layouts tried when parsing a Time without an explicit
+// layout
+var timeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
+
+// ParseTime parses a timestamp with any of the supported layouts: RFC 3339,
+// "2006-01-02 15:04:05", "2006-01-02 15:04" and "2006-01-02". Timestamps
+// without time zone are considered UTC.
+func ParseTime(s string) (Time, error) {
+	s = strings.TrimSpace(s)
+	for _, layout := range timeLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return Time{&t}, nil
+		}
+	}
+	return Time{nil}, errors.New("Can't parse time: " + s)
+}
+
+// Time is a wrapper for time.Time to be able to implement custom methods
+type Time struct {
+	t *time.Time
+}
+
+// Copy returns a copy of a given Cell
+func (t Time) Copy() Cell {
+	if t.t == nil {
+		return Time{nil}
+	}
+	u := *t.t
+	return Time{&u}
+}
+
+// Int returns the Unix time in seconds of the Time
+func (t Time) Int() (*int, error) {
+	if t.t == nil {
+		return nil, errors.New("Empty value")
+	}
+	i := int(t.t.Unix())
+	return &i, nil
+}
+
+// Float returns the Unix time in seconds of the Time, including the fraction
+// of second
+func (t Time) Float() (*float64, error) {
+	if t.t == nil {
+		return nil, errors.New("Empty value")
+	}
+	f := float64(t.t.UnixNano()) / 1e9
+	return &f, nil
+}
+
+// Bool is not supported for Time
+func (t Time) Bool() (*bool, error) {
+	return nil, errors.New("Can't convert Time to Bool")
+}
+
+// String returns the date as "2006-01-02" if the time is midnight on UTC and
+// as RFC 3339 otherwise
+func (t Time) String() string {
+	if t.t == nil {
+		return "NA"
+	}
+	if t.t.Location() == time.UTC && t.t.Equal(truncateDay(*t.t)) {
+		return t.t.Format("2006-01-02")
+	}
+	return t.t.Format(time.RFC3339Nano)
+}
+
+// Checksum generates a pseudo-unique 16 byte array
+func (t Time) Checksum() [16]byte {
+	if t.t == nil {
+		return md5.Sum([]byte("NATime"))
+	}
+	return md5.Sum([]byte(t.t.UTC().Format(time.RFC3339Nano) + "Time"))
+}
+
+// NA returns the empty element for this type
+func (t Time) NA() Cell {
+	return Time{nil}
+}
+
+// IsNA returns true if the element is empty and viceversa
+func (t Time) IsNA() bool {
+	return t.t == nil
+}
+
+// Time returns the value of the Time
+func (t Time) Time() (*time.Time, error) {
+	if t.t == nil {
+		return nil, errors.New("Empty value")
+	}
+	u := *t.t
+	return &u, nil
+}
+
+// Times is a constructor for a Time array. Elements can be given as
+// time.Time, strings with any of the layouts supported by ParseTime or Cells,
+// which are parsed from their string representation.
+func Times(args ...interface{}) Cells {
+	ret := make(Cells, 0, len(args))
+	for _, v := range flattenArgs(args...) {
+		switch e := v.(type) {
+		case time.Time:
+			ret = append(ret, Time{&e})
+		case Time:
+			ret = append(ret, e.Copy())
+		case string:
+			t, _ := ParseTime(e)
+			ret = append(ret, t)
+		case Cell:
+			if e.IsNA() {
+				ret = append(ret, Time{nil})
+				continue
+			}
+			t, _ := ParseTime(e.String())
+			ret = append(ret, t)
+		default:
+			ret = append(ret, Time{nil})
+		}
+	}
+	return ret
+}
+
+// truncateDay returns the midnight of the day of t on its location
+func truncateDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+func timeType(name string, parse func(s string) (Cell, error)) CellType {
+	return CellType{
+		Name:  name,
+		Empty: Time{},
+		Parse: parse,
+		Compare: func(a, b Cell) int {
+			ta, tb := *a.(Time).t, *b.(Time).t
+			switch {
+			case ta.Before(tb):
+				return -1
+			case ta.After(tb):
+				return 1
+			}
+			return 0
+		},
+	}
+}
+
+func init() {
+	// Both types share the Time column type, which is described by "time"
+	// because it is registered last
+	dateType := timeType("date", func(s string) (Cell, error) {
+		t, err := ParseTime(s)
+		if err != nil {
+			return nil, err
+		}
+		day := truncateDay(*t.t)
+		return Time{&day}, nil
+	})
+	for _, t := range []CellType{dateType, timeType("time", func(s string) (Cell, error) {
+		return ParseTime(s)
+	})} {
+		if err := registry.register(t); err != nil {
+			panic(err)
+		}
+	}
+
+	// Times with a custom layout can be requested as time(layout)
+	registry.registerParametric("time", func(layout string) (CellType, error) {
+		if layout == "" {
+			return CellType{}, errors.New("Empty time layout")
+		}
+		return timeType("time", func(s string) (Cell, error) {
+			t, err := time.Parse(layout, s)
+			if err != nil {
+				return nil, err
+			}
+			return Time{&t}, nil
+		}), nil
+	})
+}
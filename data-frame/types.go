@@ -0,0 +1,377 @@
+package df
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// CellType describes a type of Cell that can be stored on a DataFrame column.
+// Builtin types are registered with the names "string", "int", "float",
+// "bool", "category", "int64", "uint64", "decimal", "date" and "time". The
+// scale of the decimals can be given as "decimal(scale)", otherwise it will be
+// inferred, and the layout of the times as "time(layout)".
+// Custom types can be made available to Parse, conditions, sorting, I/O and
+// printing by registering them with RegisterType.
+type CellType struct {
+	// Name is the identifier used to refer to the type, i.e. on Parse
+	Name string
+
+	// Empty is the NA element for this type. Its dynamic type identifies the
+	// columns of this type.
+	Empty Cell
+
+	// Parse converts a string into a Cell of this type
+	Parse func(s string) (Cell, error)
+
+	// Compare returns a negative number if a < b, zero if a == b and
+	// a positive number if a > b. It won't be called with NA elements.
+	Compare func(a, b Cell) int
+
+	// Format returns the string representation of a Cell of this type. It
+	// won't be called with NA elements. If nil, Cell.String() will be used.
+	Format func(c Cell) string
+
+	// convert transforms a whole set of cells into this type. If nil, the
+	// cells will be parsed one by one from their string representation.
+	convert func(cells Cells) Cells
+}
+
+// typeRegistry stores the registered CellTypes by name and by column type.
+// Parametric types are the ones that accept an argument on their name, like
+// "decimal(2)".
+type typeRegistry struct {
+	mu         sync.RWMutex
+	byName     map[string]CellType
+	colType    map[string]CellType
+	parametric map[string]func(arg string) (CellType, error)
+}
+
+var registry = typeRegistry{
+	byName:     map[string]CellType{},
+	colType:    map[string]CellType{},
+	parametric: map[string]func(arg string) (CellType, error){},
+}
+
+// RegisterType makes a custom Cell type available to the DataFrame
+// operations under the given name.
+func RegisterType(t CellType) error {
+	if t.Name == "" {
+		return errors.New("Can't register a type without name")
+	}
+	if t.Empty == nil || t.Parse == nil || t.Compare == nil {
+		return errors.New("Type " + t.Name + " needs an Empty element, a parser and a comparator")
+	}
+	return registry.register(t)
+}
+
+func (r *typeRegistry) register(t CellType, aliases ...string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	names := append([]string{t.Name}, aliases...)
+	for _, name := range names {
+		if _, ok := r.byName[name]; ok {
+			return errors.New("Type already registered: " + name)
+		}
+	}
+	for _, name := range names {
+		r.byName[name] = t
+	}
+	r.colType[reflect.TypeOf(t.Empty).String()] = t
+	return nil
+}
+
+func (r *typeRegistry) registerParametric(name string, f func(arg string) (CellType, error)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.parametric[name] = f
+}
+
+// lookupType returns the registered CellType with the given name. Names of
+// the form "name(arg)" are resolved with the parametric types.
+func lookupType(name string) (CellType, bool) {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+	if t, ok := registry.byName[name]; ok {
+		return t, ok
+	}
+	idx := strings.Index(name, "(")
+	if idx <= 0 || !strings.HasSuffix(name, ")") {
+		return CellType{}, false
+	}
+	f, ok := registry.parametric[name[:idx]]
+	if !ok {
+		return CellType{}, false
+	}
+	t, err := f(strings.TrimSpace(name[idx+1 : len(name)-1]))
+	if err != nil {
+		return CellType{}, false
+	}
+	return t, true
+}
+
+// typeOf returns the registered CellType for the given column type
+func typeOf(colType string) (CellType, bool) {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+	t, ok := registry.colType[colType]
+	return t, ok
+}
+
+// parseCells converts the given cells to the given type
+func (t CellType) parseCells(cells Cells) Cells {
+	if t.convert != nil {
+		return t.convert(cells)
+	}
+	ret := make(Cells, 0, len(cells))
+	for _, v := range cells {
+		if v.IsNA() {
+			ret = append(ret, t.Empty.NA())
+			continue
+		}
+		c, err := t.Parse(v.String())
+		if err != nil || c == nil {
+			ret = append(ret, t.Empty.NA())
+			continue
+		}
+		ret = append(ret, c)
+	}
+	return ret
+}
+
+// inferredTypes are the types tried by InferTypes, in order
+var inferredTypes = []string{"int", "float", "bool", "time"}
+
+// InferTypes returns the types that the columns of strings can be parsed to,
+// which are the first of "int", "float", "bool" and "time" that can parse all
+// their elements, or "string" otherwise. Empty strings and "NA" are
+// considered missing values. Columns of other types keep their type. The
+// result can be given to Parse:
+//
+//	err := d.Parse(d.InferTypes())
+func (df DataFrame) InferTypes() T {
+	types := T{}
+	for k, col := range df.Columns {
+		if col.colType != "df.String" {
+			if t, ok := typeOf(col.colType); ok {
+				types[k] = t.Name
+			}
+			continue
+		}
+		types[k] = "string"
+		for _, name := range inferredTypes {
+			if t, ok := lookupType(name); ok && parsesAll(t, col.cells) {
+				types[k] = name
+				break
+			}
+		}
+	}
+	return types
+}
+
+// parsesAll returns true if all the non missing elements can be parsed with
+// the given type and there is at least one of them
+func parsesAll(t CellType, cells Cells) bool {
+	found := false
+	for _, c := range cells {
+		if c.IsNA() {
+			continue
+		}
+		s := c.String()
+		if s == "" || s == "NA" {
+			continue
+		}
+		if v, err := t.Parse(s); err != nil || v == nil {
+			return false
+		}
+		found = true
+	}
+	return found
+}
+
+// formatCellType returns the string representation of a cell of the given
+// column type, using the formatter of the registered type if it exists.
+func formatCellType(cell Cell, colType string) string {
+	if cell.IsNA() {
+		return "NA"
+	}
+	if t, ok := typeOf(colType); ok && t.Format != nil {
+		return t.Format(cell)
+	}
+	return cell.String()
+}
+
+// compareCells compares two non NA cells of the given column type
+func compareCells(a, b Cell, colType string) (int, error) {
+	t, ok := typeOf(colType)
+	if !ok {
+		return 0, errors.New("Can't compare elements of unregistered type: " + colType)
+	}
+	return t.Compare(a, b), nil
+}
+
+// literalComparer is implemented by the Cells that need the context of the
+// cell itself to compare it with a literal, like Categorical does with its
+// levels.
+type literalComparer interface {
+	compareLiteral(s string) (int, bool)
+	equalLiteral(s string) bool
+}
+
+// compareLiteral compares a cell of the given column type with the literal
+// value s. The second value returned is false if the comparison is not
+// possible.
+func compareLiteral(v Cell, colType string, s string) (int, bool) {
+	if v.IsNA() {
+		return 0, false
+	}
+	if lc, ok := v.(literalComparer); ok {
+		return lc.compareLiteral(s)
+	}
+	t, ok := typeOf(colType)
+	if !ok {
+		return 0, false
+	}
+	c, err := t.Parse(s)
+	if err != nil || c == nil || c.IsNA() {
+		return 0, false
+	}
+	return t.Compare(v, c), true
+}
+
+// equalLiteral reports whether a cell of the given column type is equal to
+// the literal value s. Unlike compareLiteral it doesn't need an order, so any
+// label can be checked against an unordered Categorical. The second value
+// returned is false if the check is not possible.
+func equalLiteral(v Cell, colType string, s string) (bool, bool) {
+	if v.IsNA() {
+		return false, false
+	}
+	if lc, ok := v.(literalComparer); ok {
+		return lc.equalLiteral(s), true
+	}
+	cmp, ok := compareLiteral(v, colType, s)
+	return ok && cmp == 0, ok
+}
+
+func compareInts(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+func compareFloats(a, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+func init() {
+	builtins := []struct {
+		t       CellType
+		aliases []string
+	}{
+		{CellType{
+			Name:  "string",
+			Empty: String{nil},
+			Parse: func(s string) (Cell, error) {
+				return String{&s}, nil
+			},
+			Compare: func(a, b Cell) int {
+				return strings.Compare(a.String(), b.String())
+			},
+			convert: func(cells Cells) Cells {
+				// Strings takes NA elements as the "NA" string
+				ret := Strings(cells)
+				for i, c := range cells {
+					if c.IsNA() {
+						ret[i] = String{nil}
+					}
+				}
+				return ret
+			},
+		}, nil},
+		{CellType{
+			Name:  "int",
+			Empty: Int{nil},
+			Parse: func(s string) (Cell, error) {
+				i, err := strconv.Atoi(s)
+				if err != nil {
+					return nil, err
+				}
+				return Int{&i}, nil
+			},
+			Compare: func(a, b Cell) int {
+				return compareInts(*a.(Int).i, *b.(Int).i)
+			},
+			convert: func(cells Cells) Cells { return Ints(cells) },
+		}, nil},
+		{CellType{
+			Name:  "float",
+			Empty: Float{nil},
+			Parse: func(s string) (Cell, error) {
+				f, err := strconv.ParseFloat(s, 64)
+				if err != nil {
+					return nil, err
+				}
+				return Float{&f}, nil
+			},
+			Compare: func(a, b Cell) int {
+				return compareFloats(*a.(Float).f, *b.(Float).f)
+			},
+			convert: func(cells Cells) Cells { return Floats(cells) },
+		}, []string{"float64"}},
+		{CellType{
+			Name:  "bool",
+			Empty: Bool{nil},
+			Parse: func(s string) (Cell, error) {
+				b, err := strconv.ParseBool(s)
+				if err != nil {
+					return nil, err
+				}
+				return Bool{&b}, nil
+			},
+			Compare: func(a, b Cell) int {
+				ab, bb := *a.(Bool).b, *b.(Bool).b
+				switch {
+				case !ab && bb:
+					return -1
+				case ab && !bb:
+					return 1
+				}
+				return 0
+			},
+			convert: func(cells Cells) Cells { return Bools(cells) },
+		}, nil},
+		{CellType{
+			Name:  "category",
+			Empty: Categorical{},
+			Parse: func(s string) (Cell, error) {
+				return Categoricals(s)[0], nil
+			},
+			Compare: func(a, b Cell) int {
+				ca, cb := a.(Categorical), b.(Categorical)
+				if ca.Ordered() && ca.lvl == cb.lvl {
+					return compareInts(*ca.c, *cb.c)
+				}
+				return strings.Compare(ca.String(), cb.String())
+			},
+			convert: func(cells Cells) Cells { return Categoricals(cells) },
+		}, nil},
+	}
+	for _, v := range builtins {
+		if err := registry.register(v.t, v.aliases...); err != nil {
+			panic(err)
+		}
+	}
+}
@@ -0,0 +1,264 @@
+package df
+
+import (
+	"crypto/md5"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// version is a custom Cell type used to test the type registry. It stores
+// a major.minor version number.
+type version struct {
+	v *[2]int
+}
+
+func (v version) String() string {
+	if v.v == nil {
+		return "NA"
+	}
+	return fmt.Sprint(v.v[0], ".", v.v[1])
+}
+func (v version) Int() (*int, error)       { return nil, errors.New("Could't convert to int") }
+func (v version) Float() (*float64, error) { return nil, errors.New("Could't convert to float64") }
+func (v version) Bool() (*bool, error)     { return nil, errors.New("Could't convert to bool") }
+func (v version) NA() Cell                 { return version{nil} }
+func (v version) IsNA() bool               { return v.v == nil }
+func (v version) Checksum() [16]byte       { return md5.Sum([]byte(v.String() + "version")) }
+func (v version) Copy() Cell {
+	if v.v == nil {
+		return version{nil}
+	}
+	j := *v.v
+	return version{&j}
+}
+
+func parseVersion(s string) (Cell, error) {
+	parts := strings.Split(s, ".")
+	if len(parts) != 2 {
+		return nil, errors.New("Invalid version: " + s)
+	}
+	major, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return nil, err
+	}
+	minor, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return nil, err
+	}
+	return version{&[2]int{major, minor}}, nil
+}
+
+func init() {
+	err := RegisterType(CellType{
+		Name:  "version",
+		Empty: version{},
+		Parse: parseVersion,
+		Compare: func(a, b Cell) int {
+			va, vb := a.(version).v, b.(version).v
+			if c := compareInts(va[0], vb[0]); c != 0 {
+				return c
+			}
+			return compareInts(va[1], vb[1])
+		},
+		Format: func(c Cell) string {
+			return "v" + c.String()
+		},
+	})
+	if err != nil {
+		panic(err)
+	}
+}
+
+func TestRegisterType(t *testing.T) {
+	err := RegisterType(CellType{Name: "version", Empty: version{}, Parse: parseVersion})
+	if err == nil {
+		t.Error("Registering an incomplete type should throw an error")
+	}
+	err = RegisterType(CellType{
+		Name:    "int",
+		Empty:   version{},
+		Parse:   parseVersion,
+		Compare: func(a, b Cell) int { return 0 },
+	})
+	if err == nil {
+		t.Error("Registering a duplicated type should throw an error")
+	}
+}
+
+func TestDataFrame_ParseRegisteredType(t *testing.T) {
+	data := [][]string{
+		[]string{"Name", "Version"},
+		[]string{"a", "1.10"},
+		[]string{"b", "1.2"},
+		[]string{"c", "invalid"},
+		[]string{"d", "2.0"},
+	}
+	d := DataFrame{}
+	err := d.LoadAndParse(data, T{"Version": "version"})
+	if err != nil {
+		t.Error(err)
+	}
+	if d.Columns["Version"].colType != "df.version" {
+		t.Error("Incorrect type parsing:", d.Columns["Version"].colType)
+	}
+
+	// Formatter is used for records and printing
+	expected := "[[Name Version] [a v1.10] [b v1.2] [c NA] [d v2.0]]"
+	received := fmt.Sprint(d.SaveRecords())
+	if expected != received {
+		t.Error(
+			"Records not being formatted properly\n",
+			"Expected:\n",
+			expected, "\n",
+			"Received:\n",
+			received,
+		)
+	}
+	expected = "   Name  Version  \n\n0: a     v1.10    \n1: b     v1.2     \n2: c     NA       \n3: d     v2.0     \n"
+	received = d.String()
+	if expected != received {
+		t.Error(
+			"DataFrame not being printed properly\n",
+			"Expected:\n",
+			expected, "\n",
+			"Received:\n",
+			received,
+		)
+	}
+
+	// Comparator is used on conditions
+	dc, err := d.ConditionRows(NewCondition([]string{"Version > 1.9"}))
+	if err != nil {
+		t.Error(err)
+	}
+	expected = "[[Name Version] [a v1.10] [d v2.0]]"
+	received = fmt.Sprint(dc.SaveRecords())
+	if expected != received {
+		t.Error(
+			"Conditions not using the registered comparator\n",
+			"Expected:\n",
+			expected, "\n",
+			"Received:\n",
+			received,
+		)
+	}
+}
+
+func TestDataFrame_InferTypes(t *testing.T) {
+	data := [][]string{
+		[]string{"A", "B", "C", "D", "E", "F", "G"},
+		[]string{"1", "1.5", "true", "2016-01-02", "a", "NA", "1.0"},
+		[]string{"", "2", "F", "2016-01-03", "1", "", "v"},
+		[]string{"-3", "NA", "NA", "", "NA", "NA", "2.3"},
+	}
+	d := DataFrame{}
+	if err := d.LoadData(data); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.Parse(T{"G": "version"}); err != nil {
+		t.Fatal(err)
+	}
+	types := d.InferTypes()
+	expected := "map[A:int B:float C:bool D:time E:string F:string G:version]"
+	received := fmt.Sprint(types)
+	if expected != received {
+		t.Error(
+			"Types not being inferred properly\n",
+			"Expected:\n",
+			expected, "\n",
+			"Received:\n",
+			received,
+		)
+	}
+	if err := d.Parse(types); err != nil {
+		t.Fatal(err)
+	}
+	expected = "[df.Int df.Float df.Bool df.Time df.String df.String df.version]"
+	received = fmt.Sprint(d.Types())
+	if expected != received {
+		t.Error(
+			"Inferred types not being parsed properly\n",
+			"Expected:\n",
+			expected, "\n",
+			"Received:\n",
+			received,
+		)
+	}
+}
+
+func TestConditions(t *testing.T) {
+	var tests = []struct {
+		cond     []string
+		expected string
+	}{
+		{[]string{"Age > 30"}, "[[Age Name] [50 a] [32 b]]"},
+		{[]string{"Age >= 32"}, "[[Age Name] [50 a] [32 b]]"},
+		{[]string{"Age < 32"}, "[[Age Name] [17 c]]"},
+		{[]string{"Age == 17"}, "[[Age Name] [17 c]]"},
+		{[]string{"Age > 20", "Age <= 32"}, "[[Age Name] [32 b]]"},
+		{[]string{"Name > a"}, "[[Age Name] [32 b] [17 c] [NA d]]"},
+	}
+	d, _ := New(
+		C{"Age", Ints(50, 32, 17, nil)},
+		C{"Name", Strings("a", "b", "c", "d")},
+	)
+	for k, v := range tests {
+		dc, err := d.ConditionRows(NewCondition(v.cond))
+		if err != nil {
+			t.Error("Test", k, ":", err)
+			continue
+		}
+		received := fmt.Sprint(dc.SaveRecords())
+		if v.expected != received {
+			t.Error(
+				"Test", k, "\n",
+				"Expected:\n",
+				v.expected, "\n",
+				"Received:\n",
+				received,
+			)
+		}
+	}
+}
+
+func TestParseConditions(t *testing.T) {
+	var tests = []struct {
+		cond     []string
+		expected string
+	}{
+		{[]string{"Age > 30", "Name == b"}, "[[Age Name] [32 b]]"},
+		{nil, "[[Age Name] [50 a] [32 b] [17 c] [NA d]]"},
+		{[]string{"Age"}, `Invalid condition "Age"`},
+		{[]string{"Age != 3"}, `Invalid operation "!=" in condition "Age != 3"`},
+	}
+	d, _ := New(
+		C{"Age", Ints(50, 32, 17, nil)},
+		C{"Name", Strings("a", "b", "c", "d")},
+	)
+	for k, v := range tests {
+		var received string
+		conds, err := ParseConditions(v.cond)
+		if err != nil {
+			received = err.Error()
+		} else {
+			dc, err := d.ConditionRows(conds)
+			if err != nil {
+				t.Error("Test", k, ":", err)
+				continue
+			}
+			received = fmt.Sprint(dc.SaveRecords())
+		}
+		if v.expected != received {
+			t.Error(
+				"Test", k, "\n",
+				"Expected:\n",
+				v.expected, "\n",
+				"Received:\n",
+				received,
+			)
+		}
+	}
+}
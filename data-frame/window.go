@@ -0,0 +1,433 @@
+package df
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// Aggregation is a function that summarizes a set of numeric elements
+type Aggregation int
+
+// Supported aggregations
+const (
+	AggSum Aggregation = iota
+	AggMean
+	AggMin
+	AggMax
+	AggStd
+	AggCount
+	AggFirst
+	AggLast
+)
+
+func (a Aggregation) String() string {
+	switch a {
+	case AggSum:
+		return "sum"
+	case AggMean:
+		return "mean"
+	case AggMin:
+		return "min"
+	case AggMax:
+		return "max"
+	case AggStd:
+		return "std"
+	case AggCount:
+		return "count"
+	case AggFirst:
+		return "first"
+	case AggLast:
+		return "last"
+	}
+	return "unknown"
+}
+
+// aggregate applies the aggregation to the given non NA values
+func (a Aggregation) aggregate(values []float64) float64 {
+	switch a {
+	case AggCount:
+		return float64(len(values))
+	case AggSum:
+		sum := 0.0
+		for _, v := range values {
+			sum += v
+		}
+		return sum
+	case AggMean:
+		return mean(values)
+	case AggStd:
+		return math.Sqrt(variance(values))
+	case AggFirst, AggLast:
+		if len(values) == 0 {
+			return math.NaN()
+		}
+		if a == AggFirst {
+			return values[0]
+		}
+		return values[len(values)-1]
+	case AggMin, AggMax:
+		if len(values) == 0 {
+			return math.NaN()
+		}
+		r := values[0]
+		for _, v := range values[1:] {
+			if (a == AggMin && v < r) || (a == AggMax && v > r) {
+				r = v
+			}
+		}
+		return r
+	}
+	return math.NaN()
+}
+
+// WindowOptions configures the window functions. Name is the column where the
+// result is stored, by default the name of the column followed by the name of
+// the function. If GroupBy is given the function is computed independently for
+// the rows of each group, in their order on the DataFrame. MinPeriods is the
+// minimum number of non NA elements needed on a window to compute its
+// aggregation, by default the size of the window for Rolling and 1 for
+// Expanding. Desc ranks the elements in descending order.
+type WindowOptions struct {
+	Name       string
+	GroupBy    []string
+	MinPeriods int
+	Desc       bool
+}
+
+func windowOptions(opts []WindowOptions) WindowOptions {
+	if len(opts) == 0 {
+		return WindowOptions{}
+	}
+	return opts[0]
+}
+
+// groupRows returns the indexes of the rows of each group of rows with the
+// same elements on the given columns, in order of first appearance. If no
+// columns are given all the rows belong to the same group.
+func (df DataFrame) groupRows(colnames []string) ([][]int, error) {
+	for _, k := range colnames {
+		if _, err := df.col(k); err != nil {
+			return nil, err
+		}
+	}
+	index := map[string]int{}
+	groups := [][]int{}
+	for i := 0; i < df.nRows; i++ {
+		key := rowChecksum(df, colnames, i)
+		g, ok := index[key]
+		if !ok {
+			g = len(groups)
+			index[key] = g
+			groups = append(groups, []int{})
+		}
+		groups[g] = append(groups[g], i)
+	}
+	return groups, nil
+}
+
+// windowColumn applies f to the elements of each group of the column colname
+// and stores the results on a new column. f receives the elements of the group
+// in order and must return one element for each of them.
+func (df DataFrame) windowColumn(colname, suffix string, opts WindowOptions, f func(cells Cells) (Cells, error)) (*DataFrame, error) {
+	col, err := df.col(colname)
+	if err != nil {
+		return nil, err
+	}
+	groups, err := df.groupRows(opts.GroupBy)
+	if err != nil {
+		return nil, err
+	}
+	name := opts.Name
+	if name == "" {
+		name = colname + "_" + suffix
+	}
+	if df.nRows == 0 {
+		if col.empty == nil {
+			return df.setColumn(column{colName: name}), nil
+		}
+		// Applying f to an NA element gives the type of the result
+		r, err := f(Cells{col.empty})
+		if err != nil {
+			return nil, err
+		}
+		return df.withEmptyColumn(name, r[0].NA())
+	}
+	result := make(Cells, df.nRows)
+	for _, rows := range groups {
+		cells := make(Cells, 0, len(rows))
+		for _, i := range rows {
+			cells = append(cells, col.cells[i])
+		}
+		r, err := f(cells)
+		if err != nil {
+			return nil, err
+		}
+		for j, i := range rows {
+			result[i] = r[j]
+		}
+	}
+	return df.withColumn(name, result)
+}
+
+// numericWindow computes an aggregation over the windows of a numeric column.
+// window returns the first position of the window that ends on each element.
+func (df DataFrame) numericWindow(colname, suffix string, agg Aggregation, minPeriods int, opts WindowOptions, window func(i int) int) (*DataFrame, error) {
+	if col, err := df.col(colname); err == nil && numericKind(col.empty) == kindNone {
+		return nil, fmt.Errorf("Can't compute window functions over the non numeric column %s", colname)
+	}
+	return df.windowColumn(colname, suffix, opts, func(cells Cells) (Cells, error) {
+		ret := make(Cells, 0, len(cells))
+		for i := range cells {
+			values, err := numericValues(cells[window(i) : i+1])
+			if err != nil {
+				return nil, err
+			}
+			if len(values) < minPeriods {
+				if agg == AggCount {
+					ret = append(ret, Int{nil})
+				} else {
+					ret = append(ret, Float{nil})
+				}
+				continue
+			}
+			r := agg.aggregate(values)
+			switch {
+			case agg == AggCount:
+				n := int(r)
+				ret = append(ret, Int{&n})
+			case math.IsNaN(r):
+				ret = append(ret, Float{nil})
+			default:
+				ret = append(ret, Float{&r})
+			}
+		}
+		return ret, nil
+	})
+}
+
+// Rolling adds a column with the aggregation of the elements of the numeric
+// column colname over a moving window of the given size that ends on each
+// row.
+func (df DataFrame) Rolling(colname string, size int, agg Aggregation, opts ...WindowOptions) (*DataFrame, error) {
+	if size <= 0 {
+		return nil, errors.New("The size of the window must be positive")
+	}
+	opt := windowOptions(opts)
+	minPeriods := opt.MinPeriods
+	if minPeriods <= 0 {
+		minPeriods = size
+	}
+	return df.numericWindow(colname, "rolling_"+agg.String(), agg, minPeriods, opt, func(i int) int {
+		if i-size+1 < 0 {
+			return 0
+		}
+		return i - size + 1
+	})
+}
+
+// Expanding adds a column with the aggregation of the elements of the numeric
+// column colname from the first row up to each row.
+func (df DataFrame) Expanding(colname string, agg Aggregation, opts ...WindowOptions) (*DataFrame, error) {
+	opt := windowOptions(opts)
+	minPeriods := opt.MinPeriods
+	if minPeriods <= 0 {
+		minPeriods = 1
+	}
+	return df.numericWindow(colname, "expanding_"+agg.String(), agg, minPeriods, opt, func(int) int {
+		return 0
+	})
+}
+
+// cumulative accumulates the non NA elements of a column with f. NA elements
+// are kept as NA and don't reset the accumulation.
+func (df DataFrame) cumulative(colname, suffix string, opts []WindowOptions, f func(acc, c Cell) (Cell, error)) (*DataFrame, error) {
+	return df.windowColumn(colname, suffix, windowOptions(opts), func(cells Cells) (Cells, error) {
+		ret := make(Cells, 0, len(cells))
+		var acc Cell
+		for _, c := range cells {
+			if c.IsNA() {
+				ret = append(ret, c)
+				continue
+			}
+			if acc == nil {
+				acc = c
+			} else {
+				var err error
+				if acc, err = f(acc, c); err != nil {
+					return nil, err
+				}
+			}
+			ret = append(ret, acc)
+		}
+		return ret, nil
+	})
+}
+
+// CumSum adds a column with the cumulative sum of the numeric column colname
+func (df DataFrame) CumSum(colname string, opts ...WindowOptions) (*DataFrame, error) {
+	return df.cumulative(colname, "cumsum", opts, func(acc, c Cell) (Cell, error) {
+		return arith(acc, c, Add, ArithOptions{})
+	})
+}
+
+// CumProd adds a column with the cumulative product of the numeric column
+// colname
+func (df DataFrame) CumProd(colname string, opts ...WindowOptions) (*DataFrame, error) {
+	return df.cumulative(colname, "cumprod", opts, func(acc, c Cell) (Cell, error) {
+		return arith(acc, c, Mul, ArithOptions{})
+	})
+}
+
+// CumMax adds a column with the cumulative maximum of the column colname. Any
+// type with a registered comparator can be used.
+func (df DataFrame) CumMax(colname string, opts ...WindowOptions) (*DataFrame, error) {
+	col, err := df.col(colname)
+	if err != nil {
+		return nil, err
+	}
+	return df.cumulative(colname, "cummax", opts, func(acc, c Cell) (Cell, error) {
+		cmp, err := compareCells(c, acc, col.colType)
+		if err != nil || cmp <= 0 {
+			return acc, err
+		}
+		return c, nil
+	})
+}
+
+// shifted returns the elements of a group moved the given number of periods.
+// Positive periods move the elements forward (lag) and negative ones backward
+// (lead). The positions left empty are NA.
+func shifted(cells Cells, periods int) Cells {
+	ret := make(Cells, len(cells))
+	for i := range cells {
+		j := i - periods
+		if j < 0 || j >= len(cells) {
+			ret[i] = cells[i].NA()
+		} else {
+			ret[i] = cells[j]
+		}
+	}
+	return ret
+}
+
+// Shift adds a column with the elements of the column colname moved the given
+// number of periods. Positive periods take the previous elements (lag) and
+// negative ones the following elements (lead).
+func (df DataFrame) Shift(colname string, periods int, opts ...WindowOptions) (*DataFrame, error) {
+	return df.windowColumn(colname, "shift", windowOptions(opts), func(cells Cells) (Cells, error) {
+		return shifted(cells, periods), nil
+	})
+}
+
+// Diff adds a column with the difference between each element of the numeric
+// column colname and the element the given number of periods before it.
+func (df DataFrame) Diff(colname string, periods int, opts ...WindowOptions) (*DataFrame, error) {
+	return df.windowColumn(colname, "diff", windowOptions(opts), func(cells Cells) (Cells, error) {
+		return arithCells(cells, shifted(cells, periods), Sub, ArithOptions{})
+	})
+}
+
+// PctChange adds a Float column with the relative change between each element
+// of the numeric column colname and the element the given number of periods
+// before it.
+func (df DataFrame) PctChange(colname string, periods int, opts ...WindowOptions) (*DataFrame, error) {
+	return df.windowColumn(colname, "pct_change", windowOptions(opts), func(cells Cells) (Cells, error) {
+		prev := shifted(cells, periods)
+		ret := make(Cells, 0, len(cells))
+		for i, c := range cells {
+			if c.IsNA() || prev[i].IsNA() {
+				ret = append(ret, Float{nil})
+				continue
+			}
+			if numericKind(c) == kindNone {
+				return nil, errors.New("Can't compute the change of non numeric elements")
+			}
+			a, _ := c.Float()
+			b, _ := prev[i].Float()
+			if *b == 0 {
+				ret = append(ret, Float{nil})
+				continue
+			}
+			r := *a / *b - 1
+			ret = append(ret, Float{&r})
+		}
+		return ret, nil
+	})
+}
+
+// RankMethod defines how Rank assigns ranks to equal elements
+type RankMethod int
+
+const (
+	// RankMin gives equal elements the lowest rank of the group
+	RankMin RankMethod = iota
+	// RankMax gives equal elements the highest rank of the group
+	RankMax
+	// RankDense is like RankMin but ranks always increase by one between
+	// groups of equal elements
+	RankDense
+	// RankFirst ranks equal elements in order of appearance
+	RankFirst
+)
+
+// Rank adds an Int column with the rank of the elements of the column colname,
+// starting at 1. Elements are compared with the comparator of the column type
+// and NA elements get an NA rank.
+func (df DataFrame) Rank(colname string, method RankMethod, opts ...WindowOptions) (*DataFrame, error) {
+	col, err := df.col(colname)
+	if err != nil {
+		return nil, err
+	}
+	t, ok := typeOf(col.colType)
+	if !ok {
+		return nil, errors.New("Can't rank elements of unregistered type: " + col.colType)
+	}
+	opt := windowOptions(opts)
+	return df.windowColumn(colname, "rank", opt, func(cells Cells) (Cells, error) {
+		order := []int{}
+		for i, c := range cells {
+			if !c.IsNA() {
+				order = append(order, i)
+			}
+		}
+		compare := func(i, j int) int {
+			cmp := t.Compare(cells[order[i]], cells[order[j]])
+			if opt.Desc {
+				return -cmp
+			}
+			return cmp
+		}
+		sort.SliceStable(order, func(i, j int) bool { return compare(i, j) < 0 })
+
+		ret := make(Cells, len(cells))
+		for i := range ret {
+			ret[i] = Int{nil}
+		}
+		dense := 0
+		for start := 0; start < len(order); {
+			end := start + 1
+			for end < len(order) && compare(start, end) == 0 {
+				end++
+			}
+			dense++
+			for k := start; k < end; k++ {
+				var r int
+				switch method {
+				case RankMin:
+					r = start + 1
+				case RankMax:
+					r = end
+				case RankDense:
+					r = dense
+				default:
+					r = k + 1
+				}
+				ret[order[k]] = Int{&r}
+			}
+			start = end
+		}
+		return ret, nil
+	})
+}
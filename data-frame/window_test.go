@@ -0,0 +1,119 @@
+package df
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestDataFrame_Rolling(t *testing.T) {
+	d, _ := New(
+		C{"G", Strings("a", "a", "b", "a", "b", "a")},
+		C{"A", Ints(1, 2, 10, nil, 20, 4)},
+	)
+	var tests = []struct {
+		f        func(d DataFrame) (*DataFrame, error)
+		col      string
+		expected string
+	}{
+		{func(d DataFrame) (*DataFrame, error) { return d.Rolling("A", 2, AggSum) }, "A_rolling_sum", "[NA 3 12 NA NA 24]"},
+		{func(d DataFrame) (*DataFrame, error) { return d.Rolling("A", 2, AggMean, WindowOptions{MinPeriods: 1}) }, "A_rolling_mean", "[1 1.5 6 10 20 12]"},
+		{func(d DataFrame) (*DataFrame, error) {
+			return d.Rolling("A", 3, AggCount, WindowOptions{MinPeriods: 1})
+		}, "A_rolling_count", "[1 2 3 2 2 2]"},
+		{func(d DataFrame) (*DataFrame, error) {
+			return d.Rolling("A", 2, AggMax, WindowOptions{GroupBy: []string{"G"}, Name: "M"})
+		}, "M", "[NA 2 NA NA 20 NA]"},
+		{func(d DataFrame) (*DataFrame, error) { return d.Expanding("A", AggMin) }, "A_expanding_min", "[1 1 1 1 1 1]"},
+		{func(d DataFrame) (*DataFrame, error) {
+			return d.Expanding("A", AggStd, WindowOptions{GroupBy: []string{"G"}})
+		}, "A_expanding_std", "[NA 0.7071067811865476 NA 0.7071067811865476 7.0710678118654755 1.5275252316519465]"},
+		{func(d DataFrame) (*DataFrame, error) { return d.CumSum("A") }, "A_cumsum", "[1 3 13 NA 33 37]"},
+		{func(d DataFrame) (*DataFrame, error) { return d.CumProd("A", WindowOptions{GroupBy: []string{"G"}}) }, "A_cumprod", "[1 2 10 NA 200 8]"},
+		{func(d DataFrame) (*DataFrame, error) { return d.CumMax("G") }, "G_cummax", "[a a b b b b]"},
+		{func(d DataFrame) (*DataFrame, error) { return d.Shift("A", 1) }, "A_shift", "[NA 1 2 10 NA 20]"},
+		{func(d DataFrame) (*DataFrame, error) { return d.Shift("A", -1, WindowOptions{GroupBy: []string{"G"}}) }, "A_shift", "[2 NA 20 4 NA NA]"},
+		{func(d DataFrame) (*DataFrame, error) { return d.Diff("A", 1, WindowOptions{GroupBy: []string{"G"}}) }, "A_diff", "[NA 1 NA NA 10 NA]"},
+		{func(d DataFrame) (*DataFrame, error) { return d.PctChange("A", 1) }, "A_pct_change", "[NA 1 4 NA NA -0.8]"},
+	}
+	for k, v := range tests {
+		dd, err := v.f(*d)
+		if err != nil {
+			t.Error("Test", k, ":", err)
+			continue
+		}
+		received := fmt.Sprint(dd.Columns[v.col].cells)
+		if v.expected != received {
+			t.Error(
+				"Test", k, "\n",
+				"Expected:\n",
+				v.expected, "\n",
+				"Received:\n",
+				received,
+			)
+		}
+
+		// Without rows the new column gets the type it has with them
+		de, err := v.f(*d.Empty())
+		if err != nil {
+			t.Error("Test", k, "without rows:", err)
+		} else if de.NRows() != 0 || de.Columns[v.col].colType != dd.Columns[v.col].colType {
+			t.Error("Test", k, "without rows: expected an empty", dd.Columns[v.col].colType, "column, received", de.NRows(), "rows of type", de.Columns[v.col].colType)
+		}
+	}
+	if d.NCols() != 2 {
+		t.Error("Original DataFrame was modified:", d.Names())
+	}
+
+	if _, err := d.Rolling("G", 2, AggSum); err == nil {
+		t.Error("Rolling over non numeric columns should throw an error")
+	}
+	if _, err := d.Rolling("A", 0, AggSum); err == nil {
+		t.Error("Empty windows should throw an error")
+	}
+	if _, err := d.CumSum("A", WindowOptions{GroupBy: []string{"X"}}); err == nil {
+		t.Error("Unknown group columns should throw an error")
+	}
+}
+
+func TestDataFrame_Rank(t *testing.T) {
+	d, _ := New(
+		C{"G", Strings("a", "a", "b", "a", "b", "a")},
+		C{"A", Floats(3, 1, 2, 3, nil, 5)},
+	)
+	var tests = []struct {
+		method   RankMethod
+		opts     WindowOptions
+		expected string
+	}{
+		{RankMin, WindowOptions{}, "[3 1 2 3 NA 5]"},
+		{RankMax, WindowOptions{}, "[4 1 2 4 NA 5]"},
+		{RankDense, WindowOptions{}, "[3 1 2 3 NA 4]"},
+		{RankFirst, WindowOptions{}, "[3 1 2 4 NA 5]"},
+		{RankFirst, WindowOptions{Desc: true}, "[2 5 4 3 NA 1]"},
+		{RankDense, WindowOptions{GroupBy: []string{"G"}}, "[2 1 1 2 NA 3]"},
+	}
+	for k, v := range tests {
+		dd, err := d.Rank("A", v.method, v.opts)
+		if err != nil {
+			t.Error("Test", k, ":", err)
+			continue
+		}
+		received := fmt.Sprint(dd.Columns["A_rank"].cells)
+		if v.expected != received {
+			t.Error(
+				"Test", k, "\n",
+				"Expected:\n",
+				v.expected, "\n",
+				"Received:\n",
+				received,
+			)
+		}
+	}
+
+	dd, err := d.Empty().Rank("A", RankMin)
+	if err != nil {
+		t.Error(err)
+	} else if dd.NRows() != 0 || dd.Columns["A_rank"].colType != "df.Int" {
+		t.Error("Expected an empty df.Int column, received", dd.NRows(), "rows of type", dd.Columns["A_rank"].colType)
+	}
+}